@@ -0,0 +1,27 @@
+-- +migrate Up
+CREATE TABLE balances (
+    address CHAR(42) PRIMARY KEY NOT NULL,
+    balance NUMERIC NOT NULL DEFAULT 0,
+    updated_block BIGINT NOT NULL
+);
+
+CREATE INDEX balances_balance_index ON balances (balance DESC);
+
+CREATE TABLE balance_changes (
+    id BIGSERIAL PRIMARY KEY NOT NULL,
+    address CHAR(42) NOT NULL,
+    delta NUMERIC NOT NULL,
+    block_number BIGINT NOT NULL,
+    log_index INTEGER NOT NULL
+);
+
+CREATE INDEX balance_changes_block_number_index ON balance_changes (block_number);
+CREATE INDEX balance_changes_address_block_index ON balance_changes (address, block_number);
+
+-- +migrate Down
+DROP INDEX IF EXISTS balance_changes_address_block_index;
+DROP INDEX IF EXISTS balance_changes_block_number_index;
+DROP INDEX IF EXISTS balances_balance_index;
+
+DROP TABLE IF EXISTS balance_changes;
+DROP TABLE IF EXISTS balances;
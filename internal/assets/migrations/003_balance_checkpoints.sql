@@ -0,0 +1,14 @@
+-- +migrate Up
+CREATE TABLE balance_checkpoints (
+    address CHAR(42) NOT NULL,
+    block_number BIGINT NOT NULL,
+    balance NUMERIC NOT NULL,
+    PRIMARY KEY (address, block_number)
+);
+
+CREATE INDEX balance_checkpoints_block_number_index ON balance_checkpoints (block_number);
+
+-- +migrate Down
+DROP INDEX IF EXISTS balance_checkpoints_block_number_index;
+
+DROP TABLE IF EXISTS balance_checkpoints;
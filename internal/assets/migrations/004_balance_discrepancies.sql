@@ -0,0 +1,16 @@
+-- +migrate Up
+CREATE TABLE balance_discrepancies (
+    id BIGSERIAL PRIMARY KEY NOT NULL,
+    address CHAR(42) NOT NULL,
+    block_number BIGINT NOT NULL,
+    indexed_balance NUMERIC NOT NULL,
+    onchain_balance NUMERIC NOT NULL,
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT (NOW() AT TIME ZONE 'utc')
+);
+
+CREATE INDEX balance_discrepancies_address_index ON balance_discrepancies (address);
+
+-- +migrate Down
+DROP INDEX IF EXISTS balance_discrepancies_address_index;
+
+DROP TABLE IF EXISTS balance_discrepancies;
@@ -0,0 +1,29 @@
+-- +migrate Up
+CREATE TABLE transfer_stats (
+    granularity VARCHAR(8) NOT NULL,
+    bucket TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    volume NUMERIC NOT NULL DEFAULT 0,
+    transfer_count BIGINT NOT NULL DEFAULT 0,
+    unique_senders BIGINT NOT NULL DEFAULT 0,
+    unique_receivers BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (granularity, bucket)
+);
+
+-- Participants of every bucket, needed to keep unique counts incremental.
+-- first_block is the block the address first appeared in the bucket.
+CREATE TABLE transfer_stats_participants (
+    granularity VARCHAR(8) NOT NULL,
+    bucket TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    address CHAR(42) NOT NULL,
+    role VARCHAR(8) NOT NULL,
+    first_block BIGINT NOT NULL,
+    PRIMARY KEY (granularity, bucket, address, role)
+);
+
+CREATE INDEX transfer_stats_participants_first_block_index ON transfer_stats_participants (first_block);
+
+-- +migrate Down
+DROP INDEX IF EXISTS transfer_stats_participants_first_block_index;
+
+DROP TABLE IF EXISTS transfer_stats_participants;
+DROP TABLE IF EXISTS transfer_stats;
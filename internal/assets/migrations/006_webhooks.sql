@@ -0,0 +1,35 @@
+-- +migrate Up
+CREATE TABLE webhooks (
+    id BIGSERIAL PRIMARY KEY NOT NULL,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    addresses TEXT[] NOT NULL DEFAULT '{}',
+    min_amount NUMERIC,
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT (NOW() AT TIME ZONE 'utc')
+);
+
+-- Transactional outbox of webhook calls, written together with the transfers
+CREATE TABLE webhook_deliveries (
+    id BIGSERIAL PRIMARY KEY NOT NULL,
+    webhook_id BIGINT NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+    event_type VARCHAR(16) NOT NULL,
+    block_number BIGINT NOT NULL,
+    log_index INTEGER NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT (NOW() AT TIME ZONE 'utc'),
+    last_error TEXT,
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT (NOW() AT TIME ZONE 'utc'),
+    delivered_at TIMESTAMP WITHOUT TIME ZONE
+);
+
+CREATE INDEX webhook_deliveries_due_index ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX webhook_deliveries_webhook_id_index ON webhook_deliveries (webhook_id);
+
+-- +migrate Down
+DROP INDEX IF EXISTS webhook_deliveries_webhook_id_index;
+DROP INDEX IF EXISTS webhook_deliveries_due_index;
+
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
@@ -0,0 +1,9 @@
+-- +migrate Up
+CREATE TABLE publisher_positions (
+    name VARCHAR(64) PRIMARY KEY NOT NULL,
+    block_number BIGINT NOT NULL,
+    log_index BIGINT NOT NULL
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS publisher_positions;
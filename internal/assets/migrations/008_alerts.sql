@@ -0,0 +1,42 @@
+-- +migrate Up
+CREATE TABLE alert_rules (
+    id BIGSERIAL PRIMARY KEY NOT NULL,
+    name TEXT NOT NULL,
+    kind VARCHAR(16) NOT NULL,
+    min_amount NUMERIC,
+    addresses TEXT[] NOT NULL DEFAULT '{}',
+    transfer_count INTEGER,
+    time_window BIGINT,
+    notifiers TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT (NOW() AT TIME ZONE 'utc')
+);
+
+-- Alerts are written together with the transfers that triggered them and
+-- notified afterwards
+CREATE TABLE alerts (
+    id BIGSERIAL PRIMARY KEY NOT NULL,
+    rule_id BIGINT NOT NULL REFERENCES alert_rules (id) ON DELETE CASCADE,
+    address CHAR(42) NOT NULL,
+    amount NUMERIC NOT NULL,
+    transfer_count INTEGER NOT NULL,
+    transaction_hash CHAR(66) NOT NULL,
+    block_number BIGINT NOT NULL,
+    log_index INTEGER NOT NULL,
+    timestamp TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    notified_at TIMESTAMP WITHOUT TIME ZONE,
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT (NOW() AT TIME ZONE 'utc')
+);
+
+CREATE INDEX alerts_pending_index ON alerts (id) WHERE notified_at IS NULL;
+CREATE INDEX alerts_rule_address_index ON alerts (rule_id, address, timestamp);
+CREATE INDEX alerts_block_number_index ON alerts (block_number);
+
+-- +migrate Down
+DROP INDEX IF EXISTS alerts_block_number_index;
+DROP INDEX IF EXISTS alerts_rule_address_index;
+DROP INDEX IF EXISTS alerts_pending_index;
+
+DROP TABLE IF EXISTS alerts;
+DROP TABLE IF EXISTS alert_rules;
@@ -0,0 +1,30 @@
+-- +migrate Up
+CREATE TABLE address_labels (
+    address CHAR(42) PRIMARY KEY NOT NULL,
+    label TEXT NOT NULL,
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT (NOW() AT TIME ZONE 'utc'),
+    updated_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT (NOW() AT TIME ZONE 'utc')
+);
+
+CREATE INDEX address_labels_label_index ON address_labels (label);
+
+CREATE TABLE watchlists (
+    id BIGSERIAL PRIMARY KEY NOT NULL,
+    name TEXT NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT (NOW() AT TIME ZONE 'utc')
+);
+
+CREATE TABLE watchlist_addresses (
+    watchlist_id BIGINT NOT NULL REFERENCES watchlists (id) ON DELETE CASCADE,
+    address CHAR(42) NOT NULL,
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT (NOW() AT TIME ZONE 'utc'),
+    PRIMARY KEY (watchlist_id, address)
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS watchlist_addresses;
+DROP TABLE IF EXISTS watchlists;
+
+DROP INDEX IF EXISTS address_labels_label_index;
+DROP TABLE IF EXISTS address_labels;
@@ -0,0 +1,42 @@
+-- +migrate Up
+ALTER TABLE usdt_transfers ADD COLUMN flags INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX usdt_transfers_flagged_index ON usdt_transfers (timestamp) WHERE flags <> 0;
+
+CREATE TABLE sanctions_lists (
+    name VARCHAR(64) PRIMARY KEY NOT NULL,
+    source TEXT NOT NULL,
+    address_count INTEGER NOT NULL,
+    imported_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT (NOW() AT TIME ZONE 'utc')
+);
+
+CREATE TABLE sanctioned_addresses (
+    list VARCHAR(64) NOT NULL REFERENCES sanctions_lists (name) ON DELETE CASCADE,
+    address CHAR(42) NOT NULL,
+    PRIMARY KEY (list, address)
+);
+
+CREATE INDEX sanctioned_addresses_address_index ON sanctioned_addresses (address);
+
+-- AddedBlackList / RemovedBlackList events of the USDT contract. The status of
+-- an address at a transfer is given by its latest event before the transfer.
+CREATE TABLE blacklist_events (
+    address CHAR(42) NOT NULL,
+    blacklisted BOOLEAN NOT NULL,
+    block_number BIGINT NOT NULL,
+    log_index INTEGER NOT NULL,
+    PRIMARY KEY (address, block_number, log_index)
+);
+
+CREATE INDEX blacklist_events_block_number_index ON blacklist_events (block_number);
+
+-- +migrate Down
+DROP INDEX IF EXISTS blacklist_events_block_number_index;
+DROP TABLE IF EXISTS blacklist_events;
+
+DROP INDEX IF EXISTS sanctioned_addresses_address_index;
+DROP TABLE IF EXISTS sanctioned_addresses;
+DROP TABLE IF EXISTS sanctions_lists;
+
+DROP INDEX IF EXISTS usdt_transfers_flagged_index;
+ALTER TABLE usdt_transfers DROP COLUMN IF EXISTS flags;
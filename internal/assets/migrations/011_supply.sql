@@ -0,0 +1,40 @@
+-- +migrate Up
+-- Mint and burn events, amount is signed: Issue adds to the supply,
+-- Redeem and DestroyedBlackFunds subtract from it.
+CREATE TABLE supply_changes (
+    block_number BIGINT NOT NULL,
+    log_index INTEGER NOT NULL,
+    kind VARCHAR(32) NOT NULL,
+    amount NUMERIC NOT NULL,
+    timestamp TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    PRIMARY KEY (block_number, log_index)
+);
+
+CREATE INDEX supply_changes_timestamp_index ON supply_changes (timestamp);
+
+-- Total supply after every block that changed it. The first row is the
+-- on-chain total supply right before the first processed block.
+CREATE TABLE supply_history (
+    block_number BIGINT PRIMARY KEY NOT NULL,
+    timestamp TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    delta NUMERIC NOT NULL,
+    total_supply NUMERIC NOT NULL
+);
+
+CREATE INDEX supply_history_timestamp_index ON supply_history (timestamp);
+
+CREATE TABLE supply_checkpoints (
+    block_number BIGINT PRIMARY KEY NOT NULL,
+    indexed_supply NUMERIC NOT NULL,
+    onchain_supply NUMERIC NOT NULL,
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT (NOW() AT TIME ZONE 'utc')
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS supply_checkpoints;
+
+DROP INDEX IF EXISTS supply_history_timestamp_index;
+DROP TABLE IF EXISTS supply_history;
+
+DROP INDEX IF EXISTS supply_changes_timestamp_index;
+DROP TABLE IF EXISTS supply_changes;
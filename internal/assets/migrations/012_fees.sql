@@ -0,0 +1,21 @@
+-- +migrate Up
+ALTER TABLE usdt_transfers ADD COLUMN fee NUMERIC NOT NULL DEFAULT 0;
+-- Set on fee transfers to the contract owner, log index of the principal
+-- transfer in the same block
+ALTER TABLE usdt_transfers ADD COLUMN principal_log_index INTEGER;
+
+-- basisPointsRate and maximumFee history, the row with log_index -1 holds
+-- the values read from the contract right before the first processed block
+CREATE TABLE fee_params (
+    block_number BIGINT NOT NULL,
+    log_index INTEGER NOT NULL,
+    basis_points NUMERIC NOT NULL,
+    maximum_fee NUMERIC NOT NULL,
+    PRIMARY KEY (block_number, log_index)
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS fee_params;
+
+ALTER TABLE usdt_transfers DROP COLUMN IF EXISTS principal_log_index;
+ALTER TABLE usdt_transfers DROP COLUMN IF EXISTS fee;
@@ -0,0 +1,17 @@
+-- +migrate Up
+-- Lineage of the USDT contract, every Deprecate event adds the upgraded
+-- contract watched starting from the deprecation block. log_index is the
+-- Deprecate event, it is NULL for the original contract and for upgrades
+-- found in the contract state when the listener starts.
+CREATE TABLE usdt_contracts (
+    address CHAR(42) PRIMARY KEY NOT NULL,
+    start_block BIGINT NOT NULL,
+    predecessor CHAR(42) REFERENCES usdt_contracts (address) ON DELETE CASCADE,
+    log_index INTEGER,
+    deprecated_block BIGINT
+);
+
+INSERT INTO usdt_contracts (address, start_block) VALUES ('0xdAC17F958D2ee523a2206206994597C13D831ec7', 0);
+
+-- +migrate Down
+DROP TABLE IF EXISTS usdt_contracts;
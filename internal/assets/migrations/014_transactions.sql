@@ -0,0 +1,21 @@
+-- +migrate Up
+-- Transactions of the indexed transfers, filled only when receipt
+-- enrichment is enabled
+CREATE TABLE transactions (
+    hash CHAR(66) PRIMARY KEY NOT NULL,
+    block_number BIGINT NOT NULL,
+    from_address CHAR(42) NOT NULL,
+    to_address CHAR(42),
+    method_selector CHAR(10),
+    gas_used BIGINT NOT NULL,
+    effective_gas_price NUMERIC NOT NULL,
+    gas_fee NUMERIC NOT NULL,
+    status SMALLINT NOT NULL
+);
+
+CREATE INDEX transactions_block_number_index ON transactions (block_number);
+
+-- +migrate Down
+DROP INDEX IF EXISTS transactions_block_number_index;
+
+DROP TABLE IF EXISTS transactions;
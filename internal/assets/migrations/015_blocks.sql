@@ -0,0 +1,18 @@
+-- +migrate Up
+CREATE TABLE blocks (
+    number BIGINT PRIMARY KEY NOT NULL,
+    hash CHAR(66) NOT NULL,
+    parent_hash CHAR(66) NOT NULL,
+    timestamp TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    transfer_count BIGINT NOT NULL,
+    volume NUMERIC NOT NULL,
+    unique_senders BIGINT NOT NULL,
+    unique_receivers BIGINT NOT NULL
+);
+
+CREATE INDEX blocks_timestamp_index ON blocks (timestamp);
+
+-- +migrate Down
+DROP INDEX IF EXISTS blocks_timestamp_index;
+
+DROP TABLE IF EXISTS blocks;
@@ -0,0 +1,36 @@
+-- +migrate Up
+ALTER TABLE last_processed_block ADD COLUMN paused BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE reingestion_jobs (
+    id BIGSERIAL PRIMARY KEY NOT NULL,
+    from_block BIGINT NOT NULL,
+    to_block BIGINT NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    current_block BIGINT,
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT (NOW() AT TIME ZONE 'utc'),
+    updated_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT (NOW() AT TIME ZONE 'utc'),
+    CHECK (from_block > 0 AND from_block <= to_block)
+);
+
+-- The listener runs one job at a time
+CREATE UNIQUE INDEX reingestion_jobs_active_index ON reingestion_jobs ((TRUE)) WHERE status <> 'done';
+
+CREATE TABLE admin_audit_log (
+    id BIGSERIAL PRIMARY KEY NOT NULL,
+    action VARCHAR(32) NOT NULL,
+    details JSONB NOT NULL,
+    remote_addr TEXT NOT NULL,
+    user_agent TEXT NOT NULL,
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT (NOW() AT TIME ZONE 'utc')
+);
+
+CREATE INDEX admin_audit_log_action_index ON admin_audit_log (action);
+
+-- +migrate Down
+DROP INDEX IF EXISTS admin_audit_log_action_index;
+DROP INDEX IF EXISTS reingestion_jobs_active_index;
+
+DROP TABLE IF EXISTS admin_audit_log;
+DROP TABLE IF EXISTS reingestion_jobs;
+
+ALTER TABLE last_processed_block DROP COLUMN IF EXISTS paused;
@@ -0,0 +1,20 @@
+-- +migrate Up
+CREATE TABLE api_keys (
+    id BIGSERIAL PRIMARY KEY NOT NULL,
+    name TEXT NOT NULL,
+    -- SHA-256 of the key, the key itself is only shown when it is issued
+    key_hash CHAR(64) NOT NULL UNIQUE,
+    prefix VARCHAR(16) NOT NULL,
+    scopes TEXT[] NOT NULL,
+    rate DOUBLE PRECISION,
+    burst INTEGER,
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT (NOW() AT TIME ZONE 'utc'),
+    revoked_at TIMESTAMP WITHOUT TIME ZONE
+);
+
+ALTER TABLE admin_audit_log ADD COLUMN api_key_id BIGINT REFERENCES api_keys (id);
+
+-- +migrate Down
+ALTER TABLE admin_audit_log DROP COLUMN IF EXISTS api_key_id;
+
+DROP TABLE IF EXISTS api_keys;
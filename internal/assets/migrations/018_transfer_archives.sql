@@ -0,0 +1,32 @@
+-- +migrate Up
+CREATE TABLE transfer_archives (
+    id BIGSERIAL PRIMARY KEY NOT NULL,
+    month DATE NOT NULL,
+    from_block BIGINT NOT NULL,
+    to_block BIGINT NOT NULL,
+    min_transfer_id BIGINT NOT NULL,
+    max_transfer_id BIGINT NOT NULL,
+    transfer_count BIGINT NOT NULL,
+    path TEXT NOT NULL UNIQUE,
+    size BIGINT NOT NULL,
+    created_at TIMESTAMP WITHOUT TIME ZONE NOT NULL DEFAULT (NOW() AT TIME ZONE 'utc'),
+    CHECK (from_block <= to_block)
+);
+
+CREATE INDEX transfer_archives_blocks_index ON transfer_archives (from_block, to_block);
+CREATE INDEX transfer_archives_transfer_ids_index ON transfer_archives (min_transfer_id, max_transfer_id);
+
+-- Transfers of every block up to block_number are in the archive files
+CREATE TABLE archived_block (
+    id SMALLINT PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    block_number BIGINT NOT NULL
+);
+
+INSERT INTO archived_block (block_number) VALUES (0);
+
+-- +migrate Down
+DROP TABLE IF EXISTS archived_block;
+
+DROP INDEX IF EXISTS transfer_archives_transfer_ids_index;
+DROP INDEX IF EXISTS transfer_archives_blocks_index;
+DROP TABLE IF EXISTS transfer_archives;
@@ -0,0 +1,87 @@
+-- +migrate Up
+-- usdt_transfers becomes a table partitioned by block ranges. The stored
+-- rows are not copied, the existing table is attached as the partition of
+-- the blocks up to the next 1000000 block boundary and the partitions after
+-- it are created by the service. Its indexes match the ones of the
+-- partitioned table and are attached as they are, only the primary key has
+-- to include block_number and is built again. The duplicate
+-- idx_usdt_transfers_from_address is dropped.
+DROP INDEX IF EXISTS idx_usdt_transfers_from_address;
+
+ALTER SEQUENCE usdt_transfers_id_seq OWNED BY NONE;
+ALTER TABLE usdt_transfers RENAME TO usdt_transfers_p0;
+ALTER TABLE usdt_transfers_p0 DROP CONSTRAINT usdt_transfers_pkey;
+ALTER TABLE usdt_transfers_p0 ADD CONSTRAINT usdt_transfers_p0_pkey PRIMARY KEY (id, block_number);
+ALTER INDEX usdt_transfers_from_index RENAME TO usdt_transfers_p0_from_index;
+ALTER INDEX usdt_transfers_to_index RENAME TO usdt_transfers_p0_to_index;
+ALTER INDEX usdt_transfers_timestamp_index RENAME TO usdt_transfers_p0_timestamp_index;
+ALTER INDEX usdt_transfers_flagged_index RENAME TO usdt_transfers_p0_flagged_index;
+ALTER INDEX usdt_transfers_tx_log_index RENAME TO usdt_transfers_p0_tx_log_index;
+
+-- The partition key must be a part of every unique index
+CREATE TABLE usdt_transfers (
+    id BIGINT NOT NULL DEFAULT nextval('usdt_transfers_id_seq'),
+    from_address CHAR(42) NOT NULL,
+    to_address CHAR(42) NOT NULL,
+    amount NUMERIC NOT NULL,
+    transaction_hash CHAR(66) NOT NULL,
+    block_number BIGINT NOT NULL,
+    log_index INTEGER NOT NULL,
+    timestamp TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    flags INTEGER NOT NULL DEFAULT 0,
+    fee NUMERIC NOT NULL DEFAULT 0,
+    principal_log_index INTEGER,
+    PRIMARY KEY (id, block_number)
+) PARTITION BY RANGE (block_number);
+
+ALTER SEQUENCE usdt_transfers_id_seq OWNED BY usdt_transfers.id;
+
+CREATE INDEX usdt_transfers_from_index ON usdt_transfers (from_address);
+CREATE INDEX usdt_transfers_to_index ON usdt_transfers (to_address);
+CREATE INDEX usdt_transfers_timestamp_index ON usdt_transfers (timestamp);
+CREATE INDEX usdt_transfers_flagged_index ON usdt_transfers (timestamp) WHERE flags <> 0;
+CREATE UNIQUE INDEX usdt_transfers_tx_log_index ON usdt_transfers (block_number, log_index);
+
+-- The bound is read from the unique index, attaching scans the table once
+-- to check it
+-- +migrate StatementBegin
+DO $$
+DECLARE
+    partition_blocks CONSTANT BIGINT := 1000000;
+    to_block BIGINT;
+BEGIN
+    SELECT COALESCE(MAX(block_number), 0) / partition_blocks * partition_blocks + partition_blocks
+    INTO to_block
+    FROM usdt_transfers_p0;
+
+    EXECUTE format('ALTER TABLE usdt_transfers ATTACH PARTITION usdt_transfers_p0 FOR VALUES FROM (0) TO (%s)',
+        to_block);
+END
+$$;
+-- +migrate StatementEnd
+
+-- +migrate Down
+-- The transfers of the partitions created since are moved back to the
+-- first one, which becomes the table again
+ALTER TABLE usdt_transfers DETACH PARTITION usdt_transfers_p0;
+
+INSERT INTO usdt_transfers_p0 (id, from_address, to_address, amount, transaction_hash, block_number,
+                               log_index, timestamp, flags, fee, principal_log_index)
+SELECT id, from_address, to_address, amount, transaction_hash, block_number,
+       log_index, timestamp, flags, fee, principal_log_index
+FROM usdt_transfers;
+
+ALTER SEQUENCE usdt_transfers_id_seq OWNED BY NONE;
+DROP TABLE usdt_transfers;
+
+ALTER TABLE usdt_transfers_p0 RENAME TO usdt_transfers;
+ALTER TABLE usdt_transfers DROP CONSTRAINT usdt_transfers_p0_pkey;
+ALTER TABLE usdt_transfers ADD CONSTRAINT usdt_transfers_pkey PRIMARY KEY (id);
+ALTER INDEX usdt_transfers_p0_from_index RENAME TO usdt_transfers_from_index;
+ALTER INDEX usdt_transfers_p0_to_index RENAME TO usdt_transfers_to_index;
+ALTER INDEX usdt_transfers_p0_timestamp_index RENAME TO usdt_transfers_timestamp_index;
+ALTER INDEX usdt_transfers_p0_flagged_index RENAME TO usdt_transfers_flagged_index;
+ALTER INDEX usdt_transfers_p0_tx_log_index RENAME TO usdt_transfers_tx_log_index;
+ALTER SEQUENCE usdt_transfers_id_seq OWNED BY usdt_transfers.id;
+
+CREATE INDEX idx_usdt_transfers_from_address ON usdt_transfers(from_address);
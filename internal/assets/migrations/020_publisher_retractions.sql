@@ -0,0 +1,25 @@
+-- +migrate Up
+-- Outbox of retractions of published transfers rolled back by a reorg,
+-- written in the rollback transaction together with the position rewind
+CREATE TABLE publisher_retractions (
+    id BIGSERIAL PRIMARY KEY NOT NULL,
+    name VARCHAR(64) NOT NULL REFERENCES publisher_positions (name) ON DELETE CASCADE,
+    transfer_id BIGINT NOT NULL,
+    from_address CHAR(42) NOT NULL,
+    to_address CHAR(42) NOT NULL,
+    amount NUMERIC NOT NULL,
+    transaction_hash CHAR(66) NOT NULL,
+    block_number BIGINT NOT NULL,
+    log_index INTEGER NOT NULL,
+    timestamp TIMESTAMP WITHOUT TIME ZONE NOT NULL,
+    flags INTEGER NOT NULL,
+    fee NUMERIC NOT NULL,
+    principal_log_index INTEGER
+);
+
+CREATE INDEX publisher_retractions_name_index ON publisher_retractions (name, id);
+
+-- +migrate Down
+DROP INDEX IF EXISTS publisher_retractions_name_index;
+
+DROP TABLE IF EXISTS publisher_retractions;
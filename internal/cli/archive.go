@@ -0,0 +1,20 @@
+package cli
+
+import (
+	"context"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/config"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data/pg"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/archiver"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// Archive archives the transfers beyond the retention limits once
+func Archive(cfg config.Config) error {
+	if !cfg.Retention().Enabled() {
+		return errors.New("retention is not configured, set retention.blocks or retention.days")
+	}
+
+	_, err := archiver.NewArchiver(cfg, pg.NewMasterQ(cfg.DB()), cfg.Log()).Archive(context.Background())
+	return errors.Wrap(err, "failed to archive transfers")
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/config"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data/pg"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/export"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/requests"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// ExportTransfers writes the transfers matching the request to a local
+// file, the file is removed if the export fails
+func ExportTransfers(cfg config.Config, request requests.ExportTransfersRequest, path string) (err error) {
+	if err := request.Validate(); err != nil {
+		return errors.Wrap(err, "invalid export filters")
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return errors.Wrap(err, "failed to create export file")
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil && closeErr != nil {
+			err = errors.Wrap(closeErr, "failed to close export file")
+		}
+		if err != nil {
+			os.Remove(path)
+		}
+	}()
+
+	db := pg.NewMasterQ(cfg.DB())
+	err = db.Transaction(func(q data.MasterQ) error {
+		return export.Export(request.Apply(q.USDTTransfer()), request.Format, file)
+	})
+	if err != nil {
+		return err
+	}
+
+	archivedBlock, err := db.TransferArchive().LastBlock()
+	if err != nil {
+		return errors.Wrap(err, "failed to get last archived block")
+	}
+	if archivedBlock != 0 && request.FromBlock <= archivedBlock {
+		cfg.Log().WithFields(logan.F{
+			"archivedBlock": archivedBlock,
+			"dir":           cfg.Retention().Dir,
+		}).Warn("Transfers up to the archived block are in the archive files and were not exported")
+	}
+
+	cfg.Log().WithFields(logan.F{
+		"file":   path,
+		"format": request.Format,
+	}).Info("Transfers exported")
+	return nil
+}
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/config"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data/pg"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/auth"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// IssueKey creates an API key and prints it, the key can't be shown again
+func IssueKey(cfg config.Config, name string, scopes []string, rate *float64, burst *int) error {
+	if rate != nil && *rate <= 0 || burst != nil && *burst <= 0 {
+		return errors.New("rate and burst must be positive")
+	}
+
+	key, prefix, hash, err := auth.NewKey()
+	if err != nil {
+		return err
+	}
+
+	apiKey, err := pg.NewMasterQ(cfg.DB()).APIKey().Insert(data.APIKey{
+		Name:    name,
+		KeyHash: hash,
+		Prefix:  prefix,
+		Scopes:  scopes,
+		Rate:    rate,
+		Burst:   burst,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to insert API key")
+	}
+
+	cfg.Log().WithFields(logan.F{
+		"id":     apiKey.ID,
+		"name":   apiKey.Name,
+		"scopes": apiKey.Scopes,
+	}).Info("API key issued")
+	fmt.Println(key)
+	return nil
+}
+
+func RevokeKey(cfg config.Config, id int64) error {
+	db := pg.NewMasterQ(cfg.DB())
+
+	apiKey, err := db.APIKey().FilterByID(id).Get()
+	if err != nil {
+		return errors.Wrap(err, "failed to get API key")
+	}
+	if apiKey == nil {
+		return errors.From(errors.New("API key not found"), logan.F{"id": id})
+	}
+
+	if err := db.APIKey().FilterByID(id).Revoke(); err != nil {
+		return errors.Wrap(err, "failed to revoke API key")
+	}
+
+	cfg.Log().WithFields(logan.F{"id": id, "name": apiKey.Name}).Info("API key revoked")
+	return nil
+}
+
+func ListKeys(cfg config.Config) error {
+	keys, err := pg.NewMasterQ(cfg.DB()).APIKey().Select()
+	if err != nil {
+		return errors.Wrap(err, "failed to select API keys")
+	}
+
+	for _, key := range keys {
+		revoked := "-"
+		if key.RevokedAt != nil {
+			revoked = key.RevokedAt.Format("2006-01-02T15:04:05Z")
+		}
+		fmt.Printf("%d\t%s\t%s\t%v\trevoked: %s\n", key.ID, key.Prefix, key.Name, []string(key.Scopes), revoked)
+	}
+	return nil
+}
+
+// optionalFloat and optionalInt turn unset flags into nil
+func optionalFloat(value float64) *float64 {
+	if value == 0 {
+		return nil
+	}
+	return &value
+}
+
+func optionalInt(value int) *int {
+	if value == 0 {
+		return nil
+	}
+	return &value
+}
@@ -2,7 +2,10 @@ package cli
 
 import (
 	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/config"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
 	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/export"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/requests"
 	"github.com/alecthomas/kingpin"
 	"gitlab.com/distributed_lab/kit/kv"
 	"gitlab.com/distributed_lab/logan/v3"
@@ -29,6 +32,50 @@ func Run(args []string) bool {
     migrateUpCmd := migrateCmd.Command("up", "migrate db up")
     migrateDownCmd := migrateCmd.Command("down", "migrate db down")
 
+    reconcileCmd := app.Command("reconcile", "compare derived balances with on-chain balanceOf once")
+    reconcileAddresses := reconcileCmd.Flag("address", "address to reconcile, random holders are sampled if omitted").Strings()
+
+    screeningCmd := app.Command("screening", "sanctions and blacklist screening")
+    importListCmd := screeningCmd.Command("import", "replace a sanctions list with addresses from a file and rescreen transfers")
+    importListName := importListCmd.Arg("list", "list name, e.g. ofac").Required().String()
+    importListFile := importListCmd.Arg("file", "path to the list file").Required().ExistingFile()
+    seedBlacklistCmd := screeningCmd.Command("seed-blacklist", "record addresses blacklisted before the starting block")
+    seedBlacklistAddresses := seedBlacklistCmd.Flag("address", "address to check, all holders are checked if omitted").Strings()
+
+    keysCmd := app.Command("keys", "API keys")
+    issueKeyCmd := keysCmd.Command("issue", "issue an API key and print it")
+    issueKeyName := issueKeyCmd.Flag("name", "name of the key owner").Required().String()
+    issueKeyScopes := issueKeyCmd.Flag("scope", "scope of the key, can be repeated").Required().Enums(data.ScopeRead, data.ScopeStream, data.ScopeAdmin)
+    issueKeyRate := issueKeyCmd.Flag("rate", "requests per second, auth.rate if omitted").Float64()
+    issueKeyBurst := issueKeyCmd.Flag("burst", "rate limit bucket size, auth.burst if omitted").Int()
+    revokeKeyCmd := keysCmd.Command("revoke", "revoke an API key")
+    revokeKeyID := revokeKeyCmd.Arg("id", "key id").Required().Int64()
+    listKeysCmd := keysCmd.Command("list", "list API keys")
+
+    exportCmd := app.Command("export", "export transfers matching the filters to a file")
+    exportOut := exportCmd.Flag("out", "path of the file to write").Required().String()
+    exportRequest := requests.ExportTransfersRequest{}
+    exportCmd.Flag("format", "file format").Default(export.FormatCSV).EnumVar(&exportRequest.Format, export.Formats...)
+    exportCmd.Flag("address", "address to filter transfers by").StringVar(&exportRequest.Address)
+    exportCmd.Flag("direction", "side of the transfer the address is matched against").Default(requests.DirectionFrom).
+        EnumVar(&exportRequest.Direction, requests.DirectionFrom, requests.DirectionTo, requests.DirectionAny)
+    exportCmd.Flag("min-amount", "minimal amount in base units").StringVar(&exportRequest.MinAmount)
+    exportCmd.Flag("max-amount", "maximal amount in base units").StringVar(&exportRequest.MaxAmount)
+    exportCmd.Flag("watchlist", "watchlist id the address on the direction side must belong to").Int64Var(&exportRequest.Watchlist)
+    exportCmd.Flag("label", "label the address on the direction side must have").StringVar(&exportRequest.Label)
+    exportCmd.Flag("from-block", "first block of the transfers").Uint64Var(&exportRequest.FromBlock)
+    exportCmd.Flag("to-block", "last block of the transfers").Uint64Var(&exportRequest.ToBlock)
+
+    recordCmd := app.Command("record", "ingest a block range from the RPC node into the database and record the responses for replay")
+    recordFrom := recordCmd.Flag("from", "first block of the range").Required().Uint64()
+    recordTo := recordCmd.Flag("to", "last block of the range").Required().Uint64()
+    recordDir := recordCmd.Flag("dir", "recording directory").Required().String()
+
+    replayCmd := app.Command("replay", "ingest recorded block ranges without the RPC node")
+    replayDirs := replayCmd.Arg("dir", "recording directories, replayed in order").Required().ExistingDirs()
+
+    archiveCmd := app.Command("archive", "move transfers beyond the retention limits to archive files once")
+
     // custom commands go here...
 
     cmd, err := app.Parse(args[1:])
@@ -44,6 +91,26 @@ func Run(args []string) bool {
         err = MigrateUp(cfg)
     case migrateDownCmd.FullCommand():
         err = MigrateDown(cfg)
+    case reconcileCmd.FullCommand():
+        err = Reconcile(cfg, *reconcileAddresses)
+    case importListCmd.FullCommand():
+        err = ImportSanctionsList(cfg, *importListName, *importListFile)
+    case seedBlacklistCmd.FullCommand():
+        err = SeedBlacklist(cfg, *seedBlacklistAddresses)
+    case issueKeyCmd.FullCommand():
+        err = IssueKey(cfg, *issueKeyName, *issueKeyScopes, optionalFloat(*issueKeyRate), optionalInt(*issueKeyBurst))
+    case revokeKeyCmd.FullCommand():
+        err = RevokeKey(cfg, *revokeKeyID)
+    case listKeysCmd.FullCommand():
+        err = ListKeys(cfg)
+    case exportCmd.FullCommand():
+        err = ExportTransfers(cfg, exportRequest, *exportOut)
+    case recordCmd.FullCommand():
+        err = Record(cfg, *recordFrom, *recordTo, *recordDir)
+    case replayCmd.FullCommand():
+        err = Replay(cfg, *replayDirs)
+    case archiveCmd.FullCommand():
+        err = Archive(cfg)
     // handle any custom commands here in the same way
     default:
         log.Errorf("unknown command %s", cmd)
@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"context"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/config"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data/pg"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/reconciler"
+	"github.com/ethereum/go-ethereum/common"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+func Reconcile(cfg config.Config, addresses []string) error {
+	balanceReconciler, err := reconciler.NewReconciler(cfg, pg.NewMasterQ(cfg.DB()), cfg.Log())
+	if err != nil {
+		return errors.Wrap(err, "failed to create balance reconciler")
+	}
+
+	for i, address := range addresses {
+		if !common.IsHexAddress(address) {
+			return errors.From(errors.New("invalid address"), logan.F{"address": address})
+		}
+		addresses[i] = common.HexToAddress(address).Hex()
+	}
+
+	if len(addresses) == 0 {
+		addresses, err = balanceReconciler.Addresses()
+		if err != nil {
+			return errors.Wrap(err, "failed to get addresses to reconcile")
+		}
+	}
+
+	_, err = balanceReconciler.Reconcile(context.Background(), addresses)
+	return errors.Wrap(err, "failed to reconcile balances")
+}
@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"context"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/config"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data/pg"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/metrics"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/broadcaster"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/listener"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/partitioner"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/recording"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/status"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// Record ingests the block range from the RPC node like the service does
+// and writes every RPC response to the directory, so the range can be
+// replayed without the node. The range is committed to the database as by
+// the service, the recording is replayed into another one.
+func Record(cfg config.Config, fromBlock, toBlock uint64, dir string) error {
+	if fromBlock == 0 || toBlock < fromBlock {
+		return errors.New("from must be positive and to must not be lower than from")
+	}
+
+	db := pg.NewMasterQ(cfg.DB())
+	lastProcessedBlock, err := db.LastProcessedBlock().Get()
+	if err != nil {
+		return errors.Wrap(err, "failed to get last processed block")
+	}
+	// blocks the DB already has are skipped by the listener and not recorded
+	if lastProcessedBlock >= fromBlock {
+		return errors.From(errors.New("database is past the start of the range"), logan.F{
+			"lastProcessedBlock": lastProcessedBlock,
+			"from":               fromBlock,
+		})
+	}
+
+	client, err := metrics.DialEthClient(cfg.Ethereum().RPCURL)
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to Ethereum client")
+	}
+	recorder, err := recording.NewRecorder(client, dir)
+	if err != nil {
+		return errors.Wrap(err, "failed to create recorder")
+	}
+
+	if err := listen(cfg, recorder, fromBlock, toBlock); err != nil {
+		recorder.Close()
+		return err
+	}
+
+	if err := recorder.Finish(fromBlock, toBlock); err != nil {
+		return errors.Wrap(err, "failed to finish recording")
+	}
+
+	cfg.Log().WithFields(logan.F{
+		"from": fromBlock,
+		"to":   toBlock,
+		"dir":  dir,
+	}).Info("Block range recorded")
+	return nil
+}
+
+// Replay ingests recorded block ranges through the listener, the
+// directories are replayed in the given order. Every recording has to
+// continue the database, a gap would leave blocks out and an overlap would
+// apply them twice.
+func Replay(cfg config.Config, dirs []string) error {
+	db := pg.NewMasterQ(cfg.DB())
+	for _, dir := range dirs {
+		replay, err := recording.Open(dir)
+		if err != nil {
+			return errors.Wrap(err, "failed to open recording", logan.F{"dir": dir})
+		}
+		manifest := replay.Manifest()
+
+		lastProcessedBlock, err := db.LastProcessedBlock().Get()
+		if err != nil {
+			return errors.Wrap(err, "failed to get last processed block")
+		}
+		// an empty database starts with the recording
+		if lastProcessedBlock != 0 && lastProcessedBlock != manifest.FromBlock-1 {
+			return errors.From(errors.New("database doesn't end right before the recording"), logan.F{
+				"dir":                dir,
+				"lastProcessedBlock": lastProcessedBlock,
+				"from":               manifest.FromBlock,
+			})
+		}
+
+		if err := listen(cfg, replay, manifest.FromBlock, manifest.ToBlock); err != nil {
+			return errors.Wrap(err, "failed to replay recording", logan.F{"dir": dir})
+		}
+
+		cfg.Log().WithFields(logan.F{
+			"from": manifest.FromBlock,
+			"to":   manifest.ToBlock,
+			"dir":  dir,
+		}).Info("Recording replayed")
+	}
+	return nil
+}
+
+// listen runs the listener over the block range of the chain
+func listen(cfg config.Config, chain listener.Chain, fromBlock, toBlock uint64) error {
+	db := pg.NewMasterQ(cfg.DB())
+	if err := partitioner.NewPartitioner(cfg, db, cfg.Log()).Create(fromBlock, toBlock); err != nil {
+		return errors.Wrap(err, "failed to create transfer partitions")
+	}
+
+	usdtListener, err := listener.NewChainListener(
+		cfg,
+		chain,
+		db,
+		cfg.Log(),
+		broadcaster.New(cfg.Streaming().BufferSize),
+		status.New(cfg.Health().MaxLag),
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to create USDT listener")
+	}
+	usdtListener.StopAt(toBlock)
+
+	return usdtListener.Listen(context.Background(), true, fromBlock)
+}
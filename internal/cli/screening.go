@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"context"
+	"os"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/config"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data/pg"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/screening"
+	"github.com/ethereum/go-ethereum/common"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+func ImportSanctionsList(cfg config.Config, name, path string) error {
+	if len(name) > 64 {
+		return errors.New("list name must be at most 64 characters long")
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return errors.Wrap(err, "failed to open sanctions list")
+	}
+	defer file.Close()
+
+	addresses, err := screening.ParseAddresses(file)
+	if err != nil {
+		return err
+	}
+
+	changed, err := screening.ImportList(pg.NewMasterQ(cfg.DB()), name, path, addresses)
+	if err != nil {
+		return errors.Wrap(err, "failed to import sanctions list")
+	}
+
+	cfg.Log().WithFields(logan.F{
+		"list":      name,
+		"addresses": len(addresses),
+		"changed":   changed,
+	}).Info("Sanctions list imported")
+	return nil
+}
+
+func SeedBlacklist(cfg config.Config, addresses []string) error {
+	db := pg.NewMasterQ(cfg.DB())
+
+	seeder, err := screening.NewBlacklistSeeder(cfg, db, cfg.Log())
+	if err != nil {
+		return errors.Wrap(err, "failed to create blacklist seeder")
+	}
+
+	for i, address := range addresses {
+		if !common.IsHexAddress(address) {
+			return errors.From(errors.New("invalid address"), logan.F{"address": address})
+		}
+		addresses[i] = common.HexToAddress(address).Hex()
+	}
+
+	if len(addresses) == 0 {
+		holders, err := db.Balance().FilterPositive().Select()
+		if err != nil {
+			return errors.Wrap(err, "failed to select holders")
+		}
+		for _, holder := range holders {
+			addresses = append(addresses, holder.Address)
+		}
+	}
+
+	blacklisted, err := seeder.Seed(context.Background(), addresses)
+	if err != nil {
+		return errors.Wrap(err, "failed to seed blacklist")
+	}
+
+	cfg.Log().WithFields(logan.F{
+		"checked":     len(addresses),
+		"blacklisted": blacklisted,
+	}).Info("Blacklist seeded")
+	return nil
+}
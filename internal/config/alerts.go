@@ -0,0 +1,62 @@
+package config
+
+import (
+	"time"
+
+	"gitlab.com/distributed_lab/figure"
+	"gitlab.com/distributed_lab/kit/comfig"
+	"gitlab.com/distributed_lab/kit/kv"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// Alerts configures delivery of alerts raised by the rules. The webhook
+// notifier is available only when WebhookURL is set, its calls are signed
+// with WebhookSecret the same way as transfer webhooks.
+type Alerts struct {
+	Disabled      bool          `fig:"disabled"`
+	PollPeriod    time.Duration `fig:"poll_period"`
+	BatchSize     uint64        `fig:"batch_size"`
+	MaxAttempts   int           `fig:"max_attempts"`
+	Timeout       time.Duration `fig:"timeout"`
+	WebhookURL    string        `fig:"webhook_url"`
+	WebhookSecret string        `fig:"webhook_secret"`
+}
+
+type Alertser interface {
+	Alerts() *Alerts
+}
+
+func NewAlertser(getter kv.Getter) Alertser {
+	return &alertsConfig{
+		getter: getter,
+	}
+}
+
+type alertsConfig struct {
+	getter kv.Getter
+	once   comfig.Once
+}
+
+func (c *alertsConfig) Alerts() *Alerts {
+	return c.once.Do(func() interface{} {
+		cfg := Alerts{
+			PollPeriod:  time.Second,
+			BatchSize:   100,
+			MaxAttempts: 5,
+			Timeout:     10 * time.Second,
+		}
+
+		raw := kv.MustGetStringMap(c.getter, "alerts")
+
+		err := figure.Out(&cfg).From(raw).Please()
+		if err != nil {
+			panic(errors.Wrap(err, "failed to figure out alerts config"))
+		}
+
+		if cfg.MaxAttempts <= 0 {
+			panic(errors.New("alerts max_attempts must be positive"))
+		}
+
+		return &cfg
+	}).(*Alerts)
+}
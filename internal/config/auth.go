@@ -0,0 +1,59 @@
+package config
+
+import (
+	"gitlab.com/distributed_lab/figure"
+	"gitlab.com/distributed_lab/kit/comfig"
+	"gitlab.com/distributed_lab/kit/kv"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// Auth configures API key authentication and rate limiting of the API.
+// When disabled the API is open and the admin endpoints are not served.
+type Auth struct {
+	Disabled bool `fig:"disabled"`
+	// Rate is the number of requests per second a key gets back into its
+	// bucket, keys may override it and Burst
+	Rate float64 `fig:"rate"`
+	// Burst is the size of the token bucket of a key
+	Burst int `fig:"burst"`
+	// AllowedOrigins are the origins browsers may call the API from, "*"
+	// allows any
+	AllowedOrigins []string `fig:"allowed_origins"`
+}
+
+type Auther interface {
+	Auth() *Auth
+}
+
+func NewAuther(getter kv.Getter) Auther {
+	return &authConfig{
+		getter: getter,
+	}
+}
+
+type authConfig struct {
+	getter kv.Getter
+	once   comfig.Once
+}
+
+func (c *authConfig) Auth() *Auth {
+	return c.once.Do(func() interface{} {
+		cfg := Auth{
+			Rate:  10,
+			Burst: 20,
+		}
+
+		raw := kv.MustGetStringMap(c.getter, "auth")
+
+		err := figure.Out(&cfg).From(raw).Please()
+		if err != nil {
+			panic(errors.Wrap(err, "failed to figure out auth config"))
+		}
+
+		if cfg.Rate <= 0 || cfg.Burst <= 0 {
+			panic(errors.New("auth rate limits must be positive"))
+		}
+
+		return &cfg
+	}).(*Auth)
+}
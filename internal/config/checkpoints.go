@@ -0,0 +1,50 @@
+package config
+
+import (
+	"gitlab.com/distributed_lab/figure"
+	"gitlab.com/distributed_lab/kit/comfig"
+	"gitlab.com/distributed_lab/kit/kv"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// Checkpoints configures periodic balance snapshots used to answer
+// historical balance queries without replaying the whole history.
+type Checkpoints struct {
+	Interval uint64 `fig:"interval"`
+}
+
+type Checkpointer interface {
+	Checkpoints() *Checkpoints
+}
+
+func NewCheckpointer(getter kv.Getter) Checkpointer {
+	return &checkpointsConfig{
+		getter: getter,
+	}
+}
+
+type checkpointsConfig struct {
+	getter kv.Getter
+	once   comfig.Once
+}
+
+func (c *checkpointsConfig) Checkpoints() *Checkpoints {
+	return c.once.Do(func() interface{} {
+		cfg := Checkpoints{
+			Interval: 10000,
+		}
+
+		raw := kv.MustGetStringMap(c.getter, "checkpoints")
+
+		err := figure.Out(&cfg).From(raw).Please()
+		if err != nil {
+			panic(errors.Wrap(err, "failed to figure out checkpoints config"))
+		}
+
+		if cfg.Interval == 0 {
+			panic(errors.New("checkpoints interval must be positive"))
+		}
+
+		return &cfg
+	}).(*Checkpoints)
+}
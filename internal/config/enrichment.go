@@ -0,0 +1,54 @@
+package config
+
+import (
+	"gitlab.com/distributed_lab/figure"
+	"gitlab.com/distributed_lab/kit/comfig"
+	"gitlab.com/distributed_lab/kit/kv"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// Enrichment configures fetching of transactions and receipts of the
+// indexed transfers. It costs RPC batches for every block with transfers,
+// so it is off by default.
+type Enrichment struct {
+	Enabled bool `fig:"enabled"`
+	// BatchSize is the maximum number of transactions requested in one RPC
+	// batch, each of them takes a transaction and a receipt call
+	BatchSize int `fig:"batch_size"`
+}
+
+type Enrichmenter interface {
+	Enrichment() *Enrichment
+}
+
+func NewEnrichmenter(getter kv.Getter) Enrichmenter {
+	return &enrichmentConfig{
+		getter: getter,
+	}
+}
+
+type enrichmentConfig struct {
+	getter kv.Getter
+	once   comfig.Once
+}
+
+func (c *enrichmentConfig) Enrichment() *Enrichment {
+	return c.once.Do(func() interface{} {
+		cfg := Enrichment{
+			BatchSize: 100,
+		}
+
+		raw := kv.MustGetStringMap(c.getter, "enrichment")
+
+		err := figure.Out(&cfg).From(raw).Please()
+		if err != nil {
+			panic(errors.Wrap(err, "failed to figure out enrichment config"))
+		}
+
+		if cfg.BatchSize < 1 {
+			panic(errors.New("enrichment batch size must be positive"))
+		}
+
+		return &cfg
+	}).(*Enrichment)
+}
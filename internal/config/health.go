@@ -0,0 +1,56 @@
+package config
+
+import (
+	"time"
+
+	"gitlab.com/distributed_lab/figure"
+	"gitlab.com/distributed_lab/kit/comfig"
+	"gitlab.com/distributed_lab/kit/kv"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// Health configures the readiness check and the sync status
+type Health struct {
+	// MaxLag is the number of blocks the listener may be behind the head
+	// while still being ready, further behind it is backfilling
+	MaxLag uint64 `fig:"max_lag"`
+	// Timeout limits the DB and RPC calls of a readiness check
+	Timeout time.Duration `fig:"timeout"`
+}
+
+type Healther interface {
+	Health() *Health
+}
+
+func NewHealther(getter kv.Getter) Healther {
+	return &healthConfig{
+		getter: getter,
+	}
+}
+
+type healthConfig struct {
+	getter kv.Getter
+	once   comfig.Once
+}
+
+func (c *healthConfig) Health() *Health {
+	return c.once.Do(func() interface{} {
+		cfg := Health{
+			MaxLag:  10,
+			Timeout: 5 * time.Second,
+		}
+
+		raw := kv.MustGetStringMap(c.getter, "health")
+
+		err := figure.Out(&cfg).From(raw).Please()
+		if err != nil {
+			panic(errors.Wrap(err, "failed to figure out health config"))
+		}
+
+		if cfg.Timeout <= 0 {
+			panic(errors.New("health timeout must be positive"))
+		}
+
+		return &cfg
+	}).(*Health)
+}
@@ -14,6 +14,18 @@ type Config interface {
     types.Copuser
     pgdb.Databaser
     Ethereumer
+    Checkpointer
+    Reconciliationer
+    Streamer
+    Webhookser
+    Publisherer
+    Alertser
+    Enrichmenter
+    Tracinger
+    Healther
+    Auther
+    Retentioner
+    Partitioner
 }
 
 type config struct {
@@ -22,16 +34,40 @@ type config struct {
     types.Copuser
     pgdb.Databaser
     Ethereumer
+    Checkpointer
+    Reconciliationer
+    Streamer
+    Webhookser
+    Publisherer
+    Alertser
+    Enrichmenter
+    Tracinger
+    Healther
+    Auther
+    Retentioner
+    Partitioner
     getter kv.Getter
 }
 
 func New(getter kv.Getter) Config {
     return &config{
-        getter:     getter,
-        Databaser:  pgdb.NewDatabaser(getter),
-        Copuser:    copus.NewCopuser(getter),
-        Listenerer: comfig.NewListenerer(getter),
-        Logger:     comfig.NewLogger(getter, comfig.LoggerOpts{}),
-        Ethereumer: NewEthereumer(getter),
+        getter:           getter,
+        Databaser:        pgdb.NewDatabaser(getter),
+        Copuser:          copus.NewCopuser(getter),
+        Listenerer:       comfig.NewListenerer(getter),
+        Logger:           comfig.NewLogger(getter, comfig.LoggerOpts{}),
+        Ethereumer:       NewEthereumer(getter),
+        Checkpointer:     NewCheckpointer(getter),
+        Reconciliationer: NewReconciliationer(getter),
+        Streamer:         NewStreamer(getter),
+        Webhookser:       NewWebhookser(getter),
+        Publisherer:      NewPublisherer(getter),
+        Alertser:         NewAlertser(getter),
+        Enrichmenter:     NewEnrichmenter(getter),
+        Tracinger:        NewTracinger(getter),
+        Healther:         NewHealther(getter),
+        Auther:           NewAuther(getter),
+        Retentioner:      NewRetentioner(getter),
+        Partitioner:      NewPartitioner(getter),
     }
 }
\ No newline at end of file
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"time"
+
+	"gitlab.com/distributed_lab/figure"
+	"gitlab.com/distributed_lab/kit/comfig"
+	"gitlab.com/distributed_lab/kit/kv"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// Partitions configures the block range partitions of usdt_transfers.
+// Partitions of Blocks blocks are created every Period, Premake of them
+// ahead of the last processed block. Changing Blocks only affects new
+// partitions.
+type Partitions struct {
+	Blocks  uint64        `fig:"blocks"`
+	Premake uint64        `fig:"premake"`
+	Period  time.Duration `fig:"period"`
+}
+
+type Partitioner interface {
+	Partitions() *Partitions
+}
+
+func NewPartitioner(getter kv.Getter) Partitioner {
+	return &partitionsConfig{
+		getter: getter,
+	}
+}
+
+type partitionsConfig struct {
+	getter kv.Getter
+	once   comfig.Once
+}
+
+func (c *partitionsConfig) Partitions() *Partitions {
+	return c.once.Do(func() interface{} {
+		cfg := Partitions{
+			Blocks:  1000000,
+			Premake: 2,
+			Period:  time.Minute,
+		}
+
+		raw := kv.MustGetStringMap(c.getter, "partitions")
+
+		err := figure.Out(&cfg).From(raw).Please()
+		if err != nil {
+			panic(errors.Wrap(err, "failed to figure out partitions config"))
+		}
+
+		if cfg.Blocks == 0 || cfg.Premake == 0 || cfg.Period <= 0 {
+			panic(errors.New("partitions blocks, premake and period must be positive"))
+		}
+
+		return &cfg
+	}).(*Partitions)
+}
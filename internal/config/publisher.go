@@ -0,0 +1,79 @@
+package config
+
+import (
+	"time"
+
+	"gitlab.com/distributed_lab/figure"
+	"gitlab.com/distributed_lab/kit/comfig"
+	"gitlab.com/distributed_lab/kit/kv"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// Publisher drivers
+const (
+	PublisherDriverNone  = "none"
+	PublisherDriverNats  = "nats"
+	PublisherDriverKafka = "kafka"
+)
+
+// Publisher configures relaying of ingested transfers to a message broker.
+// Topic is the Kafka topic or the NATS subject prefix, URL is only used by
+// NATS and Brokers only by Kafka.
+type Publisher struct {
+	Driver     string        `fig:"driver"`
+	URL        string        `fig:"url"`
+	Brokers    []string      `fig:"brokers"`
+	Topic      string        `fig:"topic"`
+	BatchSize  uint64        `fig:"batch_size"`
+	PollPeriod time.Duration `fig:"poll_period"`
+}
+
+type Publisherer interface {
+	Publisher() *Publisher
+}
+
+func NewPublisherer(getter kv.Getter) Publisherer {
+	return &publisherConfig{
+		getter: getter,
+	}
+}
+
+type publisherConfig struct {
+	getter kv.Getter
+	once   comfig.Once
+}
+
+func (c *publisherConfig) Publisher() *Publisher {
+	return c.once.Do(func() interface{} {
+		cfg := Publisher{
+			Driver:     PublisherDriverNone,
+			Topic:      "usdt.transfers",
+			BatchSize:  500,
+			PollPeriod: 5 * time.Second,
+		}
+
+		raw := kv.MustGetStringMap(c.getter, "publisher")
+
+		err := figure.Out(&cfg).From(raw).Please()
+		if err != nil {
+			panic(errors.Wrap(err, "failed to figure out publisher config"))
+		}
+
+		switch cfg.Driver {
+		case PublisherDriverNone:
+		case PublisherDriverNats:
+			if cfg.URL == "" {
+				panic(errors.New("publisher url is required for the nats driver"))
+			}
+		case PublisherDriverKafka:
+			if len(cfg.Brokers) == 0 {
+				panic(errors.New("publisher brokers are required for the kafka driver"))
+			}
+		default:
+			panic(errors.From(errors.New("unknown publisher driver"), logan.F{"driver": cfg.Driver}))
+		}
+
+		return &cfg
+	}).(*Publisher)
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gitlab.com/distributed_lab/figure"
+	"gitlab.com/distributed_lab/kit/comfig"
+	"gitlab.com/distributed_lab/kit/kv"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// Reconciliation configures the job comparing derived balances with
+// on-chain balanceOf results.
+type Reconciliation struct {
+	Disabled bool          `fig:"disabled"`
+	Period   time.Duration `fig:"period"`
+	// SampleSize is the number of random holders checked per run when
+	// Addresses is empty
+	SampleSize uint64   `fig:"sample_size"`
+	Addresses  []string `fig:"addresses"`
+}
+
+type Reconciliationer interface {
+	Reconciliation() *Reconciliation
+}
+
+func NewReconciliationer(getter kv.Getter) Reconciliationer {
+	return &reconciliationConfig{
+		getter: getter,
+	}
+}
+
+type reconciliationConfig struct {
+	getter kv.Getter
+	once   comfig.Once
+}
+
+func (c *reconciliationConfig) Reconciliation() *Reconciliation {
+	return c.once.Do(func() interface{} {
+		cfg := Reconciliation{
+			Period:     time.Hour,
+			SampleSize: 100,
+		}
+
+		raw := kv.MustGetStringMap(c.getter, "reconciliation")
+
+		err := figure.Out(&cfg).From(raw).Please()
+		if err != nil {
+			panic(errors.Wrap(err, "failed to figure out reconciliation config"))
+		}
+
+		for _, address := range cfg.Addresses {
+			if !common.IsHexAddress(address) {
+				panic(errors.From(errors.New("invalid reconciliation address"), logan.F{"address": address}))
+			}
+		}
+
+		return &cfg
+	}).(*Reconciliation)
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"time"
+
+	"gitlab.com/distributed_lab/figure"
+	"gitlab.com/distributed_lab/kit/comfig"
+	"gitlab.com/distributed_lab/kit/kv"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// Retention configures archival of old transfers. A transfer is kept in the
+// database while it is within Blocks of the checkpoint or Days of now,
+// older ones are moved to monthly Parquet files in Dir. Zero disables a
+// limit, archival is off when both are zero.
+type Retention struct {
+	Blocks uint64 `fig:"blocks"`
+	Days   uint64 `fig:"days"`
+	Dir    string `fig:"dir"`
+	// URL is where the files of Dir are published, archive downloads are
+	// redirected there instead of being served by the service
+	URL string `fig:"url"`
+	// Period is the time between archival runs
+	Period time.Duration `fig:"period"`
+	// BatchBlocks is the number of blocks archived in one transaction
+	BatchBlocks uint64 `fig:"batch_blocks"`
+}
+
+// Enabled reports whether any retention limit is set
+func (r Retention) Enabled() bool {
+	return r.Blocks != 0 || r.Days != 0
+}
+
+type Retentioner interface {
+	Retention() *Retention
+}
+
+func NewRetentioner(getter kv.Getter) Retentioner {
+	return &retentionConfig{
+		getter: getter,
+	}
+}
+
+type retentionConfig struct {
+	getter kv.Getter
+	once   comfig.Once
+}
+
+func (c *retentionConfig) Retention() *Retention {
+	return c.once.Do(func() interface{} {
+		cfg := Retention{
+			Dir:         "archive",
+			Period:      time.Hour,
+			BatchBlocks: 10000,
+		}
+
+		raw := kv.MustGetStringMap(c.getter, "retention")
+
+		err := figure.Out(&cfg).From(raw).Please()
+		if err != nil {
+			panic(errors.Wrap(err, "failed to figure out retention config"))
+		}
+
+		if cfg.Period <= 0 || cfg.BatchBlocks == 0 {
+			panic(errors.New("retention period and batch_blocks must be positive"))
+		}
+
+		return &cfg
+	}).(*Retention)
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"time"
+
+	"gitlab.com/distributed_lab/figure"
+	"gitlab.com/distributed_lab/kit/comfig"
+	"gitlab.com/distributed_lab/kit/kv"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// Streaming configures backpressure limits of the push endpoints
+type Streaming struct {
+	// BufferSize is the number of events a subscriber may lag behind before
+	// it is dropped
+	BufferSize int `fig:"buffer_size"`
+	// MaxFilters limits the filters a single WebSocket connection can hold
+	MaxFilters int `fig:"max_filters"`
+	// WriteTimeout drops WebSocket clients that don't read their socket
+	WriteTimeout time.Duration `fig:"write_timeout"`
+}
+
+type Streamer interface {
+	Streaming() *Streaming
+}
+
+func NewStreamer(getter kv.Getter) Streamer {
+	return &streamingConfig{
+		getter: getter,
+	}
+}
+
+type streamingConfig struct {
+	getter kv.Getter
+	once   comfig.Once
+}
+
+func (c *streamingConfig) Streaming() *Streaming {
+	return c.once.Do(func() interface{} {
+		cfg := Streaming{
+			BufferSize:   1024,
+			MaxFilters:   32,
+			WriteTimeout: 10 * time.Second,
+		}
+
+		raw := kv.MustGetStringMap(c.getter, "streaming")
+
+		err := figure.Out(&cfg).From(raw).Please()
+		if err != nil {
+			panic(errors.Wrap(err, "failed to figure out streaming config"))
+		}
+
+		if cfg.BufferSize <= 0 || cfg.MaxFilters <= 0 || cfg.WriteTimeout <= 0 {
+			panic(errors.New("streaming limits must be positive"))
+		}
+
+		return &cfg
+	}).(*Streaming)
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"gitlab.com/distributed_lab/figure"
+	"gitlab.com/distributed_lab/kit/comfig"
+	"gitlab.com/distributed_lab/kit/kv"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// Tracing configures OpenTelemetry spans of the listener, the HTTP handlers
+// and the DB queries they make, exported to an OTLP/HTTP collector
+type Tracing struct {
+	Enabled bool `fig:"enabled"`
+	// Endpoint is the host:port of the collector, the exporter posts to
+	// its /v1/traces path
+	Endpoint    string `fig:"endpoint"`
+	Insecure    bool   `fig:"insecure"`
+	ServiceName string `fig:"service_name"`
+	// SampleRatio is the share of traces started by the service that are
+	// recorded, traces of incoming requests follow the caller decision
+	SampleRatio float64 `fig:"sample_ratio"`
+}
+
+type Tracinger interface {
+	Tracing() *Tracing
+}
+
+func NewTracinger(getter kv.Getter) Tracinger {
+	return &tracingConfig{
+		getter: getter,
+	}
+}
+
+type tracingConfig struct {
+	getter kv.Getter
+	once   comfig.Once
+}
+
+func (c *tracingConfig) Tracing() *Tracing {
+	return c.once.Do(func() interface{} {
+		cfg := Tracing{
+			Endpoint:    "localhost:4318",
+			ServiceName: "usdt-listener-svc",
+			SampleRatio: 1,
+		}
+
+		raw := kv.MustGetStringMap(c.getter, "tracing")
+
+		err := figure.Out(&cfg).From(raw).Please()
+		if err != nil {
+			panic(errors.Wrap(err, "failed to figure out tracing config"))
+		}
+
+		if cfg.SampleRatio < 0 || cfg.SampleRatio > 1 {
+			panic(errors.New("tracing sample ratio must be within [0, 1]"))
+		}
+
+		return &cfg
+	}).(*Tracing)
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"time"
+
+	"gitlab.com/distributed_lab/figure"
+	"gitlab.com/distributed_lab/kit/comfig"
+	"gitlab.com/distributed_lab/kit/kv"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// Webhooks configures the outbox dispatcher. Failed calls are retried after
+// MinBackoff doubled with every attempt up to MaxBackoff, deliveries failed
+// MaxAttempts times are moved to the dead status.
+type Webhooks struct {
+	Disabled    bool          `fig:"disabled"`
+	PollPeriod  time.Duration `fig:"poll_period"`
+	Timeout     time.Duration `fig:"timeout"`
+	BatchSize   uint64        `fig:"batch_size"`
+	MaxAttempts int           `fig:"max_attempts"`
+	MinBackoff  time.Duration `fig:"min_backoff"`
+	MaxBackoff  time.Duration `fig:"max_backoff"`
+}
+
+type Webhookser interface {
+	Webhooks() *Webhooks
+}
+
+func NewWebhookser(getter kv.Getter) Webhookser {
+	return &webhooksConfig{
+		getter: getter,
+	}
+}
+
+type webhooksConfig struct {
+	getter kv.Getter
+	once   comfig.Once
+}
+
+func (c *webhooksConfig) Webhooks() *Webhooks {
+	return c.once.Do(func() interface{} {
+		cfg := Webhooks{
+			PollPeriod:  time.Second,
+			Timeout:     10 * time.Second,
+			BatchSize:   100,
+			MaxAttempts: 10,
+			MinBackoff:  10 * time.Second,
+			MaxBackoff:  time.Hour,
+		}
+
+		raw := kv.MustGetStringMap(c.getter, "webhooks")
+
+		err := figure.Out(&cfg).From(raw).Please()
+		if err != nil {
+			panic(errors.Wrap(err, "failed to figure out webhooks config"))
+		}
+
+		if cfg.MaxAttempts <= 0 || cfg.MinBackoff <= 0 || cfg.MaxBackoff < cfg.MinBackoff {
+			panic(errors.New("invalid webhooks retry policy"))
+		}
+
+		return &cfg
+	}).(*Webhooks)
+}
@@ -0,0 +1,80 @@
+package data
+
+import (
+	"encoding/json"
+	"time"
+
+	"gitlab.com/distributed_lab/kit/pgdb"
+)
+
+// Statuses of a re-ingestion job
+const (
+	// ReingestionStatusPending jobs wait for the listener to pick them up
+	ReingestionStatusPending = "pending"
+	// ReingestionStatusRollingBack jobs roll back every block from the
+	// checkpoint down to the first block of the range
+	ReingestionStatusRollingBack = "rolling_back"
+	// ReingestionStatusReprocessing jobs wait for the listener to process
+	// the range again
+	ReingestionStatusReprocessing = "reprocessing"
+	ReingestionStatusDone         = "done"
+)
+
+// Actions recorded in the admin audit log
+const (
+	AuditActionSetCheckpoint = "set_checkpoint"
+	AuditActionPause         = "pause"
+	AuditActionResume        = "resume"
+	AuditActionReingest      = "reingest"
+)
+
+// ReingestionJob re-ingests a range of processed blocks. CurrentBlock is the
+// block rolled back down to while rolling back and the block processed up to
+// while reprocessing.
+type ReingestionJob struct {
+	ID           int64     `db:"id"`
+	FromBlock    uint64    `db:"from_block"`
+	ToBlock      uint64    `db:"to_block"`
+	Status       string    `db:"status"`
+	CurrentBlock *uint64   `db:"current_block"`
+	CreatedAt    time.Time `db:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at"`
+}
+
+type AuditEntry struct {
+	ID         int64           `db:"id"`
+	Action     string          `db:"action"`
+	Details    json.RawMessage `db:"details"`
+	RemoteAddr string          `db:"remote_addr"`
+	UserAgent  string          `db:"user_agent"`
+	// APIKeyID is the key that made the action
+	APIKeyID  *int64    `db:"api_key_id"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type ReingestionJobQ interface {
+	New() ReingestionJobQ
+
+	Get() (*ReingestionJob, error)
+	Select() ([]ReingestionJob, error)
+	Insert(job ReingestionJob) (*ReingestionJob, error)
+	// UpdateProgress moves the job to the status and the current block
+	UpdateProgress(id int64, status string, currentBlock uint64) error
+
+	FilterByID(id int64) ReingestionJobQ
+	// FilterActive selects the job that is not done yet, there is at most one
+	FilterActive() ReingestionJobQ
+
+	Page(pageParams *pgdb.OffsetPageParams) ReingestionJobQ
+}
+
+type AuditLogQ interface {
+	New() AuditLogQ
+
+	Select() ([]AuditEntry, error)
+	Insert(entry AuditEntry) error
+
+	FilterByAction(action string) AuditLogQ
+
+	Page(pageParams *pgdb.OffsetPageParams) AuditLogQ
+}
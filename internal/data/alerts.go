@@ -0,0 +1,96 @@
+package data
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	"gitlab.com/distributed_lab/kit/pgdb"
+)
+
+// Kinds of alert rules
+const (
+	// AlertRuleAmount matches transfers of at least MinAmount, optionally
+	// touching one of Addresses
+	AlertRuleAmount = "amount"
+	// AlertRuleAddress matches transfers from or to one of Addresses,
+	// optionally of at least MinAmount
+	AlertRuleAddress = "address"
+	// AlertRuleVelocity matches senders making TransferCount transfers (of
+	// at least MinAmount if set) within TimeWindow seconds. Addresses, if
+	// set, limits the watched senders.
+	AlertRuleVelocity = "velocity"
+)
+
+// Names of the notifiers an alert rule can deliver through
+const (
+	AlertNotifierLog     = "log"
+	AlertNotifierWebhook = "webhook"
+)
+
+type AlertRule struct {
+	ID            int64          `db:"id"`
+	Name          string         `db:"name"`
+	Kind          string         `db:"kind"`
+	MinAmount     *string        `db:"min_amount"`
+	Addresses     pq.StringArray `db:"addresses"`
+	TransferCount *int64         `db:"transfer_count"`
+	TimeWindow    *int64         `db:"time_window"`
+	Notifiers     pq.StringArray `db:"notifiers"`
+	CreatedAt     time.Time      `db:"created_at"`
+}
+
+// Alert is a rule match. For velocity rules it points to the latest transfer
+// of the sender, Amount and TransferCount cover the whole window.
+type Alert struct {
+	ID              int64      `db:"id"`
+	RuleID          int64      `db:"rule_id"`
+	Address         string     `db:"address"`
+	Amount          string     `db:"amount"`
+	TransferCount   int64      `db:"transfer_count"`
+	TransactionHash string     `db:"transaction_hash"`
+	BlockNumber     uint64     `db:"block_number"`
+	LogIndex        uint64     `db:"log_index"`
+	Timestamp       time.Time  `db:"timestamp"`
+	Attempts        int        `db:"attempts"`
+	LastError       *string    `db:"last_error"`
+	NotifiedAt      *time.Time `db:"notified_at"`
+	CreatedAt       time.Time  `db:"created_at"`
+}
+
+type AlertRuleQ interface {
+	New() AlertRuleQ
+
+	Get() (*AlertRule, error)
+	Select() ([]AlertRule, error)
+	Insert(rule AlertRule) (*AlertRule, error)
+	Delete() error
+
+	FilterByID(ids ...int64) AlertRuleQ
+
+	Page(pageParams *pgdb.OffsetPageParams) AlertRuleQ
+}
+
+type AlertQ interface {
+	New() AlertQ
+
+	Get() (*Alert, error)
+	Select() ([]Alert, error)
+
+	// EvaluateBlock records an alert for every rule matched by the
+	// transfers of the block
+	EvaluateBlock(blockNumber uint64) error
+	DeleteByBlockNumber(blockNumber uint64) error
+	MarkNotified(id int64) error
+	MarkFailed(id int64, reason string) error
+
+	FilterByID(id int64) AlertQ
+	FilterByRuleID(ruleID int64) AlertQ
+	FilterByAddress(address string) AlertQ
+	// FilterPending keeps alerts not notified yet with less than maxAttempts
+	// failed notification attempts
+	FilterPending(maxAttempts int) AlertQ
+
+	OrderByID() AlertQ
+	Limit(limit uint64) AlertQ
+	Page(pageParams *pgdb.OffsetPageParams) AlertQ
+}
@@ -0,0 +1,59 @@
+package data
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	"gitlab.com/distributed_lab/kit/pgdb"
+)
+
+// Scopes of an API key
+const (
+	// ScopeRead allows the REST endpoints reading indexed data
+	ScopeRead = "read"
+	// ScopeStream allows the SSE and WebSocket transfer streams
+	ScopeStream = "stream"
+	// ScopeAdmin allows the admin endpoints and every endpoint changing
+	// webhooks, alert rules, labels and watchlists
+	ScopeAdmin = "admin"
+)
+
+// APIKey is identified by the SHA-256 of the key, Prefix is the start of
+// the key to tell keys apart in listings. Rate and Burst override the
+// default rate limit of the service when set.
+type APIKey struct {
+	ID        int64          `db:"id"`
+	Name      string         `db:"name"`
+	KeyHash   string         `db:"key_hash" json:"-"`
+	Prefix    string         `db:"prefix"`
+	Scopes    pq.StringArray `db:"scopes"`
+	Rate      *float64       `db:"rate"`
+	Burst     *int           `db:"burst"`
+	CreatedAt time.Time      `db:"created_at"`
+	RevokedAt *time.Time     `db:"revoked_at"`
+}
+
+func (k APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+type APIKeyQ interface {
+	New() APIKeyQ
+
+	Get() (*APIKey, error)
+	Select() ([]APIKey, error)
+	Insert(key APIKey) (*APIKey, error)
+	// Revoke revokes the selected keys that are not revoked yet
+	Revoke() error
+
+	FilterByID(id int64) APIKeyQ
+	FilterByHash(hash string) APIKeyQ
+	FilterActive() APIKeyQ
+
+	Page(pageParams *pgdb.OffsetPageParams) APIKeyQ
+}
@@ -0,0 +1,29 @@
+package data
+
+import (
+	"time"
+
+	"gitlab.com/distributed_lab/kit/pgdb"
+)
+
+// BalanceDiscrepancy records a mismatch between the balance derived by the
+// indexer and the one returned by the contract at the same block.
+type BalanceDiscrepancy struct {
+	ID             int64     `db:"id"`
+	Address        string    `db:"address"`
+	BlockNumber    uint64    `db:"block_number"`
+	IndexedBalance string    `db:"indexed_balance"`
+	OnchainBalance string    `db:"onchain_balance"`
+	CreatedAt      time.Time `db:"created_at"`
+}
+
+type BalanceDiscrepancyQ interface {
+	New() BalanceDiscrepancyQ
+
+	Select() ([]BalanceDiscrepancy, error)
+	Insert(discrepancy BalanceDiscrepancy) (*BalanceDiscrepancy, error)
+
+	FilterByAddress(address string) BalanceDiscrepancyQ
+
+	Page(pageParams *pgdb.OffsetPageParams) BalanceDiscrepancyQ
+}
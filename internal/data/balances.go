@@ -0,0 +1,109 @@
+package data
+
+import (
+	"math/big"
+
+	"gitlab.com/distributed_lab/kit/pgdb"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+type Balance struct {
+	Address      string `db:"address"`
+	Balance      string `db:"balance"`
+	UpdatedBlock uint64 `db:"updated_block"`
+}
+
+// BalanceChange is a single signed movement of funds caused by a contract
+// event. A transfer produces two changes, Issue/Redeem/DestroyedBlackFunds one.
+type BalanceChange struct {
+	ID          int64  `db:"id"`
+	Address     string `db:"address"`
+	Delta       string `db:"delta"`
+	BlockNumber uint64 `db:"block_number"`
+	LogIndex    uint64 `db:"log_index"`
+}
+
+type BalanceQ interface {
+	New() BalanceQ
+
+	Get() (*Balance, error)
+	Select() ([]Balance, error)
+
+	// ApplyBlock adds the balance changes of the block to the balances.
+	ApplyBlock(blockNumber uint64) error
+	// RevertBlock subtracts the balance changes of the block from the balances.
+	RevertBlock(blockNumber uint64) error
+	// Seed adds the baseline balances read from the contract to the balances.
+	Seed(baselines []BalanceChange) error
+
+	FilterByAddress(address string) BalanceQ
+	FilterPositive() BalanceQ
+	// Sample picks random rows among the matching ones
+	Sample(limit uint64) BalanceQ
+
+	Page(pageParams *pgdb.OffsetPageParams) BalanceQ
+}
+
+type BalanceChangeQ interface {
+	New() BalanceChangeQ
+
+	Select() ([]BalanceChange, error)
+	// Sum returns the total delta of the changes matching the filters
+	Sum() (string, error)
+	InsertBatch(changes []BalanceChange) error
+	DeleteByBlockNumber(blockNumber uint64) error
+
+	FilterByAddress(addresses ...string) BalanceChangeQ
+	FilterByBlockNumber(blockNumber uint64) BalanceChangeQ
+	FilterByBlockRange(fromBlock, toBlock uint64) BalanceChangeQ
+}
+
+// BalanceCheckpoint is a snapshot of an address balance taken after the block
+// was applied. Only addresses changed since the previous checkpoint are stored.
+type BalanceCheckpoint struct {
+	Address     string `db:"address"`
+	BlockNumber uint64 `db:"block_number"`
+	Balance     string `db:"balance"`
+}
+
+type BalanceCheckpointQ interface {
+	New() BalanceCheckpointQ
+
+	// Latest returns the most recent checkpoint matching the filters
+	Latest() (*BalanceCheckpoint, error)
+	// Snapshot stores balances changed after sinceBlock as checkpoints at blockNumber
+	Snapshot(blockNumber, sinceBlock uint64) error
+	DeleteByBlockNumber(blockNumber uint64) error
+
+	FilterByAddress(address string) BalanceCheckpointQ
+	FilterByMaxBlockNumber(blockNumber uint64) BalanceCheckpointQ
+}
+
+// BalanceAt derives the address balance right after the block was applied:
+// the latest checkpoint at or before the block plus the changes made since.
+func BalanceAt(q MasterQ, address string, blockNumber uint64) (*big.Int, error) {
+	balance := new(big.Int)
+	fromBlock := uint64(0)
+
+	checkpoint, err := q.BalanceCheckpoint().FilterByAddress(address).FilterByMaxBlockNumber(blockNumber).Latest()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get balance checkpoint")
+	}
+	if checkpoint != nil {
+		if _, ok := balance.SetString(checkpoint.Balance, 10); !ok {
+			return nil, errors.Errorf("invalid checkpoint balance %q", checkpoint.Balance)
+		}
+		fromBlock = checkpoint.BlockNumber + 1
+	}
+
+	sum, err := q.BalanceChange().FilterByAddress(address).FilterByBlockRange(fromBlock, blockNumber).Sum()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sum balance changes")
+	}
+	delta, ok := new(big.Int).SetString(sum, 10)
+	if !ok {
+		return nil, errors.Errorf("invalid balance changes sum %q", sum)
+	}
+
+	return balance.Add(balance, delta), nil
+}
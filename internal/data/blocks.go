@@ -0,0 +1,40 @@
+package data
+
+import (
+	"time"
+
+	"gitlab.com/distributed_lab/kit/pgdb"
+)
+
+// FinalityDepth is the number of blocks behind the checkpoint the listener
+// may still roll back on a reorg, older blocks are final
+const FinalityDepth = 64
+
+// Block is a processed block with a summary of its transfers
+type Block struct {
+	Number          uint64    `db:"number"`
+	Hash            string    `db:"hash"`
+	ParentHash      string    `db:"parent_hash"`
+	Timestamp       time.Time `db:"timestamp"`
+	TransferCount   uint64    `db:"transfer_count"`
+	Volume          string    `db:"volume"`
+	UniqueSenders   uint64    `db:"unique_senders"`
+	UniqueReceivers uint64    `db:"unique_receivers"`
+}
+
+type BlockQ interface {
+	New() BlockQ
+
+	Get() (*Block, error)
+	Select() ([]Block, error)
+	// Insert stores the block header with the summary of its already
+	// inserted transfers
+	Insert(block Block) error
+	Delete(number uint64) error
+
+	FilterByNumber(number uint64) BlockQ
+	FilterByMinTimestamp(timestamp time.Time) BlockQ
+	FilterByTimestampBefore(timestamp time.Time) BlockQ
+
+	Page(pageParams *pgdb.OffsetPageParams) BlockQ
+}
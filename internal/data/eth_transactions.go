@@ -0,0 +1,27 @@
+package data
+
+// EthTransaction is the transaction and receipt data of indexed transfers.
+// ToAddress is nil for contract creations, MethodSelector is nil for calls
+// without input.
+type EthTransaction struct {
+	Hash              string  `db:"hash"`
+	BlockNumber       uint64  `db:"block_number"`
+	FromAddress       string  `db:"from_address"`
+	ToAddress         *string `db:"to_address"`
+	MethodSelector    *string `db:"method_selector"`
+	GasUsed           uint64  `db:"gas_used"`
+	EffectiveGasPrice string  `db:"effective_gas_price"`
+	// GasFee is the amount of wei paid for the transaction
+	GasFee string `db:"gas_fee"`
+	Status uint64 `db:"status"`
+}
+
+type EthTransactionQ interface {
+	New() EthTransactionQ
+
+	Select() ([]EthTransaction, error)
+	InsertBatch(transactions []EthTransaction) error
+	DeleteByBlockNumber(blockNumber uint64) error
+
+	FilterByHash(hashes ...string) EthTransactionQ
+}
@@ -0,0 +1,25 @@
+package data
+
+import "gitlab.com/distributed_lab/kit/pgdb"
+
+// FeeParams are the contract basisPointsRate and maximumFee set by a Params
+// event. LogIndex is -1 for the values read from the contract as a baseline.
+type FeeParams struct {
+	BlockNumber uint64 `db:"block_number"`
+	LogIndex    int64  `db:"log_index"`
+	BasisPoints string `db:"basis_points"`
+	MaximumFee  string `db:"maximum_fee"`
+}
+
+type FeeParamsQ interface {
+	New() FeeParamsQ
+
+	Select() ([]FeeParams, error)
+	// Latest returns the parameters in effect after the last processed block
+	// or nil if the history is empty
+	Latest() (*FeeParams, error)
+	InsertBatch(params []FeeParams) error
+	DeleteByBlockNumber(blockNumber uint64) error
+
+	Page(pageParams *pgdb.OffsetPageParams) FeeParamsQ
+}
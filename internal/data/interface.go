@@ -7,19 +7,26 @@ import (
 )
 
 type USDTTransfer struct {
-    ID              int64     `db:"id"`
-    FromAddress     string    `db:"from_address"`
-    ToAddress       string    `db:"to_address"`
-    Amount          string    `db:"amount"`
-    TransactionHash string    `db:"transaction_hash"`
-    BlockNumber     uint64    `db:"block_number"`
-    LogIndex        uint64    `db:"log_index"`
-    Timestamp       time.Time `db:"timestamp"`
+    ID              int64         `db:"id"`
+    FromAddress     string        `db:"from_address"`
+    ToAddress       string        `db:"to_address"`
+    Amount          string        `db:"amount"`
+    TransactionHash string        `db:"transaction_hash"`
+    BlockNumber     uint64        `db:"block_number"`
+    LogIndex        uint64        `db:"log_index"`
+    Timestamp       time.Time     `db:"timestamp"`
+    Flags           TransferFlags `db:"flags"`
+    // Fee charged by the contract on top of Amount, paid by a separate
+    // transfer to the owner
+    Fee string `db:"fee"`
+    // PrincipalLogIndex is set on fee transfers to the principal transfer
+    PrincipalLogIndex *int64 `db:"principal_log_index"`
 }
 
 type LastProcessedBlock struct {
     ID          int64  `db:"id"`
     BlockNumber uint64 `db:"block_number"`
+    Paused      bool   `db:"paused"`
 }
 
 type USDTTransferQ interface {
@@ -27,19 +34,38 @@ type USDTTransferQ interface {
 
     Get() (*USDTTransfer, error)
     Select() ([]USDTTransfer, error)
+    // Each reads the selected transfers through a server-side cursor and
+    // passes them to fn in batches of batchSize, so only one batch is held
+    // in memory at a time. It must be called on a Q of a transaction, the
+    // cursor lives until the transaction ends.
+    Each(batchSize uint64, fn func(batch []USDTTransfer) error) error
     Insert(transfer USDTTransfer) (*USDTTransfer, error)
     InsertIgnore(transfer USDTTransfer) (*USDTTransfer, error)
     InsertBlock(transfer []USDTTransfer) error
     DeleteLastProcessedBlock(blockNumber uint64) error
+    // DeleteBlockRange deletes transfers of the blocks, both ends included
+    DeleteBlockRange(fromBlock, toBlock uint64) error
     Update(transfer USDTTransfer) (*USDTTransfer, error)
 
     FilterByID(id int64) USDTTransferQ
     FilterByFromAddress(address string) USDTTransferQ
     FilterByToAddress(address string) USDTTransferQ
     FilterByBlockNumber(blockNumber uint64) USDTTransferQ
+    FilterByBlockRange(fromBlock, toBlock uint64) USDTTransferQ
     FilterByTransactionHash(hash string) USDTTransferQ
+    FilterByMaxTimestamp(timestamp time.Time) USDTTransferQ
+    FilterByAddress(address string) USDTTransferQ
+    FilterByMinAmount(amount string) USDTTransferQ
+    FilterByMaxAmount(amount string) USDTTransferQ
+    // FilterAfterCursor keeps transfers placed after the (block, log index) position
+    FilterAfterCursor(blockNumber uint64, logIndex int64) USDTTransferQ
+    // FilterByWatchlist and FilterByLabel keep transfers whose sender,
+    // receiver or either of them, per direction, is in the address set
+    FilterByWatchlist(watchlistID int64, direction string) USDTTransferQ
+    FilterByLabel(label string, direction string) USDTTransferQ
     
     OrderByTimestamp(desc bool) USDTTransferQ
+    OrderByCursor() USDTTransferQ
     Limit(limit uint64) USDTTransferQ
     Offset(offset uint64) USDTTransferQ
 
@@ -51,4 +77,8 @@ type LastProcessedBlockQ interface {
 
     Get() (uint64, error)
     Update(blockNumber uint64) error
+
+    // Paused reports whether the listener was paused from the admin API
+    Paused() (bool, error)
+    SetPaused(paused bool) error
 }
\ No newline at end of file
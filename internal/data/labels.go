@@ -0,0 +1,78 @@
+package data
+
+import (
+	"time"
+
+	"gitlab.com/distributed_lab/kit/pgdb"
+)
+
+// Sides of a transfer matched by the address set filters
+const (
+	DirectionFrom = "from"
+	DirectionTo   = "to"
+	DirectionAny  = "any"
+)
+
+// AddressLabel is a human readable name of an address, e.g. "Treasury"
+type AddressLabel struct {
+	Address   string    `db:"address"`
+	Label     string    `db:"label"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+type Watchlist struct {
+	ID          int64     `db:"id"`
+	Name        string    `db:"name"`
+	Description string    `db:"description"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+type WatchlistAddress struct {
+	WatchlistID int64     `db:"watchlist_id"`
+	Address     string    `db:"address"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+type AddressLabelQ interface {
+	New() AddressLabelQ
+
+	Get() (*AddressLabel, error)
+	Select() ([]AddressLabel, error)
+	// Upsert sets labels of the addresses, replacing existing ones
+	Upsert(labels ...AddressLabel) error
+	Delete() error
+
+	FilterByAddress(addresses ...string) AddressLabelQ
+	FilterByLabel(label string) AddressLabelQ
+
+	Page(pageParams *pgdb.OffsetPageParams) AddressLabelQ
+}
+
+type WatchlistQ interface {
+	New() WatchlistQ
+
+	Get() (*Watchlist, error)
+	Select() ([]Watchlist, error)
+	Insert(watchlist Watchlist) (*Watchlist, error)
+	Delete() error
+
+	FilterByID(ids ...int64) WatchlistQ
+	FilterByName(name string) WatchlistQ
+
+	Page(pageParams *pgdb.OffsetPageParams) WatchlistQ
+}
+
+type WatchlistAddressQ interface {
+	New() WatchlistAddressQ
+
+	Select() ([]WatchlistAddress, error)
+	// Insert adds the addresses to the watchlist, skipping present ones
+	Insert(watchlistID int64, addresses ...string) error
+	Delete() error
+
+	FilterByWatchlistID(watchlistID int64) WatchlistAddressQ
+	FilterByAddress(addresses ...string) WatchlistAddressQ
+
+	Page(pageParams *pgdb.OffsetPageParams) WatchlistAddressQ
+}
@@ -1,11 +1,57 @@
 package data
 
+import "context"
+
 type MasterQ interface {
 	New() MasterQ
+	// WithContext returns the master Q running its queries within ctx, it
+	// shares the connection and the open transaction with the original one
+	WithContext(ctx context.Context) MasterQ
 
 	USDTTransfer() USDTTransferQ
+	TransferArchive() TransferArchiveQ
+	TransferPartition() TransferPartitionQ
 
 	LastProcessedBlock() LastProcessedBlockQ
+	Block() BlockQ
+
+	Balance() BalanceQ
+	BalanceChange() BalanceChangeQ
+	BalanceCheckpoint() BalanceCheckpointQ
+	BalanceDiscrepancy() BalanceDiscrepancyQ
+
+	TransferStats() TransferStatsQ
+
+	Webhook() WebhookQ
+	WebhookDelivery() WebhookDeliveryQ
+
+	PublisherPosition() PublisherPositionQ
+	PublisherRetraction() PublisherRetractionQ
+
+	AlertRule() AlertRuleQ
+	Alert() AlertQ
+
+	AddressLabel() AddressLabelQ
+	Watchlist() WatchlistQ
+	WatchlistAddress() WatchlistAddressQ
+
+	SanctionsList() SanctionsListQ
+	BlacklistEvent() BlacklistEventQ
+	Screening() ScreeningQ
+
+	SupplyChange() SupplyChangeQ
+	Supply() SupplyQ
+	SupplyCheckpoint() SupplyCheckpointQ
+
+	FeeParams() FeeParamsQ
+
+	USDTContract() USDTContractQ
+
+	EthTransaction() EthTransactionQ
+
+	ReingestionJob() ReingestionJobQ
+	AuditLog() AuditLogQ
+	APIKey() APIKeyQ
 
 	Transaction(fn func(db MasterQ) error) error
 }
\ No newline at end of file
@@ -0,0 +1,106 @@
+package pg
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	sq "github.com/Masterminds/squirrel"
+	"gitlab.com/distributed_lab/kit/pgdb"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const addressLabelsTableName = "address_labels"
+
+// upsertBatchSize keeps batch statements well below the postgres limit of
+// 65535 bind parameters
+const upsertBatchSize = 10000
+
+func NewAddressLabelQ(db *DB) data.AddressLabelQ {
+	return &addressLabelQ{
+		db:  db,
+		sql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar).Select("*").From(addressLabelsTableName),
+		del: sq.Delete(addressLabelsTableName),
+	}
+}
+
+type addressLabelQ struct {
+	db  *DB
+	sql sq.SelectBuilder
+	del sq.DeleteBuilder
+}
+
+func (q *addressLabelQ) New() data.AddressLabelQ {
+	return NewAddressLabelQ(q.db)
+}
+
+func (q *addressLabelQ) Get() (*data.AddressLabel, error) {
+	var result data.AddressLabel
+	err := q.db.Get(&result, q.sql)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get address label from db")
+	}
+	return &result, nil
+}
+
+func (q *addressLabelQ) Select() ([]data.AddressLabel, error) {
+	var result []data.AddressLabel
+	err := q.db.Select(&result, q.sql)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select address labels from db")
+	}
+	return result, nil
+}
+
+func (q *addressLabelQ) Upsert(labels ...data.AddressLabel) error {
+	for start := 0; start < len(labels); start += upsertBatchSize {
+		end := start + upsertBatchSize
+		if end > len(labels) {
+			end = len(labels)
+		}
+
+		batch := labels[start:end]
+		values := make([]interface{}, 0, len(batch)*2)
+		placeholders := make([]string, 0, len(batch))
+
+		for i, label := range batch {
+			values = append(values, label.Address, label.Label)
+			placeholders = append(placeholders, fmt.Sprintf("($%d, $%d)", i*2+1, i*2+2))
+		}
+
+		query := `INSERT INTO address_labels (address, label) VALUES ` + strings.Join(placeholders, ", ") + `
+                  ON CONFLICT (address) DO UPDATE
+                  SET label = EXCLUDED.label, updated_at = (NOW() AT TIME ZONE 'utc')`
+
+		if err := q.db.ExecRaw(query, values...); err != nil {
+			return errors.Wrap(err, "failed to upsert address labels")
+		}
+	}
+	return nil
+}
+
+func (q *addressLabelQ) Delete() error {
+	err := q.db.Exec(q.del)
+	return errors.Wrap(err, "failed to delete address labels from db")
+}
+
+func (q *addressLabelQ) FilterByAddress(addresses ...string) data.AddressLabelQ {
+	q.sql = q.sql.Where(sq.Eq{"address": addresses})
+	q.del = q.del.Where(sq.Eq{"address": addresses})
+	return q
+}
+
+func (q *addressLabelQ) FilterByLabel(label string) data.AddressLabelQ {
+	q.sql = q.sql.Where(sq.Eq{"label": label})
+	q.del = q.del.Where(sq.Eq{"label": label})
+	return q
+}
+
+func (q *addressLabelQ) Page(pageParams *pgdb.OffsetPageParams) data.AddressLabelQ {
+	q.sql = pageParams.ApplyTo(q.sql, "address")
+	return q
+}
@@ -0,0 +1,57 @@
+package pg
+
+import (
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	sq "github.com/Masterminds/squirrel"
+	"gitlab.com/distributed_lab/kit/pgdb"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const adminAuditLogTableName = "admin_audit_log"
+
+func NewAuditLogQ(db *DB) data.AuditLogQ {
+	return &auditLogQ{
+		db:  db,
+		sql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar).Select("*").From(adminAuditLogTableName),
+	}
+}
+
+type auditLogQ struct {
+	db  *DB
+	sql sq.SelectBuilder
+}
+
+func (q *auditLogQ) New() data.AuditLogQ {
+	return NewAuditLogQ(q.db)
+}
+
+func (q *auditLogQ) Select() ([]data.AuditEntry, error) {
+	var result []data.AuditEntry
+	err := q.db.Select(&result, q.sql)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select audit log entries from db")
+	}
+	return result, nil
+}
+
+func (q *auditLogQ) Insert(entry data.AuditEntry) error {
+	clauses := map[string]interface{}{
+		"action":      entry.Action,
+		"details":     entry.Details,
+		"remote_addr": entry.RemoteAddr,
+		"user_agent":  entry.UserAgent,
+		"api_key_id":  entry.APIKeyID,
+	}
+	err := q.db.Exec(sq.Insert(adminAuditLogTableName).SetMap(clauses))
+	return errors.Wrap(err, "failed to insert audit log entry to db")
+}
+
+func (q *auditLogQ) FilterByAction(action string) data.AuditLogQ {
+	q.sql = q.sql.Where(sq.Eq{"action": action})
+	return q
+}
+
+func (q *auditLogQ) Page(pageParams *pgdb.OffsetPageParams) data.AuditLogQ {
+	q.sql = pageParams.ApplyTo(q.sql, "id")
+	return q
+}
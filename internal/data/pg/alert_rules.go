@@ -0,0 +1,86 @@
+package pg
+
+import (
+	"database/sql"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	sq "github.com/Masterminds/squirrel"
+	"gitlab.com/distributed_lab/kit/pgdb"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const alertRulesTableName = "alert_rules"
+
+func NewAlertRuleQ(db *DB) data.AlertRuleQ {
+	return &alertRuleQ{
+		db:  db,
+		sql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar).Select("*").From(alertRulesTableName),
+		del: sq.Delete(alertRulesTableName),
+	}
+}
+
+type alertRuleQ struct {
+	db  *DB
+	sql sq.SelectBuilder
+	del sq.DeleteBuilder
+}
+
+func (q *alertRuleQ) New() data.AlertRuleQ {
+	return NewAlertRuleQ(q.db)
+}
+
+func (q *alertRuleQ) Get() (*data.AlertRule, error) {
+	var result data.AlertRule
+	err := q.db.Get(&result, q.sql)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get alert rule from db")
+	}
+	return &result, nil
+}
+
+func (q *alertRuleQ) Select() ([]data.AlertRule, error) {
+	var result []data.AlertRule
+	err := q.db.Select(&result, q.sql)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select alert rules from db")
+	}
+	return result, nil
+}
+
+func (q *alertRuleQ) Insert(rule data.AlertRule) (*data.AlertRule, error) {
+	clauses := map[string]interface{}{
+		"name":           rule.Name,
+		"kind":           rule.Kind,
+		"min_amount":     rule.MinAmount,
+		"addresses":      rule.Addresses,
+		"transfer_count": rule.TransferCount,
+		"time_window":    rule.TimeWindow,
+		"notifiers":      rule.Notifiers,
+	}
+	var result data.AlertRule
+	stmt := sq.Insert(alertRulesTableName).SetMap(clauses).Suffix("RETURNING *")
+	err := q.db.Get(&result, stmt)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to insert alert rule to db")
+	}
+	return &result, nil
+}
+
+func (q *alertRuleQ) Delete() error {
+	err := q.db.Exec(q.del)
+	return errors.Wrap(err, "failed to delete alert rules from db")
+}
+
+func (q *alertRuleQ) FilterByID(ids ...int64) data.AlertRuleQ {
+	q.sql = q.sql.Where(sq.Eq{"id": ids})
+	q.del = q.del.Where(sq.Eq{"id": ids})
+	return q
+}
+
+func (q *alertRuleQ) Page(pageParams *pgdb.OffsetPageParams) data.AlertRuleQ {
+	q.sql = pageParams.ApplyTo(q.sql, "id")
+	return q
+}
@@ -0,0 +1,172 @@
+package pg
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	sq "github.com/Masterminds/squirrel"
+	"gitlab.com/distributed_lab/kit/pgdb"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const alertsTableName = "alerts"
+
+func NewAlertQ(db *DB) data.AlertQ {
+	return &alertQ{
+		db:  db,
+		sql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar).Select("*").From(alertsTableName),
+	}
+}
+
+type alertQ struct {
+	db  *DB
+	sql sq.SelectBuilder
+}
+
+func (q *alertQ) New() data.AlertQ {
+	return NewAlertQ(q.db)
+}
+
+func (q *alertQ) Get() (*data.Alert, error) {
+	var result data.Alert
+	err := q.db.Get(&result, q.sql)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get alert from db")
+	}
+	return &result, nil
+}
+
+func (q *alertQ) Select() ([]data.Alert, error) {
+	var result []data.Alert
+	err := q.db.Select(&result, q.sql)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select alerts from db")
+	}
+	return result, nil
+}
+
+func (q *alertQ) EvaluateBlock(blockNumber uint64) error {
+	// Amount and address rules match single transfers, the alert is raised
+	// for the watched party, the sender if both or none are watched
+	transferRules := `INSERT INTO alerts (rule_id, address, amount, transfer_count, transaction_hash, block_number, log_index, timestamp)
+              SELECT r.id,
+                     CASE WHEN cardinality(r.addresses) = 0 OR t.from_address::TEXT = ANY(r.addresses)
+                          THEN t.from_address ELSE t.to_address END,
+                     t.amount, 1, t.transaction_hash, t.block_number, t.log_index, t.timestamp
+              FROM usdt_transfers t
+              JOIN alert_rules r ON r.kind IN ('amount', 'address')
+                                AND (cardinality(r.addresses) = 0
+                                     OR t.from_address::TEXT = ANY(r.addresses)
+                                     OR t.to_address::TEXT = ANY(r.addresses))
+                                AND (r.min_amount IS NULL OR t.amount >= r.min_amount)
+              WHERE t.block_number = ?
+              ORDER BY t.log_index, r.id`
+
+	if err := q.db.ExecRaw(transferRules, blockNumber); err != nil {
+		return errors.Wrap(err, "failed to evaluate transfer alert rules")
+	}
+
+	// Velocity rules are checked once per sender of the block against the
+	// window ending at its latest transfer. A sender already alerted within
+	// the window is not alerted again until the window passes.
+	velocityRules := `INSERT INTO alerts (rule_id, address, amount, transfer_count, transaction_hash, block_number, log_index, timestamp)
+              SELECT r.id, b.from_address, w.amount, w.transfer_count, b.transaction_hash, b.block_number, b.log_index, b.timestamp
+              FROM alert_rules r
+              JOIN LATERAL (
+                  SELECT DISTINCT ON (t.from_address) t.*
+                  FROM usdt_transfers t
+                  WHERE t.block_number = ?
+                    AND (cardinality(r.addresses) = 0 OR t.from_address::TEXT = ANY(r.addresses))
+                    AND (r.min_amount IS NULL OR t.amount >= r.min_amount)
+                  ORDER BY t.from_address, t.log_index DESC
+              ) b ON TRUE
+              JOIN LATERAL (
+                  SELECT COUNT(*) AS transfer_count, SUM(t.amount) AS amount
+                  FROM usdt_transfers t
+                  WHERE t.from_address = b.from_address
+                    AND t.block_number <= b.block_number
+                    AND t.timestamp > b.timestamp - make_interval(secs => r.time_window)
+                    AND (r.min_amount IS NULL OR t.amount >= r.min_amount)
+              ) w ON w.transfer_count >= r.transfer_count
+              WHERE r.kind = 'velocity'
+                AND NOT EXISTS (
+                    SELECT 1 FROM alerts a
+                    WHERE a.rule_id = r.id
+                      AND a.address = b.from_address
+                      AND a.timestamp > b.timestamp - make_interval(secs => r.time_window))
+              ORDER BY b.log_index, r.id`
+
+	if err := q.db.ExecRaw(velocityRules, blockNumber); err != nil {
+		return errors.Wrap(err, "failed to evaluate velocity alert rules")
+	}
+	return nil
+}
+
+func (q *alertQ) DeleteByBlockNumber(blockNumber uint64) error {
+	err := q.db.Exec(sq.Delete(alertsTableName).Where(sq.Eq{"block_number": blockNumber}))
+	return errors.Wrap(err, "failed to delete alerts")
+}
+
+func (q *alertQ) MarkNotified(id int64) error {
+	stmt := sq.Update(alertsTableName).
+		SetMap(map[string]interface{}{
+			"attempts":    sq.Expr("attempts + 1"),
+			"notified_at": time.Now().UTC(),
+			"last_error":  nil,
+		}).
+		Where(sq.Eq{"id": id})
+
+	err := q.db.Exec(stmt)
+	return errors.Wrap(err, "failed to mark alert as notified")
+}
+
+func (q *alertQ) MarkFailed(id int64, reason string) error {
+	stmt := sq.Update(alertsTableName).
+		SetMap(map[string]interface{}{
+			"attempts":   sq.Expr("attempts + 1"),
+			"last_error": reason,
+		}).
+		Where(sq.Eq{"id": id})
+
+	err := q.db.Exec(stmt)
+	return errors.Wrap(err, "failed to mark alert notification as failed")
+}
+
+func (q *alertQ) FilterByID(id int64) data.AlertQ {
+	q.sql = q.sql.Where(sq.Eq{"id": id})
+	return q
+}
+
+func (q *alertQ) FilterByRuleID(ruleID int64) data.AlertQ {
+	q.sql = q.sql.Where(sq.Eq{"rule_id": ruleID})
+	return q
+}
+
+func (q *alertQ) FilterByAddress(address string) data.AlertQ {
+	q.sql = q.sql.Where(sq.Eq{"address": address})
+	return q
+}
+
+func (q *alertQ) FilterPending(maxAttempts int) data.AlertQ {
+	q.sql = q.sql.Where(sq.Eq{"notified_at": nil}).Where(sq.Lt{"attempts": maxAttempts})
+	return q
+}
+
+func (q *alertQ) OrderByID() data.AlertQ {
+	q.sql = q.sql.OrderBy("id ASC")
+	return q
+}
+
+func (q *alertQ) Limit(limit uint64) data.AlertQ {
+	q.sql = q.sql.Limit(limit)
+	return q
+}
+
+func (q *alertQ) Page(pageParams *pgdb.OffsetPageParams) data.AlertQ {
+	q.sql = pageParams.ApplyTo(q.sql, "id")
+	return q
+}
@@ -0,0 +1,102 @@
+package pg
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	sq "github.com/Masterminds/squirrel"
+	"gitlab.com/distributed_lab/kit/pgdb"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const apiKeysTableName = "api_keys"
+
+func NewAPIKeyQ(db *DB) data.APIKeyQ {
+	return &apiKeyQ{
+		db:  db,
+		sql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar).Select("*").From(apiKeysTableName),
+		upd: sq.Update(apiKeysTableName),
+	}
+}
+
+type apiKeyQ struct {
+	db  *DB
+	sql sq.SelectBuilder
+	upd sq.UpdateBuilder
+}
+
+func (q *apiKeyQ) New() data.APIKeyQ {
+	return NewAPIKeyQ(q.db)
+}
+
+func (q *apiKeyQ) Get() (*data.APIKey, error) {
+	var result data.APIKey
+	err := q.db.Get(&result, q.sql)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get API key from db")
+	}
+	return &result, nil
+}
+
+func (q *apiKeyQ) Select() ([]data.APIKey, error) {
+	var result []data.APIKey
+	err := q.db.Select(&result, q.sql)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select API keys from db")
+	}
+	return result, nil
+}
+
+func (q *apiKeyQ) Insert(key data.APIKey) (*data.APIKey, error) {
+	clauses := map[string]interface{}{
+		"name":     key.Name,
+		"key_hash": key.KeyHash,
+		"prefix":   key.Prefix,
+		"scopes":   key.Scopes,
+		"rate":     key.Rate,
+		"burst":    key.Burst,
+	}
+	var result data.APIKey
+	stmt := sq.Insert(apiKeysTableName).SetMap(clauses).Suffix("RETURNING *")
+	err := q.db.Get(&result, stmt)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to insert API key to db")
+	}
+	return &result, nil
+}
+
+func (q *apiKeyQ) Revoke() error {
+	stmt := q.upd.
+		Set("revoked_at", time.Now().UTC()).
+		Where(sq.Eq{"revoked_at": nil})
+
+	err := q.db.Exec(stmt)
+	return errors.Wrap(err, "failed to revoke API keys")
+}
+
+func (q *apiKeyQ) FilterByID(id int64) data.APIKeyQ {
+	q.sql = q.sql.Where(sq.Eq{"id": id})
+	q.upd = q.upd.Where(sq.Eq{"id": id})
+	return q
+}
+
+func (q *apiKeyQ) FilterByHash(hash string) data.APIKeyQ {
+	q.sql = q.sql.Where(sq.Eq{"key_hash": hash})
+	q.upd = q.upd.Where(sq.Eq{"key_hash": hash})
+	return q
+}
+
+func (q *apiKeyQ) FilterActive() data.APIKeyQ {
+	q.sql = q.sql.Where(sq.Eq{"revoked_at": nil})
+	q.upd = q.upd.Where(sq.Eq{"revoked_at": nil})
+	return q
+}
+
+func (q *apiKeyQ) Page(pageParams *pgdb.OffsetPageParams) data.APIKeyQ {
+	q.sql = pageParams.ApplyTo(q.sql, "id")
+	return q
+}
@@ -0,0 +1,98 @@
+package pg
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	sq "github.com/Masterminds/squirrel"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const balanceChangesTableName = "balance_changes"
+
+func NewBalanceChangeQ(db *DB) data.BalanceChangeQ {
+	return &balanceChangeQ{
+		db:  db,
+		sql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar).Select("*").From(balanceChangesTableName),
+	}
+}
+
+type balanceChangeQ struct {
+	db  *DB
+	sql sq.SelectBuilder
+}
+
+func (q *balanceChangeQ) New() data.BalanceChangeQ {
+	return NewBalanceChangeQ(q.db)
+}
+
+func (q *balanceChangeQ) Select() ([]data.BalanceChange, error) {
+	var result []data.BalanceChange
+	err := q.db.Select(&result, q.sql.OrderBy("block_number ASC", "log_index ASC"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select balance changes from db")
+	}
+	return result, nil
+}
+
+func (q *balanceChangeQ) Sum() (string, error) {
+	var result string
+	err := q.db.Get(&result, q.sql.RemoveColumns().Column("COALESCE(SUM(delta), 0)"))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to sum balance changes")
+	}
+	return result, nil
+}
+
+func (q *balanceChangeQ) InsertBatch(changes []data.BalanceChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	values := make([]interface{}, 0, len(changes)*4)
+	placeholders := make([]string, 0, len(changes))
+
+	for i, change := range changes {
+		values = append(values,
+			change.Address,
+			change.Delta,
+			change.BlockNumber,
+			change.LogIndex,
+		)
+		placeholders = append(placeholders, fmt.Sprintf("($%d, $%d, $%d, $%d)",
+			i*4+1, i*4+2, i*4+3, i*4+4,
+		))
+	}
+
+	query := `INSERT INTO balance_changes (address, delta, block_number, log_index) VALUES ` +
+		strings.Join(placeholders, ", ")
+
+	if err := q.db.ExecRaw(query, values...); err != nil {
+		return errors.Wrap(err, "failed to insert balance changes")
+	}
+	return nil
+}
+
+func (q *balanceChangeQ) DeleteByBlockNumber(blockNumber uint64) error {
+	err := q.db.Exec(sq.Delete(balanceChangesTableName).Where(sq.Eq{"block_number": blockNumber}))
+	return errors.Wrap(err, "failed to delete balance changes for block")
+}
+
+func (q *balanceChangeQ) FilterByAddress(addresses ...string) data.BalanceChangeQ {
+	q.sql = q.sql.Where(sq.Eq{"address": addresses})
+	return q
+}
+
+func (q *balanceChangeQ) FilterByBlockNumber(blockNumber uint64) data.BalanceChangeQ {
+	q.sql = q.sql.Where(sq.Eq{"block_number": blockNumber})
+	return q
+}
+
+func (q *balanceChangeQ) FilterByBlockRange(fromBlock, toBlock uint64) data.BalanceChangeQ {
+	q.sql = q.sql.Where(sq.And{
+		sq.GtOrEq{"block_number": fromBlock},
+		sq.LtOrEq{"block_number": toBlock},
+	})
+	return q
+}
@@ -0,0 +1,65 @@
+package pg
+
+import (
+	"database/sql"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	sq "github.com/Masterminds/squirrel"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const balanceCheckpointsTableName = "balance_checkpoints"
+
+func NewBalanceCheckpointQ(db *DB) data.BalanceCheckpointQ {
+	return &balanceCheckpointQ{
+		db:  db,
+		sql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar).Select("*").From(balanceCheckpointsTableName),
+	}
+}
+
+type balanceCheckpointQ struct {
+	db  *DB
+	sql sq.SelectBuilder
+}
+
+func (q *balanceCheckpointQ) New() data.BalanceCheckpointQ {
+	return NewBalanceCheckpointQ(q.db)
+}
+
+func (q *balanceCheckpointQ) Latest() (*data.BalanceCheckpoint, error) {
+	var result data.BalanceCheckpoint
+	err := q.db.Get(&result, q.sql.OrderBy("block_number DESC").Limit(1))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get balance checkpoint from db")
+	}
+	return &result, nil
+}
+
+func (q *balanceCheckpointQ) Snapshot(blockNumber, sinceBlock uint64) error {
+	query := `INSERT INTO balance_checkpoints (address, block_number, balance)
+              SELECT address, ?, balance FROM balances WHERE updated_block > ?
+              ON CONFLICT (address, block_number) DO UPDATE SET balance = EXCLUDED.balance`
+
+	if err := q.db.ExecRaw(query, blockNumber, sinceBlock); err != nil {
+		return errors.Wrap(err, "failed to snapshot balances")
+	}
+	return nil
+}
+
+func (q *balanceCheckpointQ) DeleteByBlockNumber(blockNumber uint64) error {
+	err := q.db.Exec(sq.Delete(balanceCheckpointsTableName).Where(sq.Eq{"block_number": blockNumber}))
+	return errors.Wrap(err, "failed to delete balance checkpoints for block")
+}
+
+func (q *balanceCheckpointQ) FilterByAddress(address string) data.BalanceCheckpointQ {
+	q.sql = q.sql.Where(sq.Eq{"address": address})
+	return q
+}
+
+func (q *balanceCheckpointQ) FilterByMaxBlockNumber(blockNumber uint64) data.BalanceCheckpointQ {
+	q.sql = q.sql.Where(sq.LtOrEq{"block_number": blockNumber})
+	return q
+}
@@ -0,0 +1,61 @@
+package pg
+
+import (
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	sq "github.com/Masterminds/squirrel"
+	"gitlab.com/distributed_lab/kit/pgdb"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const balanceDiscrepanciesTableName = "balance_discrepancies"
+
+func NewBalanceDiscrepancyQ(db *DB) data.BalanceDiscrepancyQ {
+	return &balanceDiscrepancyQ{
+		db:  db,
+		sql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar).Select("*").From(balanceDiscrepanciesTableName),
+	}
+}
+
+type balanceDiscrepancyQ struct {
+	db  *DB
+	sql sq.SelectBuilder
+}
+
+func (q *balanceDiscrepancyQ) New() data.BalanceDiscrepancyQ {
+	return NewBalanceDiscrepancyQ(q.db)
+}
+
+func (q *balanceDiscrepancyQ) Select() ([]data.BalanceDiscrepancy, error) {
+	var result []data.BalanceDiscrepancy
+	err := q.db.Select(&result, q.sql)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select balance discrepancies from db")
+	}
+	return result, nil
+}
+
+func (q *balanceDiscrepancyQ) Insert(discrepancy data.BalanceDiscrepancy) (*data.BalanceDiscrepancy, error) {
+	clauses := map[string]interface{}{
+		"address":         discrepancy.Address,
+		"block_number":    discrepancy.BlockNumber,
+		"indexed_balance": discrepancy.IndexedBalance,
+		"onchain_balance": discrepancy.OnchainBalance,
+	}
+	var result data.BalanceDiscrepancy
+	stmt := sq.Insert(balanceDiscrepanciesTableName).SetMap(clauses).Suffix("RETURNING *")
+	err := q.db.Get(&result, stmt)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to insert balance discrepancy to db")
+	}
+	return &result, nil
+}
+
+func (q *balanceDiscrepancyQ) FilterByAddress(address string) data.BalanceDiscrepancyQ {
+	q.sql = q.sql.Where(sq.Eq{"address": address})
+	return q
+}
+
+func (q *balanceDiscrepancyQ) Page(pageParams *pgdb.OffsetPageParams) data.BalanceDiscrepancyQ {
+	q.sql = pageParams.ApplyTo(q.sql, "id")
+	return q
+}
@@ -0,0 +1,155 @@
+package pg
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	sq "github.com/Masterminds/squirrel"
+	"github.com/ethereum/go-ethereum/common"
+	"gitlab.com/distributed_lab/kit/pgdb"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const balancesTableName = "balances"
+
+func NewBalanceQ(db *DB) data.BalanceQ {
+	return &balanceQ{
+		db:  db,
+		sql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar).Select("*").From(balancesTableName),
+	}
+}
+
+type balanceQ struct {
+	db  *DB
+	sql sq.SelectBuilder
+	// sample is the number of rows to sample, zero to select all of them
+	sample uint64
+}
+
+func (q *balanceQ) New() data.BalanceQ {
+	return NewBalanceQ(q.db)
+}
+
+func (q *balanceQ) Get() (*data.Balance, error) {
+	var result data.Balance
+	err := q.db.Get(&result, q.sql)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get balance from db")
+	}
+	return &result, nil
+}
+
+func (q *balanceQ) Select() ([]data.Balance, error) {
+	if q.sample != 0 {
+		return q.selectSample()
+	}
+
+	var result []data.Balance
+	err := q.db.Select(&result, q.sql)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select balances from db")
+	}
+	return result, nil
+}
+
+// selectSample reads the rows following a random address by the primary key,
+// wrapping around to the first ones. Addresses are uniformly distributed, so
+// that is a random sample which doesn't sort the whole table.
+func (q *balanceQ) selectSample() ([]data.Balance, error) {
+	var start common.Address
+	if _, err := rand.Read(start[:]); err != nil {
+		return nil, errors.Wrap(err, "failed to generate sample start")
+	}
+
+	var result []data.Balance
+	err := q.db.Select(&result, q.sql.Where(sq.GtOrEq{"address": start.Hex()}).OrderBy("address").Limit(q.sample))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select balances from db")
+	}
+	if uint64(len(result)) == q.sample {
+		return result, nil
+	}
+
+	var wrapped []data.Balance
+	err = q.db.Select(&wrapped, q.sql.Where(sq.Lt{"address": start.Hex()}).OrderBy("address").Limit(q.sample-uint64(len(result))))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select balances from db")
+	}
+	return append(result, wrapped...), nil
+}
+
+func (q *balanceQ) ApplyBlock(blockNumber uint64) error {
+	query := `INSERT INTO balances (address, balance, updated_block)
+              SELECT address, SUM(delta), block_number FROM balance_changes
+              WHERE block_number = ? GROUP BY address, block_number
+              ON CONFLICT (address) DO UPDATE
+              SET balance = balances.balance + EXCLUDED.balance,
+                  updated_block = EXCLUDED.updated_block`
+
+	if err := q.db.ExecRaw(query, blockNumber); err != nil {
+		return errors.Wrap(err, "failed to apply balance changes")
+	}
+	return nil
+}
+
+func (q *balanceQ) RevertBlock(blockNumber uint64) error {
+	query := `UPDATE balances SET balance = balances.balance - c.delta
+              FROM (SELECT address, SUM(delta) AS delta FROM balance_changes
+                    WHERE block_number = ? GROUP BY address) c
+              WHERE balances.address = c.address`
+
+	if err := q.db.ExecRaw(query, blockNumber); err != nil {
+		return errors.Wrap(err, "failed to revert balance changes")
+	}
+	return nil
+}
+
+func (q *balanceQ) Seed(baselines []data.BalanceChange) error {
+	if len(baselines) == 0 {
+		return nil
+	}
+
+	values := make([]interface{}, 0, len(baselines)*3)
+	placeholders := make([]string, 0, len(baselines))
+
+	for i, baseline := range baselines {
+		values = append(values, baseline.Address, baseline.Delta, baseline.BlockNumber)
+		placeholders = append(placeholders, fmt.Sprintf("($%d, $%d, $%d)", i*3+1, i*3+2, i*3+3))
+	}
+
+	query := `INSERT INTO balances (address, balance, updated_block) VALUES ` +
+		strings.Join(placeholders, ", ") + `
+              ON CONFLICT (address) DO UPDATE
+              SET balance = balances.balance + EXCLUDED.balance`
+
+	if err := q.db.ExecRaw(query, values...); err != nil {
+		return errors.Wrap(err, "failed to seed balances")
+	}
+	return nil
+}
+
+func (q *balanceQ) FilterByAddress(address string) data.BalanceQ {
+	q.sql = q.sql.Where(sq.Eq{"address": address})
+	return q
+}
+
+func (q *balanceQ) FilterPositive() data.BalanceQ {
+	q.sql = q.sql.Where(sq.Gt{"balance": 0})
+	return q
+}
+
+func (q *balanceQ) Sample(limit uint64) data.BalanceQ {
+	q.sample = limit
+	return q
+}
+
+func (q *balanceQ) Page(pageParams *pgdb.OffsetPageParams) data.BalanceQ {
+	q.sql = pageParams.ApplyTo(q.sql, "balance")
+	return q
+}
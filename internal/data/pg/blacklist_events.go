@@ -0,0 +1,60 @@
+package pg
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	sq "github.com/Masterminds/squirrel"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const blacklistEventsTableName = "blacklist_events"
+
+func NewBlacklistEventQ(db *DB) data.BlacklistEventQ {
+	return &blacklistEventQ{
+		db: db,
+	}
+}
+
+type blacklistEventQ struct {
+	db *DB
+}
+
+func (q *blacklistEventQ) New() data.BlacklistEventQ {
+	return NewBlacklistEventQ(q.db)
+}
+
+func (q *blacklistEventQ) InsertBatch(events []data.BlacklistEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	values := make([]interface{}, 0, len(events)*4)
+	placeholders := make([]string, 0, len(events))
+
+	for i, event := range events {
+		values = append(values,
+			event.Address,
+			event.Blacklisted,
+			event.BlockNumber,
+			event.LogIndex,
+		)
+		placeholders = append(placeholders, fmt.Sprintf("($%d, $%d, $%d, $%d)",
+			i*4+1, i*4+2, i*4+3, i*4+4,
+		))
+	}
+
+	query := `INSERT INTO blacklist_events (address, blacklisted, block_number, log_index) VALUES ` +
+		strings.Join(placeholders, ", ") + ` ON CONFLICT DO NOTHING`
+
+	if err := q.db.ExecRaw(query, values...); err != nil {
+		return errors.Wrap(err, "failed to insert blacklist events")
+	}
+	return nil
+}
+
+func (q *blacklistEventQ) DeleteByBlockNumber(blockNumber uint64) error {
+	err := q.db.Exec(sq.Delete(blacklistEventsTableName).Where(sq.Eq{"block_number": blockNumber}))
+	return errors.Wrap(err, "failed to delete blacklist events")
+}
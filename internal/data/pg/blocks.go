@@ -0,0 +1,86 @@
+package pg
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	sq "github.com/Masterminds/squirrel"
+	"gitlab.com/distributed_lab/kit/pgdb"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const blocksTableName = "blocks"
+
+func NewBlockQ(db *DB) data.BlockQ {
+	return &blockQ{
+		db:  db,
+		sql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar).Select("*").From(blocksTableName),
+	}
+}
+
+type blockQ struct {
+	db  *DB
+	sql sq.SelectBuilder
+}
+
+func (q *blockQ) New() data.BlockQ {
+	return NewBlockQ(q.db)
+}
+
+func (q *blockQ) Get() (*data.Block, error) {
+	var result data.Block
+	err := q.db.Get(&result, q.sql)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get block from db")
+	}
+	return &result, nil
+}
+
+func (q *blockQ) Select() ([]data.Block, error) {
+	var result []data.Block
+	err := q.db.Select(&result, q.sql)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select blocks from db")
+	}
+	return result, nil
+}
+
+func (q *blockQ) Insert(block data.Block) error {
+	query := `INSERT INTO blocks (number, hash, parent_hash, timestamp,
+                  transfer_count, volume, unique_senders, unique_receivers)
+              SELECT ?, ?, ?, ?, COUNT(*), COALESCE(SUM(amount), 0),
+                     COUNT(DISTINCT from_address), COUNT(DISTINCT to_address)
+              FROM usdt_transfers WHERE block_number = ?`
+
+	err := q.db.ExecRaw(query, block.Number, block.Hash, block.ParentHash, block.Timestamp, block.Number)
+	return errors.Wrap(err, "failed to insert block")
+}
+
+func (q *blockQ) Delete(number uint64) error {
+	err := q.db.Exec(sq.Delete(blocksTableName).Where(sq.Eq{"number": number}))
+	return errors.Wrap(err, "failed to delete block")
+}
+
+func (q *blockQ) FilterByNumber(number uint64) data.BlockQ {
+	q.sql = q.sql.Where(sq.Eq{"number": number})
+	return q
+}
+
+func (q *blockQ) FilterByMinTimestamp(timestamp time.Time) data.BlockQ {
+	q.sql = q.sql.Where(sq.GtOrEq{"timestamp": timestamp})
+	return q
+}
+
+func (q *blockQ) FilterByTimestampBefore(timestamp time.Time) data.BlockQ {
+	q.sql = q.sql.Where(sq.Lt{"timestamp": timestamp})
+	return q
+}
+
+func (q *blockQ) Page(pageParams *pgdb.OffsetPageParams) data.BlockQ {
+	q.sql = pageParams.ApplyTo(q.sql, "number")
+	return q
+}
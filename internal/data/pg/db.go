@@ -0,0 +1,110 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/tracing"
+	sq "github.com/Masterminds/squirrel"
+	"gitlab.com/distributed_lab/kit/pgdb"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DB runs the queries of the Qs within the context of their master Q, so
+// every query is recorded as a span of the operation that made it. Queries
+// made outside of a traced operation are not recorded.
+type DB struct {
+	*pgdb.DB
+	ctx context.Context
+}
+
+func (db *DB) Get(dest interface{}, query sq.Sqlizer) error {
+	ctx, span := db.startSqlizer(query)
+	err := db.DB.GetContext(ctx, dest, query)
+	db.end(span, err)
+	return err
+}
+
+func (db *DB) GetRaw(dest interface{}, query string, args ...interface{}) error {
+	ctx, span := db.start(query)
+	err := db.DB.GetRawContext(ctx, dest, query, args...)
+	db.end(span, err)
+	return err
+}
+
+func (db *DB) Select(dest interface{}, query sq.Sqlizer) error {
+	ctx, span := db.startSqlizer(query)
+	err := db.DB.SelectContext(ctx, dest, query)
+	db.end(span, err)
+	return err
+}
+
+func (db *DB) SelectRaw(dest interface{}, query string, args ...interface{}) error {
+	ctx, span := db.start(query)
+	err := db.DB.SelectRawContext(ctx, dest, query, args...)
+	db.end(span, err)
+	return err
+}
+
+func (db *DB) Exec(query sq.Sqlizer) error {
+	ctx, span := db.startSqlizer(query)
+	err := db.DB.ExecContext(ctx, query)
+	db.end(span, err)
+	return err
+}
+
+func (db *DB) ExecRaw(query string, args ...interface{}) error {
+	ctx, span := db.start(query)
+	err := db.DB.ExecRawContext(ctx, query, args...)
+	db.end(span, err)
+	return err
+}
+
+func (db *DB) ExecWithResult(query sq.Sqlizer) (sql.Result, error) {
+	ctx, span := db.startSqlizer(query)
+	result, err := db.DB.ExecWithResultContext(ctx, query)
+	db.end(span, err)
+	return result, err
+}
+
+func (db *DB) startSqlizer(query sq.Sqlizer) (context.Context, trace.Span) {
+	if !trace.SpanFromContext(db.ctx).IsRecording() {
+		return db.ctx, nil
+	}
+
+	statement, _, err := query.ToSql()
+	if err != nil {
+		// the query fails to build again right away
+		statement = ""
+	}
+	return db.start(statement)
+}
+
+// start names the span after the SQL operation, e.g. "db SELECT"
+func (db *DB) start(statement string) (context.Context, trace.Span) {
+	if !trace.SpanFromContext(db.ctx).IsRecording() {
+		return db.ctx, nil
+	}
+
+	operation := "query"
+	if fields := strings.Fields(statement); len(fields) > 0 {
+		operation = strings.ToUpper(fields[0])
+	}
+
+	return tracing.Start(db.ctx, "db "+operation,
+		semconv.DBSystemPostgreSQL,
+		semconv.DBQueryText(statement),
+	)
+}
+
+func (db *DB) end(span trace.Span, err error) {
+	if span == nil {
+		return
+	}
+	if err == sql.ErrNoRows {
+		err = nil
+	}
+	tracing.End(span, err)
+}
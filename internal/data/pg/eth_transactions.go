@@ -0,0 +1,82 @@
+package pg
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	sq "github.com/Masterminds/squirrel"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const transactionsTableName = "transactions"
+
+func NewEthTransactionQ(db *DB) data.EthTransactionQ {
+	return &ethTransactionQ{
+		db:  db,
+		sql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar).Select("*").From(transactionsTableName),
+	}
+}
+
+type ethTransactionQ struct {
+	db  *DB
+	sql sq.SelectBuilder
+}
+
+func (q *ethTransactionQ) New() data.EthTransactionQ {
+	return NewEthTransactionQ(q.db)
+}
+
+func (q *ethTransactionQ) Select() ([]data.EthTransaction, error) {
+	var result []data.EthTransaction
+	err := q.db.Select(&result, q.sql)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select transactions from db")
+	}
+	return result, nil
+}
+
+func (q *ethTransactionQ) InsertBatch(transactions []data.EthTransaction) error {
+	if len(transactions) == 0 {
+		return nil
+	}
+
+	values := make([]interface{}, 0, len(transactions)*9)
+	placeholders := make([]string, 0, len(transactions))
+
+	for i, tx := range transactions {
+		values = append(values,
+			tx.Hash,
+			tx.BlockNumber,
+			tx.FromAddress,
+			tx.ToAddress,
+			tx.MethodSelector,
+			tx.GasUsed,
+			tx.EffectiveGasPrice,
+			tx.GasFee,
+			tx.Status,
+		)
+		placeholders = append(placeholders, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)",
+			i*9+1, i*9+2, i*9+3, i*9+4, i*9+5, i*9+6, i*9+7, i*9+8, i*9+9,
+		))
+	}
+
+	query := `INSERT INTO transactions (hash, block_number, from_address, to_address, method_selector,
+                  gas_used, effective_gas_price, gas_fee, status) VALUES ` +
+		strings.Join(placeholders, ", ") + ` ON CONFLICT (hash) DO NOTHING`
+
+	if err := q.db.ExecRaw(query, values...); err != nil {
+		return errors.Wrap(err, "failed to insert transactions")
+	}
+	return nil
+}
+
+func (q *ethTransactionQ) DeleteByBlockNumber(blockNumber uint64) error {
+	err := q.db.Exec(sq.Delete(transactionsTableName).Where(sq.Eq{"block_number": blockNumber}))
+	return errors.Wrap(err, "failed to delete transactions")
+}
+
+func (q *ethTransactionQ) FilterByHash(hashes ...string) data.EthTransactionQ {
+	q.sql = q.sql.Where(sq.Eq{"hash": hashes})
+	return q
+}
@@ -0,0 +1,90 @@
+package pg
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	sq "github.com/Masterminds/squirrel"
+	"gitlab.com/distributed_lab/kit/pgdb"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const feeParamsTableName = "fee_params"
+
+func NewFeeParamsQ(db *DB) data.FeeParamsQ {
+	return &feeParamsQ{
+		db:  db,
+		sql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar).Select("*").From(feeParamsTableName),
+	}
+}
+
+type feeParamsQ struct {
+	db  *DB
+	sql sq.SelectBuilder
+}
+
+func (q *feeParamsQ) New() data.FeeParamsQ {
+	return NewFeeParamsQ(q.db)
+}
+
+func (q *feeParamsQ) Select() ([]data.FeeParams, error) {
+	var result []data.FeeParams
+	err := q.db.Select(&result, q.sql)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select fee params from db")
+	}
+	return result, nil
+}
+
+func (q *feeParamsQ) Latest() (*data.FeeParams, error) {
+	var result data.FeeParams
+	err := q.db.Get(&result, q.sql.OrderBy("block_number DESC", "log_index DESC").Limit(1))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get fee params from db")
+	}
+	return &result, nil
+}
+
+func (q *feeParamsQ) InsertBatch(params []data.FeeParams) error {
+	if len(params) == 0 {
+		return nil
+	}
+
+	values := make([]interface{}, 0, len(params)*4)
+	placeholders := make([]string, 0, len(params))
+
+	for i, p := range params {
+		values = append(values,
+			p.BlockNumber,
+			p.LogIndex,
+			p.BasisPoints,
+			p.MaximumFee,
+		)
+		placeholders = append(placeholders, fmt.Sprintf("($%d, $%d, $%d, $%d)",
+			i*4+1, i*4+2, i*4+3, i*4+4,
+		))
+	}
+
+	query := `INSERT INTO fee_params (block_number, log_index, basis_points, maximum_fee) VALUES ` +
+		strings.Join(placeholders, ", ")
+
+	if err := q.db.ExecRaw(query, values...); err != nil {
+		return errors.Wrap(err, "failed to insert fee params")
+	}
+	return nil
+}
+
+func (q *feeParamsQ) DeleteByBlockNumber(blockNumber uint64) error {
+	err := q.db.Exec(sq.Delete(feeParamsTableName).Where(sq.Eq{"block_number": blockNumber}))
+	return errors.Wrap(err, "failed to delete fee params")
+}
+
+func (q *feeParamsQ) Page(pageParams *pgdb.OffsetPageParams) data.FeeParamsQ {
+	q.sql = pageParams.ApplyTo(q.sql, "block_number")
+	return q
+}
@@ -5,20 +5,19 @@ import (
 
 	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
 	sq "github.com/Masterminds/squirrel"
-	"gitlab.com/distributed_lab/kit/pgdb"
 	"gitlab.com/distributed_lab/logan/v3/errors"
 )
 
 const lastProcessedBlockTableName = "last_processed_block"
 
-func NewLastProcessedBlockQ(db *pgdb.DB) data.LastProcessedBlockQ {
+func NewLastProcessedBlockQ(db *DB) data.LastProcessedBlockQ {
 	return &lastProcessedBlockQ{
 		db: db,
 	}
 }
 
 type lastProcessedBlockQ struct {
-	db *pgdb.DB
+	db *DB
 }
 
 func (q *lastProcessedBlockQ) New() data.LastProcessedBlockQ {
@@ -47,4 +46,25 @@ func (q *lastProcessedBlockQ) Update(blockNumber uint64) error {
         return errors.Wrap(err, "failed to update last processed block in db")
     }
     return nil
+}
+
+func (q *lastProcessedBlockQ) Paused() (bool, error) {
+	var result bool
+	err := q.db.Get(&result, sq.Select("paused").From(lastProcessedBlockTableName))
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrap(err, "failed to get listener pause from db")
+	}
+	return result, nil
+}
+
+func (q *lastProcessedBlockQ) SetPaused(paused bool) error {
+	query := sq.Update(lastProcessedBlockTableName).
+		Set("paused", paused).
+		Where(sq.Eq{"id": 1})
+
+	err := q.db.Exec(query)
+	return errors.Wrap(err, "failed to update listener pause in db")
 }
\ No newline at end of file
@@ -1,34 +1,180 @@
 package pg
 
 import (
+	"context"
+	"time"
+
 	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/metrics"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/tracing"
 	"gitlab.com/distributed_lab/kit/pgdb"
 )
 
 func NewMasterQ(db *pgdb.DB) data.MasterQ {
     return &masterQ{
-        db: db.Clone(),
+        db: &DB{
+            DB:  db.Clone(),
+            ctx: context.Background(),
+        },
     }
 }
 
 type masterQ struct {
-    db *pgdb.DB
+    db *DB
 }
 
 func (m *masterQ) New() data.MasterQ {
-    return NewMasterQ(m.db)
+    return &masterQ{
+        db: &DB{
+            DB:  m.db.DB.Clone(),
+            ctx: m.db.ctx,
+        },
+    }
+}
+
+func (m *masterQ) WithContext(ctx context.Context) data.MasterQ {
+    return &masterQ{
+        db: &DB{
+            DB:  m.db.DB,
+            ctx: ctx,
+        },
+    }
 }
 
 func (m *masterQ) USDTTransfer() data.USDTTransferQ {
     return NewUSDTTransferQ(m.db)
 }
 
+func (m *masterQ) TransferArchive() data.TransferArchiveQ {
+	return NewTransferArchiveQ(m.db)
+}
+
+func (m *masterQ) TransferPartition() data.TransferPartitionQ {
+	return NewTransferPartitionQ(m.db)
+}
+
 func (m *masterQ) LastProcessedBlock() data.LastProcessedBlockQ {
 	return NewLastProcessedBlockQ(m.db)
 }
 
+func (m *masterQ) Block() data.BlockQ {
+	return NewBlockQ(m.db)
+}
+
+func (m *masterQ) Balance() data.BalanceQ {
+	return NewBalanceQ(m.db)
+}
+
+func (m *masterQ) BalanceChange() data.BalanceChangeQ {
+	return NewBalanceChangeQ(m.db)
+}
+
+func (m *masterQ) BalanceCheckpoint() data.BalanceCheckpointQ {
+	return NewBalanceCheckpointQ(m.db)
+}
+
+func (m *masterQ) BalanceDiscrepancy() data.BalanceDiscrepancyQ {
+	return NewBalanceDiscrepancyQ(m.db)
+}
+
+func (m *masterQ) TransferStats() data.TransferStatsQ {
+	return NewTransferStatsQ(m.db)
+}
+
+func (m *masterQ) Webhook() data.WebhookQ {
+	return NewWebhookQ(m.db)
+}
+
+func (m *masterQ) WebhookDelivery() data.WebhookDeliveryQ {
+	return NewWebhookDeliveryQ(m.db)
+}
+
+func (m *masterQ) PublisherPosition() data.PublisherPositionQ {
+	return NewPublisherPositionQ(m.db)
+}
+
+func (m *masterQ) PublisherRetraction() data.PublisherRetractionQ {
+	return NewPublisherRetractionQ(m.db)
+}
+
+func (m *masterQ) AlertRule() data.AlertRuleQ {
+	return NewAlertRuleQ(m.db)
+}
+
+func (m *masterQ) Alert() data.AlertQ {
+	return NewAlertQ(m.db)
+}
+
+func (m *masterQ) AddressLabel() data.AddressLabelQ {
+	return NewAddressLabelQ(m.db)
+}
+
+func (m *masterQ) Watchlist() data.WatchlistQ {
+	return NewWatchlistQ(m.db)
+}
+
+func (m *masterQ) WatchlistAddress() data.WatchlistAddressQ {
+	return NewWatchlistAddressQ(m.db)
+}
+
+func (m *masterQ) SanctionsList() data.SanctionsListQ {
+	return NewSanctionsListQ(m.db)
+}
+
+func (m *masterQ) BlacklistEvent() data.BlacklistEventQ {
+	return NewBlacklistEventQ(m.db)
+}
+
+func (m *masterQ) Screening() data.ScreeningQ {
+	return NewScreeningQ(m.db)
+}
+
+func (m *masterQ) SupplyChange() data.SupplyChangeQ {
+	return NewSupplyChangeQ(m.db)
+}
+
+func (m *masterQ) Supply() data.SupplyQ {
+	return NewSupplyQ(m.db)
+}
+
+func (m *masterQ) SupplyCheckpoint() data.SupplyCheckpointQ {
+	return NewSupplyCheckpointQ(m.db)
+}
+
+func (m *masterQ) FeeParams() data.FeeParamsQ {
+	return NewFeeParamsQ(m.db)
+}
+
+func (m *masterQ) USDTContract() data.USDTContractQ {
+	return NewUSDTContractQ(m.db)
+}
+
+func (m *masterQ) EthTransaction() data.EthTransactionQ {
+	return NewEthTransactionQ(m.db)
+}
+
+func (m *masterQ) ReingestionJob() data.ReingestionJobQ {
+	return NewReingestionJobQ(m.db)
+}
+
+func (m *masterQ) AuditLog() data.AuditLogQ {
+	return NewAuditLogQ(m.db)
+}
+
+func (m *masterQ) APIKey() data.APIKeyQ {
+	return NewAPIKeyQ(m.db)
+}
+
 func (m *masterQ) Transaction(fn func(q data.MasterQ) error) error {
-    return m.db.Transaction(func() error {
-        return fn(m)
+    defer func(start time.Time) {
+        metrics.DBTransactionDuration.Observe(time.Since(start).Seconds())
+    }(time.Now())
+
+    ctx, span := tracing.Start(m.db.ctx, "db transaction")
+    err := m.db.Transaction(func() error {
+        return fn(m.WithContext(ctx))
     })
+    tracing.End(span, err)
+
+    return err
 }
\ No newline at end of file
@@ -0,0 +1,74 @@
+package pg
+
+import (
+	"database/sql"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	sq "github.com/Masterminds/squirrel"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const publisherPositionsTableName = "publisher_positions"
+
+func NewPublisherPositionQ(db *DB) data.PublisherPositionQ {
+	return &publisherPositionQ{
+		db: db,
+	}
+}
+
+type publisherPositionQ struct {
+	db *DB
+}
+
+func (q *publisherPositionQ) New() data.PublisherPositionQ {
+	return NewPublisherPositionQ(q.db)
+}
+
+func (q *publisherPositionQ) Get(name string) (*data.PublisherPosition, error) {
+	var result data.PublisherPosition
+	err := q.db.Get(&result, sq.Select("*").From(publisherPositionsTableName).Where(sq.Eq{"name": name}))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get publisher position from db")
+	}
+	return &result, nil
+}
+
+func (q *publisherPositionQ) Lock(name string) (*data.PublisherPosition, error) {
+	var result data.PublisherPosition
+	err := q.db.Get(&result, sq.Select("*").From(publisherPositionsTableName).Where(sq.Eq{"name": name}).Suffix("FOR UPDATE"))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to lock publisher position")
+	}
+	return &result, nil
+}
+
+func (q *publisherPositionQ) Upsert(position data.PublisherPosition) error {
+	stmt := sq.Insert(publisherPositionsTableName).
+		SetMap(map[string]interface{}{
+			"name":         position.Name,
+			"block_number": position.BlockNumber,
+			"log_index":    position.LogIndex,
+		}).
+		Suffix("ON CONFLICT (name) DO UPDATE SET block_number = EXCLUDED.block_number, log_index = EXCLUDED.log_index")
+
+	err := q.db.Exec(stmt)
+	return errors.Wrap(err, "failed to upsert publisher position")
+}
+
+func (q *publisherPositionQ) Rewind(blockNumber uint64) error {
+	stmt := sq.Update(publisherPositionsTableName).
+		SetMap(map[string]interface{}{
+			"block_number": blockNumber,
+			"log_index":    -1,
+		}).
+		Where(sq.GtOrEq{"block_number": blockNumber})
+
+	err := q.db.Exec(stmt)
+	return errors.Wrap(err, "failed to rewind publisher positions")
+}
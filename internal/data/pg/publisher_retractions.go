@@ -0,0 +1,71 @@
+package pg
+
+import (
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	sq "github.com/Masterminds/squirrel"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const publisherRetractionsTableName = "publisher_retractions"
+
+func NewPublisherRetractionQ(db *DB) data.PublisherRetractionQ {
+	return &publisherRetractionQ{
+		db: db,
+		sql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
+			Select("id AS retraction_id", "name", "transfer_id AS id", "from_address", "to_address", "amount",
+				"transaction_hash", "block_number", "log_index", "timestamp", "flags", "fee", "principal_log_index").
+			From(publisherRetractionsTableName),
+	}
+}
+
+type publisherRetractionQ struct {
+	db  *DB
+	sql sq.SelectBuilder
+}
+
+func (q *publisherRetractionQ) New() data.PublisherRetractionQ {
+	return NewPublisherRetractionQ(q.db)
+}
+
+func (q *publisherRetractionQ) Select() ([]data.PublisherRetraction, error) {
+	var result []data.PublisherRetraction
+	err := q.db.Select(&result, q.sql.OrderBy("retraction_id ASC"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select publisher retractions from db")
+	}
+	return result, nil
+}
+
+// EnqueueBlock locks the positions, so a batch being published is either
+// done and retracted or not published yet
+func (q *publisherRetractionQ) EnqueueBlock(blockNumber uint64) error {
+	query := `INSERT INTO publisher_retractions (name, transfer_id, from_address, to_address, amount, transaction_hash,
+                                                 block_number, log_index, timestamp, flags, fee, principal_log_index)
+              SELECT p.name, t.id, t.from_address, t.to_address, t.amount, t.transaction_hash,
+                     t.block_number, t.log_index, t.timestamp, t.flags, t.fee, t.principal_log_index
+              FROM usdt_transfers t
+              JOIN (SELECT * FROM publisher_positions FOR UPDATE) p
+                ON (t.block_number, t.log_index) <= (p.block_number, p.log_index)
+              WHERE t.block_number = ?
+              ORDER BY p.name, t.log_index`
+
+	if err := q.db.ExecRaw(query, blockNumber); err != nil {
+		return errors.Wrap(err, "failed to enqueue publisher retractions")
+	}
+	return nil
+}
+
+func (q *publisherRetractionQ) Delete(ids ...int64) error {
+	err := q.db.Exec(sq.Delete(publisherRetractionsTableName).Where(sq.Eq{"id": ids}))
+	return errors.Wrap(err, "failed to delete publisher retractions")
+}
+
+func (q *publisherRetractionQ) FilterByName(name string) data.PublisherRetractionQ {
+	q.sql = q.sql.Where(sq.Eq{"name": name})
+	return q
+}
+
+func (q *publisherRetractionQ) Limit(limit uint64) data.PublisherRetractionQ {
+	q.sql = q.sql.Limit(limit)
+	return q
+}
@@ -0,0 +1,93 @@
+package pg
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	sq "github.com/Masterminds/squirrel"
+	"gitlab.com/distributed_lab/kit/pgdb"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const reingestionJobsTableName = "reingestion_jobs"
+
+func NewReingestionJobQ(db *DB) data.ReingestionJobQ {
+	return &reingestionJobQ{
+		db:  db,
+		sql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar).Select("*").From(reingestionJobsTableName),
+	}
+}
+
+type reingestionJobQ struct {
+	db  *DB
+	sql sq.SelectBuilder
+}
+
+func (q *reingestionJobQ) New() data.ReingestionJobQ {
+	return NewReingestionJobQ(q.db)
+}
+
+func (q *reingestionJobQ) Get() (*data.ReingestionJob, error) {
+	var result data.ReingestionJob
+	err := q.db.Get(&result, q.sql)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get re-ingestion job from db")
+	}
+	return &result, nil
+}
+
+func (q *reingestionJobQ) Select() ([]data.ReingestionJob, error) {
+	var result []data.ReingestionJob
+	err := q.db.Select(&result, q.sql)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select re-ingestion jobs from db")
+	}
+	return result, nil
+}
+
+func (q *reingestionJobQ) Insert(job data.ReingestionJob) (*data.ReingestionJob, error) {
+	clauses := map[string]interface{}{
+		"from_block": job.FromBlock,
+		"to_block":   job.ToBlock,
+		"status":     data.ReingestionStatusPending,
+	}
+	var result data.ReingestionJob
+	stmt := sq.Insert(reingestionJobsTableName).SetMap(clauses).Suffix("RETURNING *")
+	err := q.db.Get(&result, stmt)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to insert re-ingestion job to db")
+	}
+	return &result, nil
+}
+
+func (q *reingestionJobQ) UpdateProgress(id int64, status string, currentBlock uint64) error {
+	stmt := sq.Update(reingestionJobsTableName).
+		SetMap(map[string]interface{}{
+			"status":        status,
+			"current_block": currentBlock,
+			"updated_at":    time.Now().UTC(),
+		}).
+		Where(sq.Eq{"id": id})
+
+	err := q.db.Exec(stmt)
+	return errors.Wrap(err, "failed to update re-ingestion job progress")
+}
+
+func (q *reingestionJobQ) FilterByID(id int64) data.ReingestionJobQ {
+	q.sql = q.sql.Where(sq.Eq{"id": id})
+	return q
+}
+
+func (q *reingestionJobQ) FilterActive() data.ReingestionJobQ {
+	q.sql = q.sql.Where(sq.NotEq{"status": data.ReingestionStatusDone})
+	return q
+}
+
+func (q *reingestionJobQ) Page(pageParams *pgdb.OffsetPageParams) data.ReingestionJobQ {
+	q.sql = pageParams.ApplyTo(q.sql, "id")
+	return q
+}
@@ -0,0 +1,127 @@
+package pg
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	sq "github.com/Masterminds/squirrel"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const (
+	sanctionsListsTableName      = "sanctions_lists"
+	sanctionedAddressesTableName = "sanctioned_addresses"
+)
+
+func NewSanctionsListQ(db *DB) data.SanctionsListQ {
+	return &sanctionsListQ{
+		db:  db,
+		sql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar).Select("*").From(sanctionsListsTableName),
+	}
+}
+
+type sanctionsListQ struct {
+	db  *DB
+	sql sq.SelectBuilder
+}
+
+func (q *sanctionsListQ) New() data.SanctionsListQ {
+	return NewSanctionsListQ(q.db)
+}
+
+func (q *sanctionsListQ) Get() (*data.SanctionsList, error) {
+	var result data.SanctionsList
+	err := q.db.Get(&result, q.sql)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get sanctions list from db")
+	}
+	return &result, nil
+}
+
+func (q *sanctionsListQ) Select() ([]data.SanctionsList, error) {
+	var result []data.SanctionsList
+	err := q.db.Select(&result, q.sql.OrderBy("name"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select sanctions lists from db")
+	}
+	return result, nil
+}
+
+func (q *sanctionsListQ) Replace(list data.SanctionsList, addresses []string) ([]string, error) {
+	upsert := sq.Insert(sanctionsListsTableName).
+		SetMap(map[string]interface{}{
+			"name":          list.Name,
+			"source":        list.Source,
+			"address_count": len(addresses),
+			"imported_at":   list.ImportedAt,
+		}).
+		Suffix("ON CONFLICT (name) DO UPDATE SET source = EXCLUDED.source, address_count = EXCLUDED.address_count, imported_at = EXCLUDED.imported_at")
+	if err := q.db.Exec(upsert); err != nil {
+		return nil, errors.Wrap(err, "failed to upsert sanctions list")
+	}
+
+	var current []string
+	stmt := sq.Select("address").From(sanctionedAddressesTableName).Where(sq.Eq{"list": list.Name})
+	if err := q.db.Select(&current, stmt); err != nil {
+		return nil, errors.Wrap(err, "failed to select sanctioned addresses")
+	}
+
+	listed := make(map[string]bool, len(addresses))
+	for _, address := range addresses {
+		listed[address] = true
+	}
+
+	var added, removed []string
+	for _, address := range current {
+		if !listed[address] {
+			removed = append(removed, address)
+		}
+		delete(listed, address)
+	}
+	for _, address := range addresses {
+		if listed[address] {
+			added = append(added, address)
+			delete(listed, address)
+		}
+	}
+
+	if len(removed) > 0 {
+		stmt := sq.Delete(sanctionedAddressesTableName).Where(sq.Eq{"list": list.Name, "address": removed})
+		if err := q.db.Exec(stmt); err != nil {
+			return nil, errors.Wrap(err, "failed to delete sanctioned addresses")
+		}
+	}
+
+	for start := 0; start < len(added); start += upsertBatchSize {
+		end := start + upsertBatchSize
+		if end > len(added) {
+			end = len(added)
+		}
+
+		batch := added[start:end]
+		values := make([]interface{}, 0, len(batch)*2)
+		placeholders := make([]string, 0, len(batch))
+
+		for i, address := range batch {
+			values = append(values, list.Name, address)
+			placeholders = append(placeholders, fmt.Sprintf("($%d, $%d)", i*2+1, i*2+2))
+		}
+
+		query := `INSERT INTO sanctioned_addresses (list, address) VALUES ` + strings.Join(placeholders, ", ")
+		if err := q.db.ExecRaw(query, values...); err != nil {
+			return nil, errors.Wrap(err, "failed to insert sanctioned addresses")
+		}
+	}
+
+	return append(added, removed...), nil
+}
+
+func (q *sanctionsListQ) FilterByName(name string) data.SanctionsListQ {
+	q.sql = q.sql.Where(sq.Eq{"name": name})
+	return q
+}
@@ -0,0 +1,124 @@
+package pg
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/lib/pq"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// screenTransfersQuery recomputes flags of the transfers matching the
+// condition. Sanctions are checked against the current lists, the blacklist
+// status is taken as of the transfer.
+var screenTransfersQuery = fmt.Sprintf(`UPDATE usdt_transfers t SET flags =
+      (CASE WHEN EXISTS (SELECT 1 FROM sanctioned_addresses s WHERE s.address = t.from_address) THEN %d ELSE 0 END)
+    | (CASE WHEN EXISTS (SELECT 1 FROM sanctioned_addresses s WHERE s.address = t.to_address) THEN %d ELSE 0 END)
+    | (CASE WHEN %s THEN %d ELSE 0 END)
+    | (CASE WHEN %s THEN %d ELSE 0 END)
+    WHERE `,
+	data.FlagSanctionedSender,
+	data.FlagSanctionedReceiver,
+	blacklistedAt("t.from_address"), data.FlagBlacklistedSender,
+	blacklistedAt("t.to_address"), data.FlagBlacklistedReceiver,
+)
+
+func blacklistedAt(address string) string {
+	return fmt.Sprintf(`COALESCE((SELECT b.blacklisted FROM blacklist_events b
+                    WHERE b.address = %s AND (b.block_number, b.log_index) < (t.block_number, t.log_index)
+                    ORDER BY b.block_number DESC, b.log_index DESC LIMIT 1), FALSE)`, address)
+}
+
+func NewScreeningQ(db *DB) data.ScreeningQ {
+	return &screeningQ{
+		db: db,
+	}
+}
+
+type screeningQ struct {
+	db *DB
+}
+
+func (q *screeningQ) New() data.ScreeningQ {
+	return NewScreeningQ(q.db)
+}
+
+func (q *screeningQ) ScreenBlock(blockNumber uint64) error {
+	if err := q.db.ExecRaw(screenTransfersQuery+"t.block_number = ?", blockNumber); err != nil {
+		return errors.Wrap(err, "failed to screen transfers of the block")
+	}
+	return nil
+}
+
+func (q *screeningQ) Rescreen(addresses []string) error {
+	if len(addresses) == 0 {
+		return nil
+	}
+
+	condition := "(t.from_address = ANY(?::CHAR(42)[]) OR t.to_address = ANY(?::CHAR(42)[]))"
+	if err := q.db.ExecRaw(screenTransfersQuery+condition, pq.Array(addresses), pq.Array(addresses)); err != nil {
+		return errors.Wrap(err, "failed to rescreen transfers")
+	}
+	return nil
+}
+
+func (q *screeningQ) Report(from, to time.Time, limit uint64) (*data.ScreeningReport, error) {
+	report := data.ScreeningReport{
+		From:      from,
+		To:        to,
+		Addresses: []data.FlaggedAddress{},
+	}
+
+	var totals struct {
+		Total   int64  `db:"total"`
+		Flagged int64  `db:"flagged"`
+		Volume  string `db:"volume"`
+	}
+	totalsQuery := `SELECT COUNT(*) AS total,
+                           COUNT(*) FILTER (WHERE flags <> 0) AS flagged,
+                           COALESCE(SUM(amount) FILTER (WHERE flags <> 0), 0)::TEXT AS volume
+                    FROM usdt_transfers
+                    WHERE timestamp >= ? AND timestamp < ?`
+	if err := q.db.GetRaw(&totals, totalsQuery, from, to); err != nil {
+		return nil, errors.Wrap(err, "failed to count flagged transfers")
+	}
+	report.TotalTransfers = totals.Total
+	report.FlaggedTransfers = totals.Flagged
+	report.FlaggedVolume = totals.Volume
+
+	flagsQuery := `SELECT f.mask, COUNT(t.id) AS transfers, COALESCE(SUM(t.amount), 0)::TEXT AS volume
+                   FROM (VALUES (1), (2), (4), (8)) f (mask)
+                   LEFT JOIN usdt_transfers t ON t.flags & f.mask <> 0 AND t.timestamp >= ? AND t.timestamp < ?
+                   GROUP BY f.mask
+                   ORDER BY f.mask`
+	if err := q.db.SelectRaw(&report.Flags, flagsQuery, from, to); err != nil {
+		return nil, errors.Wrap(err, "failed to count transfers by flag")
+	}
+	for i := range report.Flags {
+		report.Flags[i].Flag = data.TransferFlagNames[report.Flags[i].Mask]
+	}
+
+	addressesQuery := fmt.Sprintf(`WITH flagged AS (
+                           SELECT from_address AS address, amount FROM usdt_transfers
+                           WHERE flags & %d <> 0 AND timestamp >= ? AND timestamp < ?
+                           UNION ALL
+                           SELECT to_address AS address, amount FROM usdt_transfers
+                           WHERE flags & %d <> 0 AND timestamp >= ? AND timestamp < ?)
+                       SELECT f.address,
+                              COALESCE((SELECT array_agg(s.list ORDER BY s.list) FROM sanctioned_addresses s
+                                        WHERE s.address = f.address), '{}') AS lists,
+                              COALESCE((SELECT b.blacklisted FROM blacklist_events b WHERE b.address = f.address
+                                        ORDER BY b.block_number DESC, b.log_index DESC LIMIT 1), FALSE) AS blacklisted,
+                              COUNT(*) AS transfers,
+                              SUM(f.amount)::TEXT AS volume
+                       FROM flagged f
+                       GROUP BY f.address
+                       ORDER BY SUM(f.amount) DESC
+                       LIMIT ?`, data.FlagsSender, data.FlagsReceiver)
+	if err := q.db.SelectRaw(&report.Addresses, addressesQuery, from, to, from, to, limit); err != nil {
+		return nil, errors.Wrap(err, "failed to select flagged addresses")
+	}
+
+	return &report, nil
+}
@@ -0,0 +1,61 @@
+package pg
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	sq "github.com/Masterminds/squirrel"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const supplyChangesTableName = "supply_changes"
+
+func NewSupplyChangeQ(db *DB) data.SupplyChangeQ {
+	return &supplyChangeQ{
+		db: db,
+	}
+}
+
+type supplyChangeQ struct {
+	db *DB
+}
+
+func (q *supplyChangeQ) New() data.SupplyChangeQ {
+	return NewSupplyChangeQ(q.db)
+}
+
+func (q *supplyChangeQ) InsertBatch(changes []data.SupplyChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	values := make([]interface{}, 0, len(changes)*5)
+	placeholders := make([]string, 0, len(changes))
+
+	for i, change := range changes {
+		values = append(values,
+			change.BlockNumber,
+			change.LogIndex,
+			change.Kind,
+			change.Amount,
+			change.Timestamp,
+		)
+		placeholders = append(placeholders, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d)",
+			i*5+1, i*5+2, i*5+3, i*5+4, i*5+5,
+		))
+	}
+
+	query := `INSERT INTO supply_changes (block_number, log_index, kind, amount, timestamp) VALUES ` +
+		strings.Join(placeholders, ", ")
+
+	if err := q.db.ExecRaw(query, values...); err != nil {
+		return errors.Wrap(err, "failed to insert supply changes")
+	}
+	return nil
+}
+
+func (q *supplyChangeQ) DeleteByBlockNumber(blockNumber uint64) error {
+	err := q.db.Exec(sq.Delete(supplyChangesTableName).Where(sq.Eq{"block_number": blockNumber}))
+	return errors.Wrap(err, "failed to delete supply changes")
+}
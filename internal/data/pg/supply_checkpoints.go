@@ -0,0 +1,66 @@
+package pg
+
+import (
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	sq "github.com/Masterminds/squirrel"
+	"gitlab.com/distributed_lab/kit/pgdb"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const supplyCheckpointsTableName = "supply_checkpoints"
+
+func NewSupplyCheckpointQ(db *DB) data.SupplyCheckpointQ {
+	return &supplyCheckpointQ{
+		db:  db,
+		sql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar).Select("*").From(supplyCheckpointsTableName),
+	}
+}
+
+type supplyCheckpointQ struct {
+	db  *DB
+	sql sq.SelectBuilder
+}
+
+func (q *supplyCheckpointQ) New() data.SupplyCheckpointQ {
+	return NewSupplyCheckpointQ(q.db)
+}
+
+func (q *supplyCheckpointQ) Select() ([]data.SupplyCheckpoint, error) {
+	var result []data.SupplyCheckpoint
+	err := q.db.Select(&result, q.sql)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select supply checkpoints from db")
+	}
+	return result, nil
+}
+
+func (q *supplyCheckpointQ) Insert(blockNumber uint64, onchainSupply string) (*data.SupplyCheckpoint, error) {
+	query := `INSERT INTO supply_checkpoints (block_number, indexed_supply, onchain_supply)
+              SELECT ?, h.total_supply, ? FROM supply_history h WHERE h.block_number <= ?
+              ORDER BY h.block_number DESC LIMIT 1
+              ON CONFLICT (block_number) DO UPDATE
+              SET indexed_supply = EXCLUDED.indexed_supply, onchain_supply = EXCLUDED.onchain_supply
+              RETURNING *`
+
+	var result data.SupplyCheckpoint
+	err := q.db.GetRaw(&result, query, blockNumber, onchainSupply, blockNumber)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to insert supply checkpoint to db")
+	}
+	return &result, nil
+}
+
+func (q *supplyCheckpointQ) DeleteByBlockNumber(blockNumber uint64) error {
+	err := q.db.Exec(sq.Delete(supplyCheckpointsTableName).Where(sq.Eq{"block_number": blockNumber}))
+	return errors.Wrap(err, "failed to delete supply checkpoint")
+}
+
+func (q *supplyCheckpointQ) FilterMismatched() data.SupplyCheckpointQ {
+	q.sql = q.sql.Where("indexed_supply <> onchain_supply")
+	return q
+}
+
+func (q *supplyCheckpointQ) Page(pageParams *pgdb.OffsetPageParams) data.SupplyCheckpointQ {
+	q.sql = pageParams.ApplyTo(q.sql, "block_number")
+	return q
+}
@@ -0,0 +1,102 @@
+package pg
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	sq "github.com/Masterminds/squirrel"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const supplyHistoryTableName = "supply_history"
+
+var granularityIntervals = map[string]string{
+	data.GranularityHour: "1 hour",
+	data.GranularityDay:  "1 day",
+	data.GranularityWeek: "1 week",
+}
+
+func NewSupplyQ(db *DB) data.SupplyQ {
+	return &supplyQ{
+		db:  db,
+		sql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar).Select("*").From(supplyHistoryTableName),
+	}
+}
+
+type supplyQ struct {
+	db  *DB
+	sql sq.SelectBuilder
+}
+
+func (q *supplyQ) New() data.SupplyQ {
+	return NewSupplyQ(q.db)
+}
+
+func (q *supplyQ) Latest() (*data.SupplyPoint, error) {
+	var result data.SupplyPoint
+	err := q.db.Get(&result, q.sql.OrderBy("block_number DESC").Limit(1))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get supply point from db")
+	}
+	return &result, nil
+}
+
+func (q *supplyQ) Insert(point data.SupplyPoint) error {
+	clauses := map[string]interface{}{
+		"block_number": point.BlockNumber,
+		"timestamp":    point.Timestamp,
+		"delta":        point.Delta,
+		"total_supply": point.TotalSupply,
+	}
+	err := q.db.Exec(sq.Insert(supplyHistoryTableName).SetMap(clauses))
+	return errors.Wrap(err, "failed to insert supply point")
+}
+
+func (q *supplyQ) ApplyBlock(blockNumber uint64) error {
+	query := `INSERT INTO supply_history (block_number, timestamp, delta, total_supply)
+              SELECT c.block_number, MIN(c.timestamp), SUM(c.amount),
+                     (SELECT h.total_supply FROM supply_history h WHERE h.block_number < c.block_number
+                      ORDER BY h.block_number DESC LIMIT 1) + SUM(c.amount)
+              FROM supply_changes c WHERE c.block_number = ?
+              GROUP BY c.block_number`
+
+	if err := q.db.ExecRaw(query, blockNumber); err != nil {
+		return errors.Wrap(err, "failed to apply supply changes")
+	}
+	return nil
+}
+
+func (q *supplyQ) DeleteByBlockNumber(blockNumber uint64) error {
+	err := q.db.Exec(sq.Delete(supplyHistoryTableName).Where(sq.Eq{"block_number": blockNumber}))
+	return errors.Wrap(err, "failed to delete supply point")
+}
+
+func (q *supplyQ) Series(granularity string, from, to time.Time) ([]data.SupplyBucket, error) {
+	interval, ok := granularityIntervals[granularity]
+	if !ok {
+		return nil, errors.Errorf("unknown granularity %s", granularity)
+	}
+
+	query := `SELECT b.bucket,
+                     (SELECT h.total_supply::TEXT FROM supply_history h WHERE h.timestamp < b.bucket + ?::INTERVAL
+                      ORDER BY h.block_number DESC LIMIT 1) AS total_supply,
+                     COALESCE(SUM(c.amount) FILTER (WHERE c.kind = 'issue'), 0)::TEXT AS issued,
+                     COALESCE(-SUM(c.amount) FILTER (WHERE c.kind = 'redeem'), 0)::TEXT AS redeemed,
+                     COALESCE(-SUM(c.amount) FILTER (WHERE c.kind = 'destroyed_black_funds'), 0)::TEXT AS destroyed
+              FROM generate_series(date_trunc(?, ?::TIMESTAMP), ?::TIMESTAMP, ?::INTERVAL) AS b (bucket)
+              LEFT JOIN supply_changes c ON c.timestamp >= b.bucket AND c.timestamp < b.bucket + ?::INTERVAL
+              WHERE b.bucket < ?
+              GROUP BY b.bucket
+              ORDER BY b.bucket`
+
+	var result []data.SupplyBucket
+	err := q.db.SelectRaw(&result, query, interval, granularity, from, to, interval, interval, to)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select supply series from db")
+	}
+	return result, nil
+}
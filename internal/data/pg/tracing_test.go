@@ -0,0 +1,103 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/tracing"
+	sq "github.com/Masterminds/squirrel"
+	"gitlab.com/distributed_lab/kit/pgdb"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// queryer answers every query without a database, Get finds no rows
+type queryer struct {
+	pgdb.Queryer
+}
+
+func (queryer) GetContext(context.Context, interface{}, sq.Sqlizer) error { return sql.ErrNoRows }
+func (queryer) SelectContext(context.Context, interface{}, sq.Sqlizer) error {
+	return nil
+}
+func (queryer) ExecContext(context.Context, sq.Sqlizer) error { return nil }
+
+func setupTracing(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		provider.Shutdown(context.Background())
+	})
+	return exporter
+}
+
+func newTestMasterQ() *masterQ {
+	return &masterQ{
+		db: &DB{
+			DB:  &pgdb.DB{Queryer: queryer{}},
+			ctx: context.Background(),
+		},
+	}
+}
+
+func TestQueriesAreChildSpans(t *testing.T) {
+	exporter := setupTracing(t)
+
+	ctx, span := tracing.Start(context.Background(), "processBlock")
+	db := newTestMasterQ().WithContext(ctx)
+	if _, err := db.Block().FilterByNumber(99).Get(); err != nil {
+		t.Fatalf("failed to get block: %v", err)
+	}
+	if err := db.Block().Delete(100); err != nil {
+		t.Fatalf("failed to delete block: %v", err)
+	}
+	span.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 3 {
+		t.Fatalf("got %d spans, want 3", len(spans))
+	}
+	root := spans[len(spans)-1]
+	if root.Name != "processBlock" {
+		t.Fatalf("last ended span is %q, want processBlock", root.Name)
+	}
+	for i, name := range []string{"db SELECT", "db DELETE"} {
+		if spans[i].Name != name {
+			t.Errorf("span %d is %q, want %q", i, spans[i].Name, name)
+		}
+		if spans[i].Parent.SpanID() != root.SpanContext.SpanID() {
+			t.Errorf("%s span is not a child of processBlock", spans[i].Name)
+		}
+		if spans[i].SpanContext.TraceID() != root.SpanContext.TraceID() {
+			t.Errorf("%s span is in another trace", spans[i].Name)
+		}
+	}
+	// no rows is a valid result, not a failed query
+	if spans[0].Status.Code != codes.Unset {
+		t.Errorf("db SELECT span has status %s", spans[0].Status.Code)
+	}
+}
+
+func TestQueriesOutsideSpanAreNotRecorded(t *testing.T) {
+	exporter := setupTracing(t)
+
+	db := newTestMasterQ()
+	if _, err := db.Block().FilterByNumber(99).Get(); err != nil {
+		t.Fatalf("failed to get block: %v", err)
+	}
+	if _, err := db.WithContext(context.Background()).Block().FilterByNumber(99).Get(); err != nil {
+		t.Fatalf("failed to get block: %v", err)
+	}
+
+	if spans := exporter.GetSpans(); len(spans) != 0 {
+		t.Errorf("got %d spans of queries outside of a traced operation, want 0", len(spans))
+	}
+}
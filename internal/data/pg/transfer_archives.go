@@ -0,0 +1,109 @@
+package pg
+
+import (
+	"database/sql"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	sq "github.com/Masterminds/squirrel"
+	"gitlab.com/distributed_lab/kit/pgdb"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const (
+	transferArchivesTableName = "transfer_archives"
+	archivedBlockTableName    = "archived_block"
+)
+
+func NewTransferArchiveQ(db *DB) data.TransferArchiveQ {
+	return &transferArchiveQ{
+		db:  db,
+		sql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar).Select("*").From(transferArchivesTableName),
+	}
+}
+
+type transferArchiveQ struct {
+	db  *DB
+	sql sq.SelectBuilder
+}
+
+func (q *transferArchiveQ) New() data.TransferArchiveQ {
+	return NewTransferArchiveQ(q.db)
+}
+
+func (q *transferArchiveQ) Get() (*data.TransferArchive, error) {
+	var result data.TransferArchive
+	err := q.db.Get(&result, q.sql)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get transfer archive from db")
+	}
+	return &result, nil
+}
+
+func (q *transferArchiveQ) Select() ([]data.TransferArchive, error) {
+	var result []data.TransferArchive
+	err := q.db.Select(&result, q.sql)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select transfer archives from db")
+	}
+	return result, nil
+}
+
+func (q *transferArchiveQ) Insert(archive data.TransferArchive) error {
+	stmt := sq.Insert(transferArchivesTableName).SetMap(map[string]interface{}{
+		"month":           archive.Month,
+		"from_block":      archive.FromBlock,
+		"to_block":        archive.ToBlock,
+		"min_transfer_id": archive.MinTransferID,
+		"max_transfer_id": archive.MaxTransferID,
+		"transfer_count":  archive.TransferCount,
+		"path":            archive.Path,
+		"size":            archive.Size,
+	})
+
+	err := q.db.Exec(stmt)
+	return errors.Wrap(err, "failed to insert transfer archive to db")
+}
+
+func (q *transferArchiveQ) LastBlock() (uint64, error) {
+	var result uint64
+	err := q.db.Get(&result, sq.Select("block_number").From(archivedBlockTableName))
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get archived block from db")
+	}
+	return result, nil
+}
+
+func (q *transferArchiveQ) SetLastBlock(blockNumber uint64) error {
+	stmt := sq.Update(archivedBlockTableName).
+		Set("block_number", blockNumber).
+		Where(sq.Eq{"id": 1})
+
+	err := q.db.Exec(stmt)
+	return errors.Wrap(err, "failed to update archived block in db")
+}
+
+func (q *transferArchiveQ) FilterByID(id int64) data.TransferArchiveQ {
+	q.sql = q.sql.Where(sq.Eq{"id": id})
+	return q
+}
+
+func (q *transferArchiveQ) FilterByBlockRange(fromBlock, toBlock uint64) data.TransferArchiveQ {
+	q.sql = q.sql.Where(sq.And{sq.LtOrEq{"from_block": toBlock}, sq.GtOrEq{"to_block": fromBlock}})
+	return q
+}
+
+func (q *transferArchiveQ) FilterByTransferID(id int64) data.TransferArchiveQ {
+	q.sql = q.sql.Where(sq.And{sq.LtOrEq{"min_transfer_id": id}, sq.GtOrEq{"max_transfer_id": id}})
+	return q
+}
+
+func (q *transferArchiveQ) Page(pageParams *pgdb.OffsetPageParams) data.TransferArchiveQ {
+	q.sql = pageParams.ApplyTo(q.sql, "id")
+	return q
+}
@@ -0,0 +1,98 @@
+package pg
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/lib/pq"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// partitionBoundRegexp matches the bound of a range partition as printed by
+// pg_get_expr
+var partitionBoundRegexp = regexp.MustCompile(`^FOR VALUES FROM \('(\d+)'\) TO \('(\d+)'\)$`)
+
+func NewTransferPartitionQ(db *DB) data.TransferPartitionQ {
+	return &transferPartitionQ{
+		db: db,
+	}
+}
+
+type transferPartitionQ struct {
+	db *DB
+}
+
+func (q *transferPartitionQ) New() data.TransferPartitionQ {
+	return NewTransferPartitionQ(q.db)
+}
+
+func (q *transferPartitionQ) Select() ([]data.TransferPartition, error) {
+	var rows []struct {
+		Name      string `db:"name"`
+		Bound     string `db:"bound"`
+		Detaching bool   `db:"detaching"`
+	}
+	query := `SELECT c.relname AS name, pg_get_expr(c.relpartbound, c.oid) AS bound, i.inhdetachpending AS detaching
+              FROM pg_inherits i
+              JOIN pg_class c ON c.oid = i.inhrelid
+              WHERE i.inhparent = ?::regclass`
+	if err := q.db.SelectRaw(&rows, query, usdtTransfersTableName); err != nil {
+		return nil, errors.Wrap(err, "failed to select transfer partitions from db")
+	}
+
+	result := make([]data.TransferPartition, 0, len(rows))
+	for _, row := range rows {
+		match := partitionBoundRegexp.FindStringSubmatch(row.Bound)
+		if match == nil {
+			return nil, errors.From(errors.New("unsupported transfer partition bound"), logan.F{
+				"partition": row.Name,
+				"bound":     row.Bound,
+			})
+		}
+		fromBlock, _ := strconv.ParseUint(match[1], 10, 64)
+		toBlock, _ := strconv.ParseUint(match[2], 10, 64)
+		result = append(result, data.TransferPartition{
+			Name:      row.Name,
+			FromBlock: fromBlock,
+			ToBlock:   toBlock,
+			Detaching: row.Detaching,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].FromBlock < result[j].FromBlock
+	})
+	return result, nil
+}
+
+func (q *transferPartitionQ) Create(fromBlock, toBlock uint64) error {
+	name := fmt.Sprintf("%s_p%d", usdtTransfersTableName, fromBlock)
+	query := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s PARTITION OF %s FOR VALUES FROM (%d) TO (%d)",
+		pq.QuoteIdentifier(name), usdtTransfersTableName, fromBlock, toBlock)
+
+	err := q.db.ExecRaw(query)
+	return errors.Wrap(err, "failed to create transfer partition", logan.F{"partition": name})
+}
+
+// Detach only takes a lock on the partition, so the listener keeps writing
+// to usdt_transfers meanwhile
+func (q *transferPartitionQ) Detach(partition data.TransferPartition) error {
+	mode := "CONCURRENTLY"
+	if partition.Detaching {
+		mode = "FINALIZE"
+	}
+	query := fmt.Sprintf("ALTER TABLE %s DETACH PARTITION %s %s",
+		usdtTransfersTableName, pq.QuoteIdentifier(partition.Name), mode)
+
+	err := q.db.ExecRaw(query)
+	return errors.Wrap(err, "failed to detach transfer partition", logan.F{"partition": partition.Name})
+}
+
+func (q *transferPartitionQ) Drop(name string) error {
+	err := q.db.ExecRaw("DROP TABLE " + pq.QuoteIdentifier(name))
+	return errors.Wrap(err, "failed to drop transfer partition", logan.F{"partition": name})
+}
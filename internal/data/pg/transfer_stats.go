@@ -0,0 +1,154 @@
+package pg
+
+import (
+	"time"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	sq "github.com/Masterminds/squirrel"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const transferStatsTableName = "transfer_stats"
+
+// blockBuckets expands transfers of a block into one row per granularity
+const blockBuckets = `SELECT g.granularity, date_trunc(g.granularity, t.timestamp) AS bucket, t.*
+                      FROM usdt_transfers t
+                      CROSS JOIN (VALUES ('hour'), ('day'), ('week')) AS g (granularity)
+                      WHERE t.block_number = ?`
+
+// bucketsSince expands transfers into one row per granularity, starting from
+// the buckets holding the block
+const bucketsSince = `SELECT g.granularity, date_trunc(g.granularity, t.timestamp) AS bucket, t.*
+                      FROM usdt_transfers t
+                      CROSS JOIN (VALUES ('hour'), ('day'), ('week')) AS g (granularity)
+                      CROSS JOIN (SELECT timestamp FROM blocks WHERE number = ?) AS s
+                      WHERE t.timestamp >= date_trunc('week', s.timestamp)
+                        AND date_trunc(g.granularity, t.timestamp) >= date_trunc(g.granularity, s.timestamp)`
+
+func NewTransferStatsQ(db *DB) data.TransferStatsQ {
+	return &transferStatsQ{
+		db:  db,
+		sql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar).Select("*").From(transferStatsTableName),
+	}
+}
+
+type transferStatsQ struct {
+	db  *DB
+	sql sq.SelectBuilder
+}
+
+func (q *transferStatsQ) New() data.TransferStatsQ {
+	return NewTransferStatsQ(q.db)
+}
+
+func (q *transferStatsQ) Select() ([]data.TransferStats, error) {
+	var result []data.TransferStats
+	err := q.db.Select(&result, q.sql.OrderBy("bucket ASC"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select transfer stats from db")
+	}
+	return result, nil
+}
+
+func (q *transferStatsQ) ApplyBlock(blockNumber uint64) error {
+	participants := `INSERT INTO transfer_stats_participants (granularity, bucket, address, role, first_block)
+                     SELECT granularity, bucket, from_address, 'sender', block_number FROM (` + blockBuckets + `) b
+                     UNION
+                     SELECT granularity, bucket, to_address, 'receiver', block_number FROM (` + blockBuckets + `) b
+                     ON CONFLICT DO NOTHING`
+	if err := q.db.ExecRaw(participants, blockNumber, blockNumber); err != nil {
+		return errors.Wrap(err, "failed to insert stats participants")
+	}
+
+	totals := `INSERT INTO transfer_stats (granularity, bucket, volume, transfer_count)
+               SELECT granularity, bucket, SUM(amount), COUNT(*) FROM (` + blockBuckets + `) b
+               GROUP BY granularity, bucket
+               ON CONFLICT (granularity, bucket) DO UPDATE
+               SET volume = transfer_stats.volume + EXCLUDED.volume,
+                   transfer_count = transfer_stats.transfer_count + EXCLUDED.transfer_count`
+	if err := q.db.ExecRaw(totals, blockNumber); err != nil {
+		return errors.Wrap(err, "failed to add transfer stats totals")
+	}
+
+	if err := q.addUniqueCounts(blockNumber, 1); err != nil {
+		return errors.Wrap(err, "failed to add unique counts")
+	}
+	return nil
+}
+
+func (q *transferStatsQ) RevertBlock(blockNumber uint64) error {
+	if err := q.addUniqueCounts(blockNumber, -1); err != nil {
+		return errors.Wrap(err, "failed to subtract unique counts")
+	}
+
+	participants := `DELETE FROM transfer_stats_participants WHERE first_block = ?`
+	if err := q.db.ExecRaw(participants, blockNumber); err != nil {
+		return errors.Wrap(err, "failed to delete stats participants")
+	}
+
+	totals := `UPDATE transfer_stats SET volume = transfer_stats.volume - b.volume,
+                   transfer_count = transfer_stats.transfer_count - b.transfer_count
+               FROM (SELECT granularity, bucket, SUM(amount) AS volume, COUNT(*) AS transfer_count
+                     FROM (` + blockBuckets + `) b GROUP BY granularity, bucket) b
+               WHERE transfer_stats.granularity = b.granularity AND transfer_stats.bucket = b.bucket`
+	if err := q.db.ExecRaw(totals, blockNumber); err != nil {
+		return errors.Wrap(err, "failed to subtract transfer stats totals")
+	}
+	return nil
+}
+
+// Prune deletes the participants of the buckets ended at or before the
+// moment. They are only needed to revert blocks of the bucket, which can't
+// happen once the moment is final.
+func (q *transferStatsQ) Prune(before time.Time) error {
+	query := `DELETE FROM transfer_stats_participants
+              WHERE (granularity = 'hour' AND bucket <= ?)
+                 OR (granularity = 'day' AND bucket <= ?)
+                 OR (granularity = 'week' AND bucket <= ?)`
+	err := q.db.ExecRaw(query, before.Add(-time.Hour), before.AddDate(0, 0, -1), before.AddDate(0, 0, -7))
+	return errors.Wrap(err, "failed to prune stats participants")
+}
+
+// Restore rebuilds the participants of the buckets holding the block or
+// later ones, an address first appeared in the bucket at its earliest
+// stored transfer. Participants still stored are kept as they are.
+func (q *transferStatsQ) Restore(fromBlock uint64) error {
+	query := `INSERT INTO transfer_stats_participants (granularity, bucket, address, role, first_block)
+              SELECT granularity, bucket, address, role, MIN(block_number) FROM (
+                  SELECT granularity, bucket, from_address AS address, 'sender' AS role, block_number FROM (` + bucketsSince + `) b
+                  UNION ALL
+                  SELECT granularity, bucket, to_address, 'receiver', block_number FROM (` + bucketsSince + `) b
+              ) p
+              GROUP BY granularity, bucket, address, role
+              ON CONFLICT DO NOTHING`
+	err := q.db.ExecRaw(query, fromBlock, fromBlock)
+	return errors.Wrap(err, "failed to restore stats participants")
+}
+
+// addUniqueCounts adds (sign 1) or subtracts (sign -1) participants that
+// first appeared in the block to the unique counters
+func (q *transferStatsQ) addUniqueCounts(blockNumber uint64, sign int) error {
+	query := `UPDATE transfer_stats
+              SET unique_senders = transfer_stats.unique_senders + ? * p.senders,
+                  unique_receivers = transfer_stats.unique_receivers + ? * p.receivers
+              FROM (SELECT granularity, bucket,
+                           COUNT(*) FILTER (WHERE role = 'sender') AS senders,
+                           COUNT(*) FILTER (WHERE role = 'receiver') AS receivers
+                    FROM transfer_stats_participants WHERE first_block = ?
+                    GROUP BY granularity, bucket) p
+              WHERE transfer_stats.granularity = p.granularity AND transfer_stats.bucket = p.bucket`
+	return q.db.ExecRaw(query, sign, sign, blockNumber)
+}
+
+func (q *transferStatsQ) FilterByGranularity(granularity string) data.TransferStatsQ {
+	q.sql = q.sql.Where(sq.Eq{"granularity": granularity})
+	return q
+}
+
+func (q *transferStatsQ) FilterByBucketRange(from, to time.Time) data.TransferStatsQ {
+	q.sql = q.sql.Where(sq.And{
+		sq.GtOrEq{"bucket": from},
+		sq.Lt{"bucket": to},
+	})
+	return q
+}
@@ -0,0 +1,48 @@
+package pg
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gitlab.com/distributed_lab/kit/pgdb"
+)
+
+// execQueryer keeps the arguments of the executed statement
+type execQueryer struct {
+	pgdb.Queryer
+	args *[]interface{}
+}
+
+func (q execQueryer) ExecRawContext(_ context.Context, _ string, args ...interface{}) error {
+	*q.args = args
+	return nil
+}
+
+func TestPruneClosedBuckets(t *testing.T) {
+	var args []interface{}
+	q := NewTransferStatsQ(&DB{
+		DB:  &pgdb.DB{Queryer: execQueryer{args: &args}},
+		ctx: context.Background(),
+	})
+
+	before := time.Date(2024, 8, 22, 10, 30, 0, 0, time.UTC)
+	if err := q.Prune(before); err != nil {
+		t.Fatalf("failed to prune participants: %v", err)
+	}
+
+	// the hour, day and week buckets that ended by the moment
+	want := []time.Time{
+		time.Date(2024, 8, 22, 9, 30, 0, 0, time.UTC),
+		time.Date(2024, 8, 21, 10, 30, 0, 0, time.UTC),
+		time.Date(2024, 8, 15, 10, 30, 0, 0, time.UTC),
+	}
+	if len(args) != len(want) {
+		t.Fatalf("got arguments %v, want %v", args, want)
+	}
+	for i, bucket := range want {
+		if !args[i].(time.Time).Equal(bucket) {
+			t.Errorf("argument %d is %v, want %v", i, args[i], bucket)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package pg
+
+import (
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	sq "github.com/Masterminds/squirrel"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const usdtContractsTableName = "usdt_contracts"
+
+func NewUSDTContractQ(db *DB) data.USDTContractQ {
+	return &usdtContractQ{
+		db:  db,
+		sql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar).Select("*").From(usdtContractsTableName),
+	}
+}
+
+type usdtContractQ struct {
+	db  *DB
+	sql sq.SelectBuilder
+}
+
+func (q *usdtContractQ) New() data.USDTContractQ {
+	return NewUSDTContractQ(q.db)
+}
+
+func (q *usdtContractQ) Select() ([]data.USDTContract, error) {
+	var result []data.USDTContract
+	err := q.db.Select(&result, q.sql.OrderBy("start_block ASC", "predecessor NULLS FIRST"))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select USDT contracts from db")
+	}
+	return result, nil
+}
+
+func (q *usdtContractQ) Upgrade(contract data.USDTContract) error {
+	if contract.Predecessor != nil {
+		stmt := sq.Update(usdtContractsTableName).
+			Set("deprecated_block", contract.StartBlock).
+			Where(sq.Eq{"address": *contract.Predecessor})
+		if err := q.db.Exec(stmt); err != nil {
+			return errors.Wrap(err, "failed to mark USDT contract deprecated")
+		}
+	}
+
+	clauses := map[string]interface{}{
+		"address":     contract.Address,
+		"start_block": contract.StartBlock,
+		"predecessor": contract.Predecessor,
+		"log_index":   contract.LogIndex,
+	}
+	if err := q.db.Exec(sq.Insert(usdtContractsTableName).SetMap(clauses)); err != nil {
+		return errors.Wrap(err, "failed to insert USDT contract")
+	}
+	return nil
+}
+
+func (q *usdtContractQ) RevertBlock(blockNumber uint64) error {
+	query := `WITH reverted AS (
+                  DELETE FROM usdt_contracts WHERE start_block = ? AND log_index IS NOT NULL
+                  RETURNING predecessor
+              )
+              UPDATE usdt_contracts SET deprecated_block = NULL
+              WHERE address IN (SELECT predecessor FROM reverted)`
+
+	if err := q.db.ExecRaw(query, blockNumber); err != nil {
+		return errors.Wrap(err, "failed to revert USDT contract upgrades")
+	}
+	return nil
+}
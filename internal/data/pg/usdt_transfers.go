@@ -4,6 +4,8 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
 	sq "github.com/Masterminds/squirrel"
@@ -13,7 +15,7 @@ import (
 
 const usdtTransfersTableName = "usdt_transfers"
 
-func NewUSDTTransferQ(db *pgdb.DB) data.USDTTransferQ {
+func NewUSDTTransferQ(db *DB) data.USDTTransferQ {
 	return &usdtTransferQ{
 		db:  db,
 		sql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar).Select("*").From(usdtTransfersTableName),
@@ -21,7 +23,7 @@ func NewUSDTTransferQ(db *pgdb.DB) data.USDTTransferQ {
 }
 
 type usdtTransferQ struct {
-	db  *pgdb.DB
+	db  *DB
 	sql sq.SelectBuilder
 }
 
@@ -55,15 +57,55 @@ func (q *usdtTransferQ) Select() ([]data.USDTTransfer, error) {
 	return result, nil
 }
 
+// transfersCursors numbers the cursors of Each, a transaction may hold
+// several of them at once
+var transfersCursors atomic.Uint64
+
+func (q *usdtTransferQ) Each(batchSize uint64, fn func(batch []data.USDTTransfer) error) error {
+	query, args, err := q.sql.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "failed to build transfers query")
+	}
+
+	// cursors only live within the transaction of the caller, outside of one
+	// postgres rejects the declaration
+	cursor := fmt.Sprintf("usdt_transfers_cursor_%d", transfersCursors.Add(1))
+	err = q.db.ExecRaw("DECLARE "+cursor+" NO SCROLL CURSOR FOR "+query, args...)
+	if err != nil {
+		return errors.Wrap(err, "failed to declare transfers cursor")
+	}
+
+	fetch := fmt.Sprintf("FETCH FORWARD %d FROM %s", batchSize, cursor)
+	for {
+		var batch []data.USDTTransfer
+		if err := q.db.SelectRaw(&batch, fetch); err != nil {
+			return errors.Wrap(err, "failed to fetch transfers from cursor")
+		}
+		if len(batch) == 0 {
+			break
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+		if uint64(len(batch)) < batchSize {
+			break
+		}
+	}
+
+	return errors.Wrap(q.db.ExecRaw("CLOSE "+cursor), "failed to close transfers cursor")
+}
+
 func (q *usdtTransferQ) Insert(transfer data.USDTTransfer) (*data.USDTTransfer, error) {
 	clauses := map[string]interface{}{
-		"from_address":     transfer.FromAddress,
-		"to_address":       transfer.ToAddress,
-		"amount":           transfer.Amount,
-		"transaction_hash": transfer.TransactionHash,
-		"block_number":     transfer.BlockNumber,
-		"log_index":        transfer.LogIndex,
-		"timestamp":        transfer.Timestamp,
+		"from_address":        transfer.FromAddress,
+		"to_address":          transfer.ToAddress,
+		"amount":              transfer.Amount,
+		"transaction_hash":    transfer.TransactionHash,
+		"block_number":        transfer.BlockNumber,
+		"log_index":           transfer.LogIndex,
+		"timestamp":           transfer.Timestamp,
+		"fee":                 transfer.Fee,
+		"principal_log_index": transfer.PrincipalLogIndex,
 	}
 	var result data.USDTTransfer
 	stmt := sq.Insert(usdtTransfersTableName).SetMap(clauses).Suffix("RETURNING *")
@@ -76,13 +118,15 @@ func (q *usdtTransferQ) Insert(transfer data.USDTTransfer) (*data.USDTTransfer,
 
 func (q *usdtTransferQ) InsertIgnore(transfer data.USDTTransfer) (*data.USDTTransfer, error) {
     clauses := map[string]interface{}{
-        "from_address":     transfer.FromAddress,
-        "to_address":       transfer.ToAddress,
-        "amount":           transfer.Amount,
-        "transaction_hash": transfer.TransactionHash,
-        "block_number":     transfer.BlockNumber,
-        "log_index":        transfer.LogIndex,
-        "timestamp":        transfer.Timestamp,
+        "from_address":        transfer.FromAddress,
+        "to_address":          transfer.ToAddress,
+        "amount":              transfer.Amount,
+        "transaction_hash":    transfer.TransactionHash,
+        "block_number":        transfer.BlockNumber,
+        "log_index":           transfer.LogIndex,
+        "timestamp":           transfer.Timestamp,
+        "fee":                 transfer.Fee,
+        "principal_log_index": transfer.PrincipalLogIndex,
     }
     var result data.USDTTransfer
 	stmt := sq.Insert(usdtTransfersTableName).SetMap(clauses).Suffix("ON CONFLICT (block_number, log_index) DO NOTHING RETURNING *")
@@ -103,10 +147,10 @@ func (q *usdtTransferQ) InsertBlock(transfers []data.USDTTransfer) error {
 
     query := `INSERT INTO usdt_transfers 
               (from_address, to_address, amount, transaction_hash, 
-               block_number, log_index, timestamp) 
+               block_number, log_index, timestamp, fee, principal_log_index) 
               VALUES `
 
-    values := make([]interface{}, 0, len(transfers)*7)
+    values := make([]interface{}, 0, len(transfers)*9)
     placeholders := make([]string, 0, len(transfers))
 
     for i, transfer := range transfers {
@@ -118,9 +162,11 @@ func (q *usdtTransferQ) InsertBlock(transfers []data.USDTTransfer) error {
             transfer.BlockNumber,
             transfer.LogIndex,
             transfer.Timestamp,
+            transfer.Fee,
+            transfer.PrincipalLogIndex,
         )
-        placeholders = append(placeholders, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d)",
-            i*7+1, i*7+2, i*7+3, i*7+4, i*7+5, i*7+6, i*7+7,
+        placeholders = append(placeholders, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)",
+            i*9+1, i*9+2, i*9+3, i*9+4, i*9+5, i*9+6, i*9+7, i*9+8, i*9+9,
         ))
     }
 
@@ -140,18 +186,29 @@ func (q *usdtTransferQ) DeleteLastProcessedBlock(blockNumber uint64) error {
     return errors.Wrap(err, "failed to delete transactions for the last processed block")
 }
 
+func (q *usdtTransferQ) DeleteBlockRange(fromBlock, toBlock uint64) error {
+	stmt := sq.Delete(usdtTransfersTableName).Where(sq.And{
+		sq.GtOrEq{"block_number": fromBlock},
+		sq.LtOrEq{"block_number": toBlock},
+	})
+	err := q.db.Exec(stmt)
+	return errors.Wrap(err, "failed to delete transfers of the block range")
+}
+
 func (q *usdtTransferQ) Update(transfer data.USDTTransfer) (*data.USDTTransfer, error) {
 	clauses := map[string]interface{}{
-		"from_address":     transfer.FromAddress,
-		"to_address":       transfer.ToAddress,
-		"amount":           transfer.Amount,
-		"transaction_hash": transfer.TransactionHash,
-		"block_number":     transfer.BlockNumber,
-		"log_index":        transfer.LogIndex,
-		"timestamp":        transfer.Timestamp,
+		"from_address":        transfer.FromAddress,
+		"to_address":          transfer.ToAddress,
+		"amount":              transfer.Amount,
+		"transaction_hash":    transfer.TransactionHash,
+		"block_number":        transfer.BlockNumber,
+		"log_index":           transfer.LogIndex,
+		"timestamp":           transfer.Timestamp,
+		"fee":                 transfer.Fee,
+		"principal_log_index": transfer.PrincipalLogIndex,
 	}
 	var result data.USDTTransfer
-	stmt := sq.Update(usdtTransfersTableName).SetMap(clauses).Where(sq.Eq{"id": transfer.ID}).Suffix("RETURNING *")
+	stmt := sq.Update(usdtTransfersTableName).SetMap(clauses).Where(sq.Eq{"id": transfer.ID, "block_number": transfer.BlockNumber}).Suffix("RETURNING *")
 	err := q.db.Get(&result, stmt)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to update USDT transfer in db")
@@ -179,11 +236,66 @@ func (q *usdtTransferQ) FilterByBlockNumber(blockNumber uint64) data.USDTTransfe
 	return q
 }
 
+func (q *usdtTransferQ) FilterByBlockRange(fromBlock, toBlock uint64) data.USDTTransferQ {
+	q.sql = q.sql.Where(sq.And{sq.GtOrEq{"block_number": fromBlock}, sq.LtOrEq{"block_number": toBlock}})
+	return q
+}
+
 func (q *usdtTransferQ) FilterByTransactionHash(hash string) data.USDTTransferQ {
 	q.sql = q.sql.Where(sq.Eq{"transaction_hash": hash})
 	return q
 }
 
+func (q *usdtTransferQ) FilterByMaxTimestamp(timestamp time.Time) data.USDTTransferQ {
+	q.sql = q.sql.Where(sq.LtOrEq{"timestamp": timestamp})
+	return q
+}
+
+func (q *usdtTransferQ) FilterByAddress(address string) data.USDTTransferQ {
+	q.sql = q.sql.Where(sq.Or{sq.Eq{"from_address": address}, sq.Eq{"to_address": address}})
+	return q
+}
+
+func (q *usdtTransferQ) FilterByMinAmount(amount string) data.USDTTransferQ {
+	q.sql = q.sql.Where(sq.GtOrEq{"amount": amount})
+	return q
+}
+
+func (q *usdtTransferQ) FilterByMaxAmount(amount string) data.USDTTransferQ {
+	q.sql = q.sql.Where(sq.LtOrEq{"amount": amount})
+	return q
+}
+
+func (q *usdtTransferQ) FilterAfterCursor(blockNumber uint64, logIndex int64) data.USDTTransferQ {
+	// the row comparison alone doesn't prune partitions
+	q.sql = q.sql.Where(sq.GtOrEq{"block_number": blockNumber}).
+		Where("(block_number, log_index) > (?, ?)", blockNumber, logIndex)
+	return q
+}
+
+func (q *usdtTransferQ) FilterByWatchlist(watchlistID int64, direction string) data.USDTTransferQ {
+	return q.filterByAddressSet(direction, "SELECT address FROM watchlist_addresses WHERE watchlist_id = ?", watchlistID)
+}
+
+func (q *usdtTransferQ) FilterByLabel(label string, direction string) data.USDTTransferQ {
+	return q.filterByAddressSet(direction, "SELECT address FROM address_labels WHERE label = ?", label)
+}
+
+func (q *usdtTransferQ) filterByAddressSet(direction, addresses string, args ...interface{}) data.USDTTransferQ {
+	from := sq.Expr("from_address IN ("+addresses+")", args...)
+	to := sq.Expr("to_address IN ("+addresses+")", args...)
+
+	switch direction {
+	case data.DirectionFrom:
+		q.sql = q.sql.Where(from)
+	case data.DirectionTo:
+		q.sql = q.sql.Where(to)
+	default:
+		q.sql = q.sql.Where(sq.Or{from, to})
+	}
+	return q
+}
+
 func (q *usdtTransferQ) OrderByTimestamp(desc bool) data.USDTTransferQ {
 	if desc {
 		q.sql = q.sql.OrderBy("timestamp DESC")
@@ -193,6 +305,11 @@ func (q *usdtTransferQ) OrderByTimestamp(desc bool) data.USDTTransferQ {
 	return q
 }
 
+func (q *usdtTransferQ) OrderByCursor() data.USDTTransferQ {
+	q.sql = q.sql.OrderBy("block_number ASC", "log_index ASC")
+	return q
+}
+
 func (q *usdtTransferQ) Limit(limit uint64) data.USDTTransferQ {
 	q.sql = q.sql.Limit(limit)
 	return q
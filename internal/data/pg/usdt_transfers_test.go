@@ -0,0 +1,73 @@
+package pg
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"gitlab.com/distributed_lab/kit/pgdb"
+)
+
+// cursorQueryer records the statements run on cursors, every fetch returns
+// a single transfer
+type cursorQueryer struct {
+	pgdb.Queryer
+	statements *[]string
+}
+
+func (q cursorQueryer) ExecRawContext(_ context.Context, query string, _ ...interface{}) error {
+	*q.statements = append(*q.statements, query)
+	return nil
+}
+
+func (q cursorQueryer) SelectRawContext(_ context.Context, dest interface{}, query string, _ ...interface{}) error {
+	*q.statements = append(*q.statements, query)
+	*dest.(*[]data.USDTTransfer) = []data.USDTTransfer{{ID: 1}}
+	return nil
+}
+
+func TestEachNestedCursors(t *testing.T) {
+	var statements []string
+	// the DB has no connection, so Each can't open a transaction of its own
+	q := &masterQ{
+		db: &DB{
+			DB:  &pgdb.DB{Queryer: cursorQueryer{statements: &statements}},
+			ctx: context.Background(),
+		},
+	}
+
+	err := q.USDTTransfer().OrderByCursor().Each(10, func([]data.USDTTransfer) error {
+		return q.USDTTransfer().OrderByCursor().Each(10, func([]data.USDTTransfer) error { return nil })
+	})
+	if err != nil {
+		t.Fatalf("failed to read transfers: %v", err)
+	}
+
+	var cursors []string
+	for _, statement := range statements {
+		if strings.HasPrefix(statement, "DECLARE ") {
+			cursors = append(cursors, strings.Fields(statement)[1])
+		}
+	}
+	if len(cursors) != 2 || cursors[0] == cursors[1] {
+		t.Fatalf("got cursors %v, want two with different names", cursors)
+	}
+
+	want := []string{
+		"DECLARE " + cursors[0],
+		"FETCH FORWARD 10 FROM " + cursors[0],
+		"DECLARE " + cursors[1],
+		"FETCH FORWARD 10 FROM " + cursors[1],
+		"CLOSE " + cursors[1],
+		"CLOSE " + cursors[0],
+	}
+	if len(statements) != len(want) {
+		t.Fatalf("got statements %q, want %d", statements, len(want))
+	}
+	for i, prefix := range want {
+		if !strings.HasPrefix(statements[i], prefix) {
+			t.Errorf("statement %d is %q, want %q", i, statements[i], prefix)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package pg
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	sq "github.com/Masterminds/squirrel"
+	"gitlab.com/distributed_lab/kit/pgdb"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const watchlistAddressesTableName = "watchlist_addresses"
+
+func NewWatchlistAddressQ(db *DB) data.WatchlistAddressQ {
+	return &watchlistAddressQ{
+		db:  db,
+		sql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar).Select("*").From(watchlistAddressesTableName),
+		del: sq.Delete(watchlistAddressesTableName),
+	}
+}
+
+type watchlistAddressQ struct {
+	db  *DB
+	sql sq.SelectBuilder
+	del sq.DeleteBuilder
+}
+
+func (q *watchlistAddressQ) New() data.WatchlistAddressQ {
+	return NewWatchlistAddressQ(q.db)
+}
+
+func (q *watchlistAddressQ) Select() ([]data.WatchlistAddress, error) {
+	var result []data.WatchlistAddress
+	err := q.db.Select(&result, q.sql)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select watchlist addresses from db")
+	}
+	return result, nil
+}
+
+func (q *watchlistAddressQ) Insert(watchlistID int64, addresses ...string) error {
+	for start := 0; start < len(addresses); start += upsertBatchSize {
+		end := start + upsertBatchSize
+		if end > len(addresses) {
+			end = len(addresses)
+		}
+
+		batch := addresses[start:end]
+		values := make([]interface{}, 0, len(batch)*2)
+		placeholders := make([]string, 0, len(batch))
+
+		for i, address := range batch {
+			values = append(values, watchlistID, address)
+			placeholders = append(placeholders, fmt.Sprintf("($%d, $%d)", i*2+1, i*2+2))
+		}
+
+		query := `INSERT INTO watchlist_addresses (watchlist_id, address) VALUES ` +
+			strings.Join(placeholders, ", ") + ` ON CONFLICT DO NOTHING`
+
+		if err := q.db.ExecRaw(query, values...); err != nil {
+			return errors.Wrap(err, "failed to insert watchlist addresses")
+		}
+	}
+	return nil
+}
+
+func (q *watchlistAddressQ) Delete() error {
+	err := q.db.Exec(q.del)
+	return errors.Wrap(err, "failed to delete watchlist addresses from db")
+}
+
+func (q *watchlistAddressQ) FilterByWatchlistID(watchlistID int64) data.WatchlistAddressQ {
+	q.sql = q.sql.Where(sq.Eq{"watchlist_id": watchlistID})
+	q.del = q.del.Where(sq.Eq{"watchlist_id": watchlistID})
+	return q
+}
+
+func (q *watchlistAddressQ) FilterByAddress(addresses ...string) data.WatchlistAddressQ {
+	q.sql = q.sql.Where(sq.Eq{"address": addresses})
+	q.del = q.del.Where(sq.Eq{"address": addresses})
+	return q
+}
+
+func (q *watchlistAddressQ) Page(pageParams *pgdb.OffsetPageParams) data.WatchlistAddressQ {
+	q.sql = pageParams.ApplyTo(q.sql, "address")
+	return q
+}
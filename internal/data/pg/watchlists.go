@@ -0,0 +1,87 @@
+package pg
+
+import (
+	"database/sql"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	sq "github.com/Masterminds/squirrel"
+	"gitlab.com/distributed_lab/kit/pgdb"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const watchlistsTableName = "watchlists"
+
+func NewWatchlistQ(db *DB) data.WatchlistQ {
+	return &watchlistQ{
+		db:  db,
+		sql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar).Select("*").From(watchlistsTableName),
+		del: sq.Delete(watchlistsTableName),
+	}
+}
+
+type watchlistQ struct {
+	db  *DB
+	sql sq.SelectBuilder
+	del sq.DeleteBuilder
+}
+
+func (q *watchlistQ) New() data.WatchlistQ {
+	return NewWatchlistQ(q.db)
+}
+
+func (q *watchlistQ) Get() (*data.Watchlist, error) {
+	var result data.Watchlist
+	err := q.db.Get(&result, q.sql)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get watchlist from db")
+	}
+	return &result, nil
+}
+
+func (q *watchlistQ) Select() ([]data.Watchlist, error) {
+	var result []data.Watchlist
+	err := q.db.Select(&result, q.sql)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select watchlists from db")
+	}
+	return result, nil
+}
+
+func (q *watchlistQ) Insert(watchlist data.Watchlist) (*data.Watchlist, error) {
+	clauses := map[string]interface{}{
+		"name":        watchlist.Name,
+		"description": watchlist.Description,
+	}
+	var result data.Watchlist
+	stmt := sq.Insert(watchlistsTableName).SetMap(clauses).Suffix("RETURNING *")
+	err := q.db.Get(&result, stmt)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to insert watchlist to db")
+	}
+	return &result, nil
+}
+
+func (q *watchlistQ) Delete() error {
+	err := q.db.Exec(q.del)
+	return errors.Wrap(err, "failed to delete watchlists from db")
+}
+
+func (q *watchlistQ) FilterByID(ids ...int64) data.WatchlistQ {
+	q.sql = q.sql.Where(sq.Eq{"id": ids})
+	q.del = q.del.Where(sq.Eq{"id": ids})
+	return q
+}
+
+func (q *watchlistQ) FilterByName(name string) data.WatchlistQ {
+	q.sql = q.sql.Where(sq.Eq{"name": name})
+	q.del = q.del.Where(sq.Eq{"name": name})
+	return q
+}
+
+func (q *watchlistQ) Page(pageParams *pgdb.OffsetPageParams) data.WatchlistQ {
+	q.sql = pageParams.ApplyTo(q.sql, "id")
+	return q
+}
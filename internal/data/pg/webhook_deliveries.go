@@ -0,0 +1,167 @@
+package pg
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	sq "github.com/Masterminds/squirrel"
+	"gitlab.com/distributed_lab/kit/pgdb"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const webhookDeliveriesTableName = "webhook_deliveries"
+
+func NewWebhookDeliveryQ(db *DB) data.WebhookDeliveryQ {
+	return &webhookDeliveryQ{
+		db:  db,
+		sql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar).Select("*").From(webhookDeliveriesTableName),
+	}
+}
+
+type webhookDeliveryQ struct {
+	db  *DB
+	sql sq.SelectBuilder
+}
+
+func (q *webhookDeliveryQ) New() data.WebhookDeliveryQ {
+	return NewWebhookDeliveryQ(q.db)
+}
+
+func (q *webhookDeliveryQ) Get() (*data.WebhookDelivery, error) {
+	var result data.WebhookDelivery
+	err := q.db.Get(&result, q.sql)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get webhook delivery from db")
+	}
+	return &result, nil
+}
+
+func (q *webhookDeliveryQ) Select() ([]data.WebhookDelivery, error) {
+	var result []data.WebhookDelivery
+	err := q.db.Select(&result, q.sql)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select webhook deliveries from db")
+	}
+	return result, nil
+}
+
+func (q *webhookDeliveryQ) EnqueueBlock(blockNumber uint64, eventType string) error {
+	query := `INSERT INTO webhook_deliveries (webhook_id, event_type, block_number, log_index, payload)
+              SELECT w.id, ?, t.block_number, t.log_index, json_build_object(
+                  'id', t.id,
+                  'from_address', t.from_address,
+                  'to_address', t.to_address,
+                  'amount', t.amount::TEXT,
+                  'fee', t.fee::TEXT,
+                  'transaction_hash', t.transaction_hash,
+                  'block_number', t.block_number,
+                  'log_index', t.log_index,
+                  'timestamp', t.timestamp)
+              FROM usdt_transfers t
+              JOIN webhooks w ON (cardinality(w.addresses) = 0
+                                  OR t.from_address::TEXT = ANY(w.addresses)
+                                  OR t.to_address::TEXT = ANY(w.addresses))
+                             AND (w.min_amount IS NULL OR t.amount >= w.min_amount)
+              WHERE t.block_number = ?
+              ORDER BY t.log_index, w.id`
+
+	if err := q.db.ExecRaw(query, eventType, blockNumber); err != nil {
+		return errors.Wrap(err, "failed to enqueue webhook deliveries")
+	}
+	return nil
+}
+
+func (q *webhookDeliveryQ) MarkDelivered(id int64) error {
+	stmt := sq.Update(webhookDeliveriesTableName).
+		SetMap(map[string]interface{}{
+			"status":       data.DeliveryStatusDelivered,
+			"attempts":     sq.Expr("attempts + 1"),
+			"delivered_at": time.Now().UTC(),
+			"last_error":   nil,
+		}).
+		Where(sq.Eq{"id": id})
+
+	err := q.db.Exec(stmt)
+	return errors.Wrap(err, "failed to mark webhook delivery as delivered")
+}
+
+func (q *webhookDeliveryQ) MarkFailed(id int64, reason string, nextAttemptAt time.Time, dead bool) error {
+	status := data.DeliveryStatusPending
+	if dead {
+		status = data.DeliveryStatusDead
+	}
+
+	stmt := sq.Update(webhookDeliveriesTableName).
+		SetMap(map[string]interface{}{
+			"status":          status,
+			"attempts":        sq.Expr("attempts + 1"),
+			"next_attempt_at": nextAttemptAt,
+			"last_error":      reason,
+		}).
+		Where(sq.Eq{"id": id})
+
+	err := q.db.Exec(stmt)
+	return errors.Wrap(err, "failed to mark webhook delivery as failed")
+}
+
+func (q *webhookDeliveryQ) Redeliver(id int64) (*data.WebhookDelivery, error) {
+	stmt := sq.Update(webhookDeliveriesTableName).
+		SetMap(map[string]interface{}{
+			"status":          data.DeliveryStatusPending,
+			"attempts":        0,
+			"next_attempt_at": time.Now().UTC(),
+			"last_error":      nil,
+		}).
+		Where(sq.Eq{"id": id}).
+		Where(sq.NotEq{"status": data.DeliveryStatusDelivered}).
+		Suffix("RETURNING *")
+
+	var result data.WebhookDelivery
+	err := q.db.Get(&result, stmt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to reset webhook delivery")
+	}
+	return &result, nil
+}
+
+func (q *webhookDeliveryQ) FilterByID(id int64) data.WebhookDeliveryQ {
+	q.sql = q.sql.Where(sq.Eq{"id": id})
+	return q
+}
+
+func (q *webhookDeliveryQ) FilterByWebhookID(webhookID int64) data.WebhookDeliveryQ {
+	q.sql = q.sql.Where(sq.Eq{"webhook_id": webhookID})
+	return q
+}
+
+func (q *webhookDeliveryQ) FilterByStatus(status string) data.WebhookDeliveryQ {
+	q.sql = q.sql.Where(sq.Eq{"status": status})
+	return q
+}
+
+func (q *webhookDeliveryQ) FilterDue(now time.Time) data.WebhookDeliveryQ {
+	q.sql = q.sql.Where(sq.Eq{"status": data.DeliveryStatusPending}).Where(sq.LtOrEq{"next_attempt_at": now})
+	return q
+}
+
+func (q *webhookDeliveryQ) OrderByID() data.WebhookDeliveryQ {
+	q.sql = q.sql.OrderBy("id ASC")
+	return q
+}
+
+func (q *webhookDeliveryQ) Limit(limit uint64) data.WebhookDeliveryQ {
+	q.sql = q.sql.Limit(limit)
+	return q
+}
+
+func (q *webhookDeliveryQ) Page(pageParams *pgdb.OffsetPageParams) data.WebhookDeliveryQ {
+	q.sql = pageParams.ApplyTo(q.sql, "id")
+	return q
+}
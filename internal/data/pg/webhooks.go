@@ -0,0 +1,83 @@
+package pg
+
+import (
+	"database/sql"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	sq "github.com/Masterminds/squirrel"
+	"gitlab.com/distributed_lab/kit/pgdb"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+const webhooksTableName = "webhooks"
+
+func NewWebhookQ(db *DB) data.WebhookQ {
+	return &webhookQ{
+		db:  db,
+		sql: sq.StatementBuilder.PlaceholderFormat(sq.Dollar).Select("*").From(webhooksTableName),
+		del: sq.Delete(webhooksTableName),
+	}
+}
+
+type webhookQ struct {
+	db  *DB
+	sql sq.SelectBuilder
+	del sq.DeleteBuilder
+}
+
+func (q *webhookQ) New() data.WebhookQ {
+	return NewWebhookQ(q.db)
+}
+
+func (q *webhookQ) Get() (*data.Webhook, error) {
+	var result data.Webhook
+	err := q.db.Get(&result, q.sql)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get webhook from db")
+	}
+	return &result, nil
+}
+
+func (q *webhookQ) Select() ([]data.Webhook, error) {
+	var result []data.Webhook
+	err := q.db.Select(&result, q.sql)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select webhooks from db")
+	}
+	return result, nil
+}
+
+func (q *webhookQ) Insert(webhook data.Webhook) (*data.Webhook, error) {
+	clauses := map[string]interface{}{
+		"url":        webhook.URL,
+		"secret":     webhook.Secret,
+		"addresses":  webhook.Addresses,
+		"min_amount": webhook.MinAmount,
+	}
+	var result data.Webhook
+	stmt := sq.Insert(webhooksTableName).SetMap(clauses).Suffix("RETURNING *")
+	err := q.db.Get(&result, stmt)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to insert webhook to db")
+	}
+	return &result, nil
+}
+
+func (q *webhookQ) Delete() error {
+	err := q.db.Exec(q.del)
+	return errors.Wrap(err, "failed to delete webhooks from db")
+}
+
+func (q *webhookQ) FilterByID(ids ...int64) data.WebhookQ {
+	q.sql = q.sql.Where(sq.Eq{"id": ids})
+	q.del = q.del.Where(sq.Eq{"id": ids})
+	return q
+}
+
+func (q *webhookQ) Page(pageParams *pgdb.OffsetPageParams) data.WebhookQ {
+	q.sql = pageParams.ApplyTo(q.sql, "id")
+	return q
+}
@@ -0,0 +1,45 @@
+package data
+
+// PublisherPosition is the (block, log index) of the last transfer a message
+// broker publisher delivered. LogIndex -1 points right before the block.
+type PublisherPosition struct {
+	Name        string `db:"name"`
+	BlockNumber uint64 `db:"block_number"`
+	LogIndex    int64  `db:"log_index"`
+}
+
+type PublisherPositionQ interface {
+	New() PublisherPositionQ
+
+	// Get returns the position of the publisher, nil if it never published
+	Get(name string) (*PublisherPosition, error)
+	// Lock returns the position like Get and locks it until the end of the
+	// transaction, so rollbacks wait for the batch being published
+	Lock(name string) (*PublisherPosition, error)
+	Upsert(position PublisherPosition) error
+	// Rewind moves every position at or after the block right before it
+	Rewind(blockNumber uint64) error
+}
+
+// PublisherRetraction is a published transfer of an orphaned block waiting
+// for its retraction to be published
+type PublisherRetraction struct {
+	ID   int64  `db:"retraction_id"`
+	Name string `db:"name"`
+	USDTTransfer
+}
+
+type PublisherRetractionQ interface {
+	New() PublisherRetractionQ
+
+	// Select returns the retractions in the order they were enqueued
+	Select() ([]PublisherRetraction, error)
+	// EnqueueBlock writes a retraction of every transfer of the block each
+	// publisher has published, it has to run before the publishers are
+	// rewound and the transfers are deleted
+	EnqueueBlock(blockNumber uint64) error
+	Delete(ids ...int64) error
+
+	FilterByName(name string) PublisherRetractionQ
+	Limit(limit uint64) PublisherRetractionQ
+}
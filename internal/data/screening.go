@@ -0,0 +1,124 @@
+package data
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/resources"
+	"github.com/lib/pq"
+)
+
+// TransferFlags is the bit mask of compliance flags of a transfer, it is
+// rendered as resources.Flags
+type TransferFlags int32
+
+const (
+	FlagSanctionedSender TransferFlags = 1 << iota
+	FlagSanctionedReceiver
+	FlagBlacklistedSender
+	FlagBlacklistedReceiver
+)
+
+// Flags raised for either side of a transfer
+const (
+	FlagsSender   = FlagSanctionedSender | FlagBlacklistedSender
+	FlagsReceiver = FlagSanctionedReceiver | FlagBlacklistedReceiver
+)
+
+// TransferFlagNames names every transfer flag
+var TransferFlagNames = map[int32]string{
+	int32(FlagSanctionedSender):    "sanctioned_sender",
+	int32(FlagSanctionedReceiver):  "sanctioned_receiver",
+	int32(FlagBlacklistedSender):   "blacklisted_sender",
+	int32(FlagBlacklistedReceiver): "blacklisted_receiver",
+}
+
+// Resource returns the flags ordered by value
+func (f TransferFlags) Resource() resources.Flags {
+	flags := resources.FlagsFromMask(int32(f), TransferFlagNames)
+	sort.Slice(flags.Values, func(i, j int) bool {
+		return flags.Values[i].Value < flags.Values[j].Value
+	})
+	return flags
+}
+
+func (f TransferFlags) MarshalJSON() ([]byte, error) {
+	return json.Marshal(f.Resource())
+}
+
+// SanctionsList is an imported list of sanctioned addresses, e.g. OFAC SDN
+type SanctionsList struct {
+	Name         string    `db:"name"`
+	Source       string    `db:"source"`
+	AddressCount int64     `db:"address_count"`
+	ImportedAt   time.Time `db:"imported_at"`
+}
+
+// BlacklistEvent is an AddedBlackList (Blacklisted set) or RemovedBlackList
+// event of the USDT contract
+type BlacklistEvent struct {
+	Address     string `db:"address"`
+	Blacklisted bool   `db:"blacklisted"`
+	BlockNumber uint64 `db:"block_number"`
+	LogIndex    int64  `db:"log_index"`
+}
+
+type FlagStat struct {
+	Flag      string `db:"-"`
+	Mask      int32  `db:"mask"`
+	Transfers int64  `db:"transfers"`
+	Volume    string `db:"volume"`
+}
+
+type FlaggedAddress struct {
+	Address     string         `db:"address"`
+	Lists       pq.StringArray `db:"lists"`
+	Blacklisted bool           `db:"blacklisted"`
+	Transfers   int64          `db:"transfers"`
+	Volume      string         `db:"volume"`
+}
+
+// ScreeningReport summarizes flagged transfers within [From, To)
+type ScreeningReport struct {
+	From             time.Time
+	To               time.Time
+	TotalTransfers   int64
+	FlaggedTransfers int64
+	FlaggedVolume    string
+	Flags            []FlagStat
+	// Addresses are the flagged parties with the largest flagged volume
+	Addresses []FlaggedAddress
+	Lists     []SanctionsList
+}
+
+type SanctionsListQ interface {
+	New() SanctionsListQ
+
+	Get() (*SanctionsList, error)
+	Select() ([]SanctionsList, error)
+	// Replace sets the addresses of the list and returns the added and
+	// removed ones
+	Replace(list SanctionsList, addresses []string) ([]string, error)
+
+	FilterByName(name string) SanctionsListQ
+}
+
+type BlacklistEventQ interface {
+	New() BlacklistEventQ
+
+	InsertBatch(events []BlacklistEvent) error
+	DeleteByBlockNumber(blockNumber uint64) error
+}
+
+type ScreeningQ interface {
+	New() ScreeningQ
+
+	// ScreenBlock sets flags of the transfers of the block
+	ScreenBlock(blockNumber uint64) error
+	// Rescreen recomputes flags of all transfers touching the addresses
+	Rescreen(addresses []string) error
+	// Report summarizes transfers within [from, to), listing up to limit
+	// flagged addresses
+	Report(from, to time.Time, limit uint64) (*ScreeningReport, error)
+}
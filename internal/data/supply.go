@@ -0,0 +1,91 @@
+package data
+
+import (
+	"time"
+
+	"gitlab.com/distributed_lab/kit/pgdb"
+)
+
+// Kinds of the events changing the total supply
+const (
+	SupplyChangeIssue               = "issue"
+	SupplyChangeRedeem              = "redeem"
+	SupplyChangeDestroyedBlackFunds = "destroyed_black_funds"
+)
+
+// SupplyChange is a single mint or burn, Amount is negative for burns
+type SupplyChange struct {
+	BlockNumber uint64    `db:"block_number"`
+	LogIndex    uint64    `db:"log_index"`
+	Kind        string    `db:"kind"`
+	Amount      string    `db:"amount"`
+	Timestamp   time.Time `db:"timestamp"`
+}
+
+// SupplyPoint is the total supply after a block that changed it
+type SupplyPoint struct {
+	BlockNumber uint64    `db:"block_number"`
+	Timestamp   time.Time `db:"timestamp"`
+	Delta       string    `db:"delta"`
+	TotalSupply string    `db:"total_supply"`
+}
+
+// SupplyBucket is the total supply at the end of a bucket together with the
+// amounts minted and burned within it. TotalSupply is nil for buckets before
+// the supply history starts.
+type SupplyBucket struct {
+	Bucket      time.Time `db:"bucket"`
+	TotalSupply *string   `db:"total_supply"`
+	Issued      string    `db:"issued"`
+	Redeemed    string    `db:"redeemed"`
+	Destroyed   string    `db:"destroyed"`
+}
+
+// SupplyCheckpoint compares the indexed total supply with the contract
+// totalSupply at the same block.
+type SupplyCheckpoint struct {
+	BlockNumber   uint64    `db:"block_number"`
+	IndexedSupply string    `db:"indexed_supply"`
+	OnchainSupply string    `db:"onchain_supply"`
+	CreatedAt     time.Time `db:"created_at"`
+}
+
+func (c SupplyCheckpoint) Matches() bool {
+	return c.IndexedSupply == c.OnchainSupply
+}
+
+type SupplyChangeQ interface {
+	New() SupplyChangeQ
+
+	InsertBatch(changes []SupplyChange) error
+	DeleteByBlockNumber(blockNumber uint64) error
+}
+
+type SupplyQ interface {
+	New() SupplyQ
+
+	// Latest returns the most recent supply point or nil if the history is empty
+	Latest() (*SupplyPoint, error)
+	Insert(point SupplyPoint) error
+
+	// ApplyBlock adds the already inserted supply changes of the block on top
+	// of the previous total, blocks without changes are skipped
+	ApplyBlock(blockNumber uint64) error
+	DeleteByBlockNumber(blockNumber uint64) error
+
+	// Series returns one bucket per granularity step in [from, to)
+	Series(granularity string, from, to time.Time) ([]SupplyBucket, error)
+}
+
+type SupplyCheckpointQ interface {
+	New() SupplyCheckpointQ
+
+	Select() ([]SupplyCheckpoint, error)
+	// Insert records the on-chain supply next to the indexed supply at the block
+	Insert(blockNumber uint64, onchainSupply string) (*SupplyCheckpoint, error)
+	DeleteByBlockNumber(blockNumber uint64) error
+
+	FilterMismatched() SupplyCheckpointQ
+
+	Page(pageParams *pgdb.OffsetPageParams) SupplyCheckpointQ
+}
@@ -0,0 +1,43 @@
+package data
+
+import (
+	"time"
+
+	"gitlab.com/distributed_lab/kit/pgdb"
+)
+
+// TransferArchive is a file holding transfers moved out of the database.
+// Transfers of a month are split between files of every archival run.
+type TransferArchive struct {
+	ID            int64     `db:"id"`
+	Month         time.Time `db:"month"`
+	FromBlock     uint64    `db:"from_block"`
+	ToBlock       uint64    `db:"to_block"`
+	MinTransferID int64     `db:"min_transfer_id"`
+	MaxTransferID int64     `db:"max_transfer_id"`
+	TransferCount uint64    `db:"transfer_count"`
+	// Path is relative to the archive directory
+	Path      string    `db:"path"`
+	Size      int64     `db:"size"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type TransferArchiveQ interface {
+	New() TransferArchiveQ
+
+	Get() (*TransferArchive, error)
+	Select() ([]TransferArchive, error)
+	Insert(archive TransferArchive) error
+
+	// LastBlock is the block transfers are archived up to, zero if none are
+	LastBlock() (uint64, error)
+	SetLastBlock(blockNumber uint64) error
+
+	FilterByID(id int64) TransferArchiveQ
+	// FilterByBlockRange keeps archives overlapping the range
+	FilterByBlockRange(fromBlock, toBlock uint64) TransferArchiveQ
+	// FilterByTransferID keeps archives whose id range covers the transfer
+	FilterByTransferID(id int64) TransferArchiveQ
+
+	Page(pageParams *pgdb.OffsetPageParams) TransferArchiveQ
+}
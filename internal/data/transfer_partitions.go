@@ -0,0 +1,26 @@
+package data
+
+// TransferPartition is a partition of usdt_transfers holding the blocks from
+// FromBlock up to ToBlock, ToBlock excluded
+type TransferPartition struct {
+	Name      string
+	FromBlock uint64
+	ToBlock   uint64
+	// Detaching is set when a concurrent detach of the partition was
+	// interrupted and has to be finalized
+	Detaching bool
+}
+
+type TransferPartitionQ interface {
+	New() TransferPartitionQ
+
+	// Select returns the partitions ordered by their blocks
+	Select() ([]TransferPartition, error)
+	// Create creates the partition of the blocks if it doesn't exist
+	Create(fromBlock, toBlock uint64) error
+	// Detach detaches the partition concurrently, it must not be called in a
+	// transaction
+	Detach(partition TransferPartition) error
+	// Drop removes the partition along with its transfers
+	Drop(name string) error
+}
@@ -0,0 +1,42 @@
+package data
+
+import "time"
+
+// Granularities of the transfer statistics rollups
+const (
+	GranularityHour = "hour"
+	GranularityDay  = "day"
+	GranularityWeek = "week"
+)
+
+var Granularities = []string{GranularityHour, GranularityDay, GranularityWeek}
+
+type TransferStats struct {
+	Granularity     string    `db:"granularity"`
+	Bucket          time.Time `db:"bucket"`
+	Volume          string    `db:"volume"`
+	TransferCount   uint64    `db:"transfer_count"`
+	UniqueSenders   uint64    `db:"unique_senders"`
+	UniqueReceivers uint64    `db:"unique_receivers"`
+}
+
+type TransferStatsQ interface {
+	New() TransferStatsQ
+
+	Select() ([]TransferStats, error)
+
+	// ApplyBlock adds the already inserted transfers of the block to the rollups
+	ApplyBlock(blockNumber uint64) error
+	// RevertBlock subtracts the transfers of the block from the rollups, it
+	// must be called before the transfers are deleted
+	RevertBlock(blockNumber uint64) error
+	// Prune deletes the participants of the buckets ended at or before the
+	// moment, their unique counts are final
+	Prune(before time.Time) error
+	// Restore brings back the participants of the buckets holding the block
+	// or later ones from the stored transfers, so the blocks can be reverted
+	Restore(fromBlock uint64) error
+
+	FilterByGranularity(granularity string) TransferStatsQ
+	FilterByBucketRange(from, to time.Time) TransferStatsQ
+}
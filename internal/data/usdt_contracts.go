@@ -0,0 +1,26 @@
+package data
+
+// USDTContract is a contract in the USDT lineage. The original contract has
+// no predecessor, upgraded ones are watched starting from StartBlock.
+// LogIndex is the Deprecate event of the predecessor, it is nil when the
+// upgrade was read from the contract state.
+type USDTContract struct {
+	Address         string  `db:"address"`
+	StartBlock      uint64  `db:"start_block"`
+	Predecessor     *string `db:"predecessor"`
+	LogIndex        *int64  `db:"log_index"`
+	DeprecatedBlock *uint64 `db:"deprecated_block"`
+}
+
+type USDTContractQ interface {
+	New() USDTContractQ
+
+	// Select returns the lineage ordered from the original contract
+	Select() ([]USDTContract, error)
+	// Upgrade marks the predecessor of the contract deprecated at its start
+	// block and inserts the contract
+	Upgrade(contract USDTContract) error
+	// RevertBlock removes contracts added by Deprecate events of the block
+	// and clears the deprecation of their predecessors
+	RevertBlock(blockNumber uint64) error
+}
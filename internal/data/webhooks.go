@@ -0,0 +1,82 @@
+package data
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+	"gitlab.com/distributed_lab/kit/pgdb"
+)
+
+// Statuses of a webhook delivery
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusDelivered = "delivered"
+	DeliveryStatusDead      = "dead"
+)
+
+// Webhook is a subscription to transfers touching any of the addresses (all
+// transfers if empty) with at least the minimal amount (any if nil).
+type Webhook struct {
+	ID        int64          `db:"id"`
+	URL       string         `db:"url"`
+	Secret    string         `db:"secret" json:"-"`
+	Addresses pq.StringArray `db:"addresses"`
+	MinAmount *string        `db:"min_amount"`
+	CreatedAt time.Time      `db:"created_at"`
+}
+
+type WebhookDelivery struct {
+	ID            int64           `db:"id"`
+	WebhookID     int64           `db:"webhook_id"`
+	EventType     string          `db:"event_type"`
+	BlockNumber   uint64          `db:"block_number"`
+	LogIndex      uint64          `db:"log_index"`
+	Payload       json.RawMessage `db:"payload"`
+	Status        string          `db:"status"`
+	Attempts      int             `db:"attempts"`
+	NextAttemptAt time.Time       `db:"next_attempt_at"`
+	LastError     *string         `db:"last_error"`
+	CreatedAt     time.Time       `db:"created_at"`
+	DeliveredAt   *time.Time      `db:"delivered_at"`
+}
+
+type WebhookQ interface {
+	New() WebhookQ
+
+	Get() (*Webhook, error)
+	Select() ([]Webhook, error)
+	Insert(webhook Webhook) (*Webhook, error)
+	Delete() error
+
+	FilterByID(ids ...int64) WebhookQ
+
+	Page(pageParams *pgdb.OffsetPageParams) WebhookQ
+}
+
+type WebhookDeliveryQ interface {
+	New() WebhookDeliveryQ
+
+	Get() (*WebhookDelivery, error)
+	Select() ([]WebhookDelivery, error)
+
+	// EnqueueBlock writes an outbox entry of the event type for every
+	// webhook matching a transfer of the block
+	EnqueueBlock(blockNumber uint64, eventType string) error
+	MarkDelivered(id int64) error
+	// MarkFailed records a failed attempt and schedules the next one, or
+	// moves the delivery to the dead status if dead is set
+	MarkFailed(id int64, reason string, nextAttemptAt time.Time, dead bool) error
+	// Redeliver resets a dead or pending delivery so the dispatcher picks it
+	// up again, nil is returned when there's no such undelivered delivery
+	Redeliver(id int64) (*WebhookDelivery, error)
+
+	FilterByID(id int64) WebhookDeliveryQ
+	FilterByWebhookID(webhookID int64) WebhookDeliveryQ
+	FilterByStatus(status string) WebhookDeliveryQ
+	FilterDue(now time.Time) WebhookDeliveryQ
+
+	OrderByID() WebhookDeliveryQ
+	Limit(limit uint64) WebhookDeliveryQ
+	Page(pageParams *pgdb.OffsetPageParams) WebhookDeliveryQ
+}
@@ -0,0 +1,109 @@
+package metrics
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/tracing"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// EthClient is an ethclient.Client recording latency and errors of the
+// calls made by the service and tracing them as spans of the caller. It can
+// be used as a contract backend, calls not overridden here are passed
+// through unobserved.
+type EthClient struct {
+	*ethclient.Client
+}
+
+func NewEthClient(client *ethclient.Client) *EthClient {
+	return &EthClient{Client: client}
+}
+
+// DialEthClient connects to the RPC node
+func DialEthClient(rawurl string) (*EthClient, error) {
+	client, err := ethclient.Dial(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	return NewEthClient(client), nil
+}
+
+// ObserveRPC records the duration and the error of an RPC call
+func ObserveRPC(method string, start time.Time, err error) {
+	RPCDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+	if err != nil {
+		RPCErrors.WithLabelValues(method).Inc()
+	}
+}
+
+// startRPC starts the span of an RPC call, the returned function ends it and
+// records the call
+func startRPC(ctx context.Context, method string) (context.Context, func(error)) {
+	start := time.Now()
+	ctx, span := tracing.Start(ctx, "rpc "+method,
+		semconv.RPCSystemKey.String("jsonrpc"),
+		semconv.RPCMethod(method),
+	)
+	return ctx, func(err error) {
+		ObserveRPC(method, start, err)
+		tracing.End(span, err)
+	}
+}
+
+func (c *EthClient) BlockNumber(ctx context.Context) (uint64, error) {
+	ctx, done := startRPC(ctx, "eth_blockNumber")
+	number, err := c.Client.BlockNumber(ctx)
+	done(err)
+	return number, err
+}
+
+func (c *EthClient) ChainID(ctx context.Context) (*big.Int, error) {
+	ctx, done := startRPC(ctx, "eth_chainId")
+	chainID, err := c.Client.ChainID(ctx)
+	done(err)
+	return chainID, err
+}
+
+func (c *EthClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	ctx, done := startRPC(ctx, "eth_getBlockByNumber")
+	header, err := c.Client.HeaderByNumber(ctx, number)
+	done(err)
+	return header, err
+}
+
+func (c *EthClient) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	ctx, done := startRPC(ctx, "eth_getLogs")
+	logs, err := c.Client.FilterLogs(ctx, query)
+	done(err)
+	return logs, err
+}
+
+func (c *EthClient) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	ctx, done := startRPC(ctx, "eth_call")
+	result, err := c.Client.CallContract(ctx, msg, blockNumber)
+	done(err)
+	return result, err
+}
+
+// BatchCallContext sends the batch in a single request, it is recorded under
+// the "batch" method and every failed element counts as an error of its own
+// method
+func (c *EthClient) BatchCallContext(ctx context.Context, batch []rpc.BatchElem) error {
+	ctx, done := startRPC(ctx, "batch")
+	err := c.Client.Client().BatchCallContext(ctx, batch)
+	done(err)
+	if err == nil {
+		for _, elem := range batch {
+			if elem.Error != nil {
+				RPCErrors.WithLabelValues(elem.Method).Inc()
+			}
+		}
+	}
+	return err
+}
@@ -0,0 +1,78 @@
+// Package metrics holds the Prometheus collectors of the service, they are
+// registered in the default registry and served on /metrics.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "usdt_listener"
+
+var (
+	HeadBlock = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "head_block",
+		Help:      "Latest block number reported by the RPC node.",
+	})
+	LastProcessedBlock = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "last_processed_block",
+		Help:      "Number of the last block committed by the listener.",
+	})
+	Lag = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "lag_blocks",
+		Help:      "Number of blocks the listener is behind the head.",
+	})
+
+	BlocksProcessed = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "blocks_processed_total",
+		Help:      "Number of blocks committed by the listener, rate() gives blocks per second.",
+	})
+	LogsPerBlock = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "logs_per_block",
+		Help:      "Number of USDT contract logs in a processed block.",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 12),
+	})
+
+	RPCDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "rpc_duration_seconds",
+		Help:      "Latency of Ethereum RPC calls.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+	RPCErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rpc_errors_total",
+		Help:      "Number of failed Ethereum RPC calls.",
+	}, []string{"method"})
+
+	DBTransactionDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_transaction_duration_seconds",
+		Help:      "Duration of database transactions.",
+		Buckets:   prometheus.DefBuckets,
+	})
+
+	Reorgs = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reorgs_total",
+		Help:      "Number of chain reorganizations detected by the listener.",
+	})
+	ReorgDepth = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "reorg_depth_blocks",
+		Help:      "Number of blocks rolled back by a chain reorganization.",
+		Buckets:   prometheus.LinearBuckets(1, 1, 10),
+	})
+
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Duration of HTTP requests by route pattern.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+)
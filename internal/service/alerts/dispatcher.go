@@ -0,0 +1,113 @@
+package alerts
+
+import (
+	"context"
+	"time"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/config"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// Dispatcher delivers alerts recorded by the listener through the notifiers
+// listed in their rules. An alert is retried every poll period until all of
+// its notifiers succeed or it fails MaxAttempts times.
+type Dispatcher struct {
+	db        data.MasterQ
+	log       *logan.Entry
+	config    *config.Alerts
+	notifiers map[string]Notifier
+}
+
+// NewDispatcher creates a new Dispatcher instance with the log notifier and,
+// if configured, the webhook one
+func NewDispatcher(config *config.Alerts, db data.MasterQ, log *logan.Entry) *Dispatcher {
+	notifiers := map[string]Notifier{
+		data.AlertNotifierLog: NewLogNotifier(log),
+	}
+	if config.WebhookURL != "" {
+		notifiers[data.AlertNotifierWebhook] = NewWebhookNotifier(config.WebhookURL, config.WebhookSecret, config.Timeout)
+	}
+
+	return &Dispatcher{
+		db:        db,
+		log:       log,
+		config:    config,
+		notifiers: notifiers,
+	}
+}
+
+// Run delivers pending alerts every poll period until the context is
+// cancelled
+func (d *Dispatcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(d.config.PollPeriod)
+	defer ticker.Stop()
+
+	for {
+		if err := d.dispatch(ctx); err != nil {
+			d.log.WithError(err).Error("Failed to dispatch alerts")
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context) error {
+	alerts, err := d.db.Alert().FilterPending(d.config.MaxAttempts).OrderByID().Limit(d.config.BatchSize).Select()
+	if err != nil {
+		return errors.Wrap(err, "failed to select pending alerts")
+	}
+
+	rules := make(map[int64]*data.AlertRule)
+
+	for _, alert := range alerts {
+		fields := logan.F{"alertID": alert.ID, "ruleID": alert.RuleID}
+
+		rule, ok := rules[alert.RuleID]
+		if !ok {
+			rule, err = d.db.AlertRule().FilterByID(alert.RuleID).Get()
+			if err != nil {
+				return errors.Wrap(err, "failed to get alert rule", fields)
+			}
+			rules[alert.RuleID] = rule
+		}
+		if rule == nil {
+			// Deleted meanwhile, its alerts are gone with it
+			continue
+		}
+
+		notifyErr := d.notify(ctx, *rule, alert)
+		if notifyErr == nil {
+			if err := d.db.Alert().MarkNotified(alert.ID); err != nil {
+				return errors.Wrap(err, "failed to mark alert as notified", fields)
+			}
+			continue
+		}
+
+		d.log.WithError(notifyErr).WithFields(fields).WithField("attempts", alert.Attempts+1).Warn("Alert notification failed")
+
+		if err := d.db.Alert().MarkFailed(alert.ID, notifyErr.Error()); err != nil {
+			return errors.Wrap(err, "failed to mark alert notification as failed", fields)
+		}
+	}
+
+	return nil
+}
+
+func (d *Dispatcher) notify(ctx context.Context, rule data.AlertRule, alert data.Alert) error {
+	for _, name := range rule.Notifiers {
+		notifier, ok := d.notifiers[name]
+		if !ok {
+			return errors.From(errors.New("notifier is not configured"), logan.F{"notifier": name})
+		}
+		if err := notifier.Notify(ctx, rule, alert); err != nil {
+			return errors.Wrap(err, "notifier failed", logan.F{"notifier": name})
+		}
+	}
+	return nil
+}
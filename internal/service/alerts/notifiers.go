@@ -0,0 +1,96 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/webhooks"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// Notification is the body of alert webhook calls
+type Notification struct {
+	Rule  data.AlertRule
+	Alert data.Alert
+}
+
+// Notifier delivers an alert to a single channel
+type Notifier interface {
+	Notify(ctx context.Context, rule data.AlertRule, alert data.Alert) error
+}
+
+// LogNotifier writes alerts to the service log
+type LogNotifier struct {
+	log *logan.Entry
+}
+
+func NewLogNotifier(log *logan.Entry) *LogNotifier {
+	return &LogNotifier{log: log}
+}
+
+func (n *LogNotifier) Notify(_ context.Context, rule data.AlertRule, alert data.Alert) error {
+	n.log.WithFields(logan.F{
+		"ruleID":          rule.ID,
+		"rule":            rule.Name,
+		"kind":            rule.Kind,
+		"address":         alert.Address,
+		"amount":          alert.Amount,
+		"transferCount":   alert.TransferCount,
+		"transactionHash": alert.TransactionHash,
+		"blockNumber":     alert.BlockNumber,
+	}).Warn("Alert rule matched")
+	return nil
+}
+
+// WebhookNotifier POSTs alerts to a single configured URL, calls are signed
+// like transfer webhooks
+type WebhookNotifier struct {
+	client *http.Client
+	url    string
+	secret string
+}
+
+func NewWebhookNotifier(url, secret string, timeout time.Duration) *WebhookNotifier {
+	return &WebhookNotifier{
+		client: &http.Client{Timeout: timeout},
+		url:    url,
+		secret: secret,
+	}
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, rule data.AlertRule, alert data.Alert) error {
+	body, err := json.Marshal(Notification{Rule: rule, Alert: alert})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal notification")
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhooks.TimestampHeader, timestamp)
+	req.Header.Set(webhooks.SignatureHeader, "sha256="+webhooks.Sign(n.secret, timestamp, body))
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to send request")
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,310 @@
+package archiver
+
+import (
+	"context"
+	"time"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/config"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/publisher"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// batchSize is the number of transfers read from the cursor at once
+const batchSize = 1000
+
+var errReingesting = errors.New("a re-ingestion job is active")
+
+// Archiver moves transfers beyond the retention limits from the database to
+// monthly Parquet files. Only the transfer rows are removed, the balances,
+// stats, supply and blocks derived from them stay in the database.
+type Archiver struct {
+	db     data.MasterQ
+	log    *logan.Entry
+	config config.Config
+}
+
+// NewArchiver creates a new Archiver instance
+func NewArchiver(config config.Config, db data.MasterQ, log *logan.Entry) *Archiver {
+	return &Archiver{
+		db:     db,
+		log:    log.WithField("service", "archiver"),
+		config: config,
+	}
+}
+
+// Run archives the transfers due every period until the context is
+// cancelled
+func (a *Archiver) Run(ctx context.Context) error {
+	ticker := time.NewTicker(a.config.Retention().Period)
+	defer ticker.Stop()
+
+	for {
+		if _, err := a.Archive(ctx); err != nil {
+			a.log.WithError(err).Error("Failed to archive transfers")
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Archive archives every block up to the cutoff, BatchBlocks blocks per
+// transaction, and returns the number of transfers archived
+func (a *Archiver) Archive(ctx context.Context) (uint64, error) {
+	job, err := a.db.ReingestionJob().FilterActive().Get()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get active re-ingestion job")
+	}
+	// the job rolls back and reprocesses blocks which may be due
+	if job != nil {
+		a.log.WithField("job", job.ID).Debug("Archival skipped while re-ingesting")
+		return 0, nil
+	}
+
+	cutoff, err := a.Cutoff()
+	if err != nil {
+		return 0, err
+	}
+
+	lastBlock, err := a.db.TransferArchive().LastBlock()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get last archived block")
+	}
+	fromBlock := lastBlock + 1
+	if lastBlock == 0 {
+		first, err := a.db.USDTTransfer().OrderByCursor().Limit(1).Get()
+		if err != nil {
+			return 0, errors.Wrap(err, "failed to get first transfer")
+		}
+		if first == nil {
+			return 0, nil
+		}
+		fromBlock = first.BlockNumber
+	}
+
+	var archived uint64
+	defer func() {
+		// partitions left by a failed run are dropped by the next one
+		if err := a.dropPartitions(lastBlock); err != nil {
+			a.log.WithError(err).Error("Failed to drop archived transfer partitions")
+		}
+	}()
+	for fromBlock <= cutoff {
+		if err := ctx.Err(); err != nil {
+			return archived, err
+		}
+
+		toBlock := min(cutoff, fromBlock+a.config.Retention().BatchBlocks-1)
+		count, err := a.archiveRange(fromBlock, toBlock)
+		if errors.Cause(err) == errReingesting {
+			break
+		}
+		if err != nil {
+			return archived, errors.Wrap(err, "failed to archive block range", logan.F{
+				"from": fromBlock,
+				"to":   toBlock,
+			})
+		}
+		archived += count
+		lastBlock = toBlock
+		fromBlock = toBlock + 1
+	}
+
+	if archived > 0 {
+		a.log.WithFields(logan.F{
+			"toBlock":  fromBlock - 1,
+			"archived": archived,
+		}).Info("Transfers archived")
+	}
+	return archived, nil
+}
+
+// Cutoff is the last block due for archival, zero when none is. A block is
+// due once it is beyond every configured limit, behind the finality depth
+// and delivered by the message broker publisher.
+func (a *Archiver) Cutoff() (uint64, error) {
+	cfg := a.config.Retention()
+	if !cfg.Enabled() {
+		return 0, nil
+	}
+
+	lastProcessed, err := a.db.LastProcessedBlock().Get()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get last processed block")
+	}
+	if lastProcessed <= data.FinalityDepth {
+		return 0, nil
+	}
+	cutoff := lastProcessed - data.FinalityDepth
+
+	if cfg.Blocks != 0 {
+		if lastProcessed <= cfg.Blocks {
+			return 0, nil
+		}
+		cutoff = min(cutoff, lastProcessed-cfg.Blocks)
+	}
+
+	if cfg.Days != 0 {
+		// transfers of a block share its timestamp
+		before := time.Now().AddDate(0, 0, -int(cfg.Days))
+		transfer, err := a.db.USDTTransfer().FilterByMaxTimestamp(before).OrderByTimestamp(true).Limit(1).Get()
+		if err != nil {
+			return 0, errors.Wrap(err, "failed to get last transfer due")
+		}
+		if transfer == nil {
+			return 0, nil
+		}
+		cutoff = min(cutoff, transfer.BlockNumber)
+	}
+
+	if a.config.Publisher().Driver != config.PublisherDriverNone {
+		position, err := a.db.PublisherPosition().Get(publisher.PositionName(a.config.Publisher()))
+		if err != nil {
+			return 0, errors.Wrap(err, "failed to get publisher position")
+		}
+		// the position block may be published partially
+		if position == nil || position.BlockNumber == 0 {
+			return 0, nil
+		}
+		cutoff = min(cutoff, position.BlockNumber-1)
+	}
+
+	return cutoff, nil
+}
+
+// archiveRange writes the transfers of the blocks to the archive files and
+// removes them from the database. The files of a failed run are removed.
+func (a *Archiver) archiveRange(fromBlock, toBlock uint64) (uint64, error) {
+	dir := a.config.Retention().Dir
+
+	var files []*archiveFile
+	months := make(map[time.Time]*archiveFile)
+	committed := false
+	defer func() {
+		if !committed {
+			for _, file := range files {
+				file.remove()
+			}
+		}
+	}()
+
+	// read through the cursor in a transaction of its own, not the commit
+	// one, the listener doesn't write blocks behind the finality depth
+	err := a.db.Transaction(func(q data.MasterQ) error {
+		return q.USDTTransfer().FilterByBlockRange(fromBlock, toBlock).OrderByCursor().Each(batchSize, func(batch []data.USDTTransfer) error {
+			for start := 0; start < len(batch); {
+				month := monthOf(batch[start].Timestamp)
+				end := start + 1
+				for end < len(batch) && monthOf(batch[end].Timestamp).Equal(month) {
+					end++
+				}
+
+				file, ok := months[month]
+				if !ok {
+					var err error
+					file, err = createArchiveFile(dir, month, fromBlock, toBlock)
+					if err != nil {
+						return err
+					}
+					months[month] = file
+					files = append(files, file)
+				}
+				if err := file.write(batch[start:end]); err != nil {
+					return err
+				}
+				start = end
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to write archive files")
+	}
+
+	var count uint64
+	for _, file := range files {
+		if err := file.close(); err != nil {
+			return 0, err
+		}
+		count += file.archive.TransferCount
+	}
+
+	err = a.db.Transaction(func(q data.MasterQ) error {
+		job, err := q.ReingestionJob().FilterActive().Get()
+		if err != nil {
+			return errors.Wrap(err, "failed to get active re-ingestion job")
+		}
+		if job != nil {
+			return errReingesting
+		}
+
+		for _, file := range files {
+			if err := q.TransferArchive().Insert(file.archive); err != nil {
+				return err
+			}
+		}
+		// blocks before the range are archived already, so partitions
+		// ending within it are archived whole. Their rows aren't deleted,
+		// the partitions are dropped after the commit.
+		partitions, err := q.TransferPartition().Select()
+		if err != nil {
+			return err
+		}
+		deleteFrom := fromBlock
+		for _, partition := range partitions {
+			if partition.ToBlock-1 <= toBlock {
+				deleteFrom = max(deleteFrom, partition.ToBlock)
+			}
+		}
+
+		if deleteFrom <= toBlock {
+			if err := q.USDTTransfer().DeleteBlockRange(deleteFrom, toBlock); err != nil {
+				return err
+			}
+		}
+		return q.TransferArchive().SetLastBlock(toBlock)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	committed = true
+	return count, nil
+}
+
+// dropPartitions drops the partitions whose blocks are all archived. DROP
+// TABLE of an attached partition locks usdt_transfers as a whole, so the
+// partition is detached concurrently first, outside of any transaction.
+func (a *Archiver) dropPartitions(lastBlock uint64) error {
+	partitions, err := a.db.TransferPartition().Select()
+	if err != nil {
+		return err
+	}
+
+	for _, partition := range partitions {
+		if partition.ToBlock-1 > lastBlock {
+			break
+		}
+		if err := a.db.TransferPartition().Detach(partition); err != nil {
+			return err
+		}
+		if err := a.db.TransferPartition().Drop(partition.Name); err != nil {
+			return err
+		}
+		a.log.WithFields(logan.F{
+			"fromBlock": partition.FromBlock,
+			"toBlock":   partition.ToBlock - 1,
+		}).Info("Archived transfer partition dropped")
+	}
+	return nil
+}
+
+func monthOf(timestamp time.Time) time.Time {
+	timestamp = timestamp.UTC()
+	return time.Date(timestamp.Year(), timestamp.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
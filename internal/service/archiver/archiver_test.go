@@ -0,0 +1,67 @@
+package archiver
+
+import (
+	"testing"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"gitlab.com/distributed_lab/logan/v3"
+)
+
+// partitionsQ records the statements run on the partitions
+type partitionsQ struct {
+	data.MasterQ
+	partitions []data.TransferPartition
+	steps      *[]string
+}
+
+func (q partitionsQ) TransferPartition() data.TransferPartitionQ {
+	return partitionQ{partitions: q.partitions, steps: q.steps}
+}
+
+type partitionQ struct {
+	data.TransferPartitionQ
+	partitions []data.TransferPartition
+	steps      *[]string
+}
+
+func (q partitionQ) Select() ([]data.TransferPartition, error) {
+	return q.partitions, nil
+}
+func (q partitionQ) Detach(partition data.TransferPartition) error {
+	*q.steps = append(*q.steps, "detach "+partition.Name)
+	return nil
+}
+func (q partitionQ) Drop(name string) error {
+	*q.steps = append(*q.steps, "drop "+name)
+	return nil
+}
+
+func TestDropPartitionsDetachesFirst(t *testing.T) {
+	var steps []string
+	a := &Archiver{
+		db: partitionsQ{
+			partitions: []data.TransferPartition{
+				{Name: "usdt_transfers_p0", FromBlock: 0, ToBlock: 1000},
+				{Name: "usdt_transfers_p1000", FromBlock: 1000, ToBlock: 2000},
+				{Name: "usdt_transfers_p2000", FromBlock: 2000, ToBlock: 3000},
+			},
+			steps: &steps,
+		},
+		log: logan.New(),
+	}
+
+	// the second partition is archived up to its last block but one
+	if err := a.dropPartitions(1998); err != nil {
+		t.Fatalf("failed to drop partitions: %v", err)
+	}
+
+	want := []string{"detach usdt_transfers_p0", "drop usdt_transfers_p0"}
+	if len(steps) != len(want) {
+		t.Fatalf("got steps %v, want %v", steps, want)
+	}
+	for i := range want {
+		if steps[i] != want[i] {
+			t.Errorf("step %d is %q, want %q", i, steps[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package archiver
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/export"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// archiveFile is a Parquet file being written, it is written under a
+// temporary name and moved in place once complete
+type archiveFile struct {
+	archive data.TransferArchive
+	path    string
+	file    *os.File
+	writer  export.Writer
+	closed  bool
+}
+
+// ArchivePath is the path of the archive of the month and the block range
+// relative to the archive directory. Directories are named the Hive way, so
+// query engines read the month as a partition column.
+func ArchivePath(month time.Time, fromBlock, toBlock uint64) string {
+	return path.Join(
+		"month="+month.Format("2006-01"),
+		fmt.Sprintf("transfers-%d-%d.parquet", fromBlock, toBlock),
+	)
+}
+
+func createArchiveFile(dir string, month time.Time, fromBlock, toBlock uint64) (*archiveFile, error) {
+	relPath := ArchivePath(month, fromBlock, toBlock)
+	filePath := filepath.Join(dir, filepath.FromSlash(relPath))
+	fields := logan.F{"path": filePath}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+		return nil, errors.Wrap(err, "failed to create archive directory", fields)
+	}
+	file, err := os.Create(filePath + ".tmp")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create archive file", fields)
+	}
+	writer, err := export.NewWriter(export.FormatParquet, file)
+	if err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return nil, err
+	}
+
+	return &archiveFile{
+		archive: data.TransferArchive{Month: month, Path: relPath},
+		path:    filePath,
+		file:    file,
+		writer:  writer,
+	}, nil
+}
+
+func (f *archiveFile) write(transfers []data.USDTTransfer) error {
+	if f.archive.TransferCount == 0 {
+		f.archive.FromBlock = transfers[0].BlockNumber
+		f.archive.MinTransferID = transfers[0].ID
+		f.archive.MaxTransferID = transfers[0].ID
+	}
+	// ids follow the insertion order, re-ingested blocks get newer ones
+	for _, transfer := range transfers {
+		f.archive.MinTransferID = min(f.archive.MinTransferID, transfer.ID)
+		f.archive.MaxTransferID = max(f.archive.MaxTransferID, transfer.ID)
+	}
+	f.archive.ToBlock = transfers[len(transfers)-1].BlockNumber
+	f.archive.TransferCount += uint64(len(transfers))
+
+	return f.writer.Write(transfers)
+}
+
+// close writes the footer and moves the file in place
+func (f *archiveFile) close() error {
+	if f.closed {
+		return nil
+	}
+	f.closed = true
+	fields := logan.F{"path": f.path}
+
+	if err := f.writer.Close(); err != nil {
+		f.file.Close()
+		return err
+	}
+	if err := f.file.Sync(); err != nil {
+		f.file.Close()
+		return errors.Wrap(err, "failed to sync archive file", fields)
+	}
+	info, err := f.file.Stat()
+	if err != nil {
+		f.file.Close()
+		return errors.Wrap(err, "failed to stat archive file", fields)
+	}
+	f.archive.Size = info.Size()
+
+	if err := f.file.Close(); err != nil {
+		return errors.Wrap(err, "failed to close archive file", fields)
+	}
+	return errors.Wrap(os.Rename(f.file.Name(), f.path), "failed to move archive file in place", fields)
+}
+
+// remove deletes the file of a run which was not committed
+func (f *archiveFile) remove() {
+	if !f.closed {
+		f.file.Close()
+	}
+	os.Remove(f.file.Name())
+	os.Remove(f.path)
+}
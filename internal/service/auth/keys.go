@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// keyPrefix marks the keys of the service, so leaked keys are easy to find
+const keyPrefix = "usdt_"
+
+// NewKey generates a random API key, only its hash and prefix are stored
+func NewKey() (key, prefix, hash string, err error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", "", errors.Wrap(err, "failed to generate API key")
+	}
+
+	key = keyPrefix + hex.EncodeToString(raw)
+	return key, key[:len(keyPrefix)+8], Hash(key), nil
+}
+
+// Hash returns the hex SHA-256 of the key. Keys are random, so they need no
+// salt or slow hashing to be looked up by their hash.
+func Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
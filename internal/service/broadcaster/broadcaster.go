@@ -0,0 +1,102 @@
+package broadcaster
+
+import (
+	"sync"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+)
+
+type EventType string
+
+const (
+	// EventTransfer is published for every committed transfer
+	EventTransfer EventType = "transfer"
+	// EventRetraction is published for every transfer rolled back by a reorg
+	EventRetraction EventType = "retraction"
+)
+
+type Event struct {
+	Type     EventType
+	Transfer data.USDTTransfer
+}
+
+// Broadcaster fans out events committed by the listener to in-process
+// subscribers. Publishing never blocks: subscribers that can't keep up are
+// dropped and have to resubscribe.
+type Broadcaster struct {
+	mu          sync.Mutex
+	subscribers map[*Subscription]struct{}
+	bufferSize  int
+}
+
+func New(bufferSize int) *Broadcaster {
+	return &Broadcaster{
+		subscribers: make(map[*Subscription]struct{}),
+		bufferSize:  bufferSize,
+	}
+}
+
+type Subscription struct {
+	events  chan Event
+	dropped bool
+}
+
+// Events is closed when the subscription is closed or dropped
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Dropped reports whether the subscription was closed for lagging behind,
+// it is only meaningful after Events is closed
+func (s *Subscription) Dropped() bool {
+	return s.dropped
+}
+
+func (b *Broadcaster) Subscribe() *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub := &Subscription{
+		events: make(chan Event, b.bufferSize),
+	}
+	b.subscribers[sub] = struct{}{}
+	return sub
+}
+
+func (b *Broadcaster) Unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.remove(sub)
+}
+
+func (b *Broadcaster) Publish(events ...Event) {
+	if len(events) == 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subscribers {
+		for _, event := range events {
+			select {
+			case sub.events <- event:
+			default:
+				sub.dropped = true
+				b.remove(sub)
+			}
+			if sub.dropped {
+				break
+			}
+		}
+	}
+}
+
+func (b *Broadcaster) remove(sub *Subscription) {
+	if _, ok := b.subscribers[sub]; !ok {
+		return
+	}
+	delete(b.subscribers, sub)
+	close(sub.events)
+}
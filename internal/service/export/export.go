@@ -0,0 +1,73 @@
+package export
+
+import (
+	"io"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// Formats of the export
+const (
+	FormatCSV     = "csv"
+	FormatNDJSON  = "ndjson"
+	FormatParquet = "parquet"
+)
+
+// Formats lists every supported format
+var Formats = []string{FormatCSV, FormatNDJSON, FormatParquet}
+
+// batchSize is the number of transfers fetched from the cursor at once, it
+// bounds the memory used by an export together with the parquet row group
+const batchSize = 1000
+
+// Writer encodes transfers in one of the formats
+type Writer interface {
+	Write(transfers []data.USDTTransfer) error
+	// Close writes what the format keeps until the end, e.g. the parquet
+	// footer. It doesn't close the underlying writer.
+	Close() error
+}
+
+// NewWriter creates the writer of the format on top of w
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w), nil
+	case FormatNDJSON:
+		return newNDJSONWriter(w), nil
+	case FormatParquet:
+		return newParquetWriter(w)
+	default:
+		return nil, errors.From(errors.New("unknown export format"), logan.F{"format": format})
+	}
+}
+
+// ContentType returns the media type of the format
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	default:
+		return "application/vnd.apache.parquet"
+	}
+}
+
+// Export writes the transfers selected by q to w in the format, ordered by
+// block and log index. Transfers are streamed from a DB cursor, so the
+// export may be of any size. q must belong to a transaction, see Each.
+func Export(q data.USDTTransferQ, format string, w io.Writer) error {
+	writer, err := NewWriter(format, w)
+	if err != nil {
+		return err
+	}
+
+	if err := q.OrderByCursor().Each(batchSize, writer.Write); err != nil {
+		return errors.Wrap(err, "failed to export transfers")
+	}
+
+	return errors.Wrap(writer.Close(), "failed to finish export")
+}
@@ -0,0 +1,56 @@
+package export
+
+import (
+	"time"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/reader"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// ReadParquet calls fn with batches of the transfers of a Parquet file
+// written by the parquet writer, in the order they were written
+func ReadParquet(path string, fn func(batch []data.USDTTransfer) error) error {
+	file, err := local.NewLocalFileReader(path)
+	if err != nil {
+		return errors.Wrap(err, "failed to open parquet file", logan.F{"path": path})
+	}
+	defer file.Close()
+
+	pr, err := reader.NewParquetReader(file, new(parquetRecord), 1)
+	if err != nil {
+		return errors.Wrap(err, "failed to read parquet footer", logan.F{"path": path})
+	}
+	defer pr.ReadStop()
+
+	rows := int(pr.GetNumRows())
+	for read := 0; read < rows; read += batchSize {
+		records := make([]parquetRecord, min(batchSize, rows-read))
+		if err := pr.Read(&records); err != nil {
+			return errors.Wrap(err, "failed to read parquet rows", logan.F{"path": path})
+		}
+
+		batch := make([]data.USDTTransfer, 0, len(records))
+		for _, r := range records {
+			batch = append(batch, data.USDTTransfer{
+				ID:                r.ID,
+				FromAddress:       r.FromAddress,
+				ToAddress:         r.ToAddress,
+				Amount:            r.Amount,
+				TransactionHash:   r.TransactionHash,
+				BlockNumber:       uint64(r.BlockNumber),
+				LogIndex:          uint64(r.LogIndex),
+				Timestamp:         time.UnixMilli(r.Timestamp).UTC(),
+				Flags:             data.TransferFlags(r.Flags),
+				Fee:               r.Fee,
+				PrincipalLogIndex: r.PrincipalLogIndex,
+			})
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// parquetRowGroupSize is the size of a row group the parquet writer buffers
+// before writing it out
+const parquetRowGroupSize = 16 * 1024 * 1024
+
+var csvHeader = []string{
+	"id",
+	"from_address",
+	"to_address",
+	"amount",
+	"transaction_hash",
+	"block_number",
+	"log_index",
+	"timestamp",
+	"flags",
+	"fee",
+	"principal_log_index",
+}
+
+// record is a transfer as exported, flags are kept as the bit mask
+type record struct {
+	ID                int64     `json:"id"`
+	FromAddress       string    `json:"from_address"`
+	ToAddress         string    `json:"to_address"`
+	Amount            string    `json:"amount"`
+	TransactionHash   string    `json:"transaction_hash"`
+	BlockNumber       uint64    `json:"block_number"`
+	LogIndex          uint64    `json:"log_index"`
+	Timestamp         time.Time `json:"timestamp"`
+	Flags             int32     `json:"flags"`
+	Fee               string    `json:"fee"`
+	PrincipalLogIndex *int64    `json:"principal_log_index"`
+}
+
+func newRecord(transfer data.USDTTransfer) record {
+	return record{
+		ID:                transfer.ID,
+		FromAddress:       transfer.FromAddress,
+		ToAddress:         transfer.ToAddress,
+		Amount:            transfer.Amount,
+		TransactionHash:   transfer.TransactionHash,
+		BlockNumber:       transfer.BlockNumber,
+		LogIndex:          transfer.LogIndex,
+		Timestamp:         transfer.Timestamp.UTC(),
+		Flags:             int32(transfer.Flags),
+		Fee:               transfer.Fee,
+		PrincipalLogIndex: transfer.PrincipalLogIndex,
+	}
+}
+
+type csvWriter struct {
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (w *csvWriter) Write(transfers []data.USDTTransfer) error {
+	if !w.wroteHeader {
+		if err := w.w.Write(csvHeader); err != nil {
+			return errors.Wrap(err, "failed to write csv header")
+		}
+		w.wroteHeader = true
+	}
+
+	for _, transfer := range transfers {
+		r := newRecord(transfer)
+
+		principalLogIndex := ""
+		if r.PrincipalLogIndex != nil {
+			principalLogIndex = strconv.FormatInt(*r.PrincipalLogIndex, 10)
+		}
+
+		err := w.w.Write([]string{
+			strconv.FormatInt(r.ID, 10),
+			r.FromAddress,
+			r.ToAddress,
+			r.Amount,
+			r.TransactionHash,
+			strconv.FormatUint(r.BlockNumber, 10),
+			strconv.FormatUint(r.LogIndex, 10),
+			r.Timestamp.Format(time.RFC3339),
+			strconv.FormatInt(int64(r.Flags), 10),
+			r.Fee,
+			principalLogIndex,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to write csv row")
+		}
+	}
+
+	w.w.Flush()
+	return errors.Wrap(w.w.Error(), "failed to flush csv rows")
+}
+
+// Close writes the header of an empty export
+func (w *csvWriter) Close() error {
+	if w.wroteHeader {
+		return nil
+	}
+	return w.Write(nil)
+}
+
+type ndjsonWriter struct {
+	w *bufio.Writer
+	e *json.Encoder
+}
+
+func newNDJSONWriter(w io.Writer) *ndjsonWriter {
+	buffered := bufio.NewWriter(w)
+	return &ndjsonWriter{w: buffered, e: json.NewEncoder(buffered)}
+}
+
+func (w *ndjsonWriter) Write(transfers []data.USDTTransfer) error {
+	for _, transfer := range transfers {
+		// Encode ends every value with a newline
+		if err := w.e.Encode(newRecord(transfer)); err != nil {
+			return errors.Wrap(err, "failed to write json line")
+		}
+	}
+	return errors.Wrap(w.w.Flush(), "failed to flush json lines")
+}
+
+func (w *ndjsonWriter) Close() error {
+	return nil
+}
+
+// parquetRecord is a record with parquet types, amounts stay strings as
+// they don't fit into INT64
+type parquetRecord struct {
+	ID                int64  `parquet:"name=id, type=INT64"`
+	FromAddress       string `parquet:"name=from_address, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ToAddress         string `parquet:"name=to_address, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Amount            string `parquet:"name=amount, type=BYTE_ARRAY, convertedtype=UTF8"`
+	TransactionHash   string `parquet:"name=transaction_hash, type=BYTE_ARRAY, convertedtype=UTF8"`
+	BlockNumber       int64  `parquet:"name=block_number, type=INT64"`
+	LogIndex          int64  `parquet:"name=log_index, type=INT64"`
+	Timestamp         int64  `parquet:"name=timestamp, type=INT64, convertedtype=TIMESTAMP_MILLIS"`
+	Flags             int32  `parquet:"name=flags, type=INT32"`
+	Fee               string `parquet:"name=fee, type=BYTE_ARRAY, convertedtype=UTF8"`
+	PrincipalLogIndex *int64 `parquet:"name=principal_log_index, type=INT64, repetitiontype=OPTIONAL"`
+}
+
+type parquetWriter struct {
+	w *writer.ParquetWriter
+}
+
+func newParquetWriter(w io.Writer) (*parquetWriter, error) {
+	pw, err := writer.NewParquetWriterFromWriter(w, new(parquetRecord), 1)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create parquet writer")
+	}
+	pw.RowGroupSize = parquetRowGroupSize
+	pw.CompressionType = parquet.CompressionCodec_SNAPPY
+
+	return &parquetWriter{w: pw}, nil
+}
+
+func (w *parquetWriter) Write(transfers []data.USDTTransfer) error {
+	for _, transfer := range transfers {
+		r := newRecord(transfer)
+		err := w.w.Write(parquetRecord{
+			ID:                r.ID,
+			FromAddress:       r.FromAddress,
+			ToAddress:         r.ToAddress,
+			Amount:            r.Amount,
+			TransactionHash:   r.TransactionHash,
+			BlockNumber:       int64(r.BlockNumber),
+			LogIndex:          int64(r.LogIndex),
+			Timestamp:         r.Timestamp.UnixMilli(),
+			Flags:             r.Flags,
+			Fee:               r.Fee,
+			PrincipalLogIndex: r.PrincipalLogIndex,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to write parquet row")
+		}
+	}
+	return nil
+}
+
+// Close writes the buffered row group and the footer
+func (w *parquetWriter) Close() error {
+	return errors.Wrap(w.w.WriteStop(), "failed to write parquet footer")
+}
@@ -0,0 +1,331 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/requests"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/status"
+	"github.com/pkg/errors"
+	"gitlab.com/distributed_lab/ape"
+	"gitlab.com/distributed_lab/ape/problems"
+	"gitlab.com/distributed_lab/kit/pgdb"
+)
+
+// Checkpoint is the listener state controlled by the admin endpoints
+type Checkpoint struct {
+	BlockNumber uint64 `json:"block_number"`
+	Paused      bool   `json:"paused"`
+}
+
+// audit records an admin action, it is called within the transaction of the
+// action so there is no action without its entry
+func audit(q data.MasterQ, r *http.Request, action string, details interface{}) error {
+	raw, err := json.Marshal(details)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal audit details")
+	}
+
+	entry := data.AuditEntry{
+		Action:     action,
+		Details:    raw,
+		RemoteAddr: r.RemoteAddr,
+		UserAgent:  r.UserAgent(),
+	}
+	if apiKey := APIKey(r); apiKey != nil {
+		entry.APIKeyID = &apiKey.ID
+	}
+
+	return q.AuditLog().Insert(entry)
+}
+
+func GetCheckpoint(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	checkpoint, err := getCheckpoint(db)
+	if err != nil {
+		log.WithError(err).Error("failed to get checkpoint")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+
+	ape.Render(w, checkpoint)
+}
+
+func getCheckpoint(db data.MasterQ) (*Checkpoint, error) {
+	blockNumber, err := db.LastProcessedBlock().Get()
+	if err != nil {
+		return nil, err
+	}
+	paused, err := db.LastProcessedBlock().Paused()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Checkpoint{BlockNumber: blockNumber, Paused: paused}, nil
+}
+
+// errNotPaused rolls back a checkpoint change made while the listener runs
+var errNotPaused = errors.New("listener is not paused")
+
+// SetCheckpoint moves the checkpoint forward so the listener skips blocks.
+// Moving it back would apply the blocks twice, that is done by re-ingestion
+// which rolls them back first. The listener must be paused, otherwise the
+// block it is processing would overwrite the new checkpoint.
+func SetCheckpoint(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	request, err := requests.NewSetCheckpointRequest(r)
+	if err != nil {
+		log.WithError(err).Error("failed to parse request")
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	var badRequest error
+	err = db.Transaction(func(q data.MasterQ) error {
+		previous, err := q.LastProcessedBlock().Get()
+		if err != nil {
+			return err
+		}
+		if *request.BlockNumber < previous {
+			badRequest = errors.New("block_number is behind the checkpoint, use re-ingestion to process blocks again")
+			return nil
+		}
+
+		if err := q.LastProcessedBlock().Update(*request.BlockNumber); err != nil {
+			return err
+		}
+		// the update locks the row, so the pause can't be lifted before the
+		// commit. The status is paused once the listener has finished its
+		// last block and seen the pause.
+		paused, err := q.LastProcessedBlock().Paused()
+		if err != nil {
+			return err
+		}
+		if !paused || Status(r).Snapshot().State != status.StatePaused {
+			return errNotPaused
+		}
+
+		return audit(q, r, data.AuditActionSetCheckpoint, map[string]uint64{
+			"previous_block_number": previous,
+			"block_number":          *request.BlockNumber,
+		})
+	})
+	if errors.Cause(err) == errNotPaused {
+		ape.RenderErr(w, problems.Conflict())
+		return
+	}
+	if err != nil {
+		log.WithError(err).Error("failed to set checkpoint")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+	if badRequest != nil {
+		ape.RenderErr(w, problems.BadRequest(badRequest)...)
+		return
+	}
+
+	log.WithField("blockNumber", *request.BlockNumber).Warn("checkpoint moved from the admin API")
+	renderCheckpoint(w, r)
+}
+
+func PauseListener(w http.ResponseWriter, r *http.Request) {
+	setPaused(w, r, true)
+}
+
+func ResumeListener(w http.ResponseWriter, r *http.Request) {
+	setPaused(w, r, false)
+}
+
+// setPaused stores the pause in the DB, the listener checks it before every
+// block so it stays paused across restarts
+func setPaused(w http.ResponseWriter, r *http.Request, paused bool) {
+	log := Log(r)
+	db := DB(r)
+
+	action := data.AuditActionResume
+	if paused {
+		action = data.AuditActionPause
+	}
+
+	err := db.Transaction(func(q data.MasterQ) error {
+		if err := q.LastProcessedBlock().SetPaused(paused); err != nil {
+			return err
+		}
+		return audit(q, r, action, map[string]interface{}{})
+	})
+	if err != nil {
+		log.WithError(err).Error("failed to set listener pause")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+
+	log.WithField("paused", paused).Warn("listener pause changed from the admin API")
+	renderCheckpoint(w, r)
+}
+
+func renderCheckpoint(w http.ResponseWriter, r *http.Request) {
+	checkpoint, err := getCheckpoint(DB(r))
+	if err != nil {
+		Log(r).WithError(err).Error("failed to get checkpoint")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+
+	ape.Render(w, checkpoint)
+}
+
+// CreateReingestion schedules re-ingestion of a processed block range, the
+// listener picks it up before the next block
+func CreateReingestion(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	request, err := requests.NewCreateReingestionRequest(r)
+	if err != nil {
+		log.WithError(err).Error("failed to parse request")
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	var job *data.ReingestionJob
+	var badRequest error
+	err = db.Transaction(func(q data.MasterQ) error {
+		lastProcessedBlock, err := q.LastProcessedBlock().Get()
+		if err != nil {
+			return err
+		}
+		if request.ToBlock > lastProcessedBlock {
+			badRequest = errors.New("to_block is not processed yet")
+			return nil
+		}
+		// blocks before the listener started have no stored state to roll
+		// back, the baselines are read right before the first one
+		firstBlock, err := q.Block().Page(&pgdb.OffsetPageParams{Limit: 1, Order: pgdb.OrderTypeAsc}).Get()
+		if err != nil {
+			return err
+		}
+		if firstBlock != nil && request.FromBlock < firstBlock.Number {
+			badRequest = errors.New("from_block is before the first processed block")
+			return nil
+		}
+		// archived transfers are gone from the db and can't be rolled back
+		archivedBlock, err := q.TransferArchive().LastBlock()
+		if err != nil {
+			return err
+		}
+		if request.FromBlock <= archivedBlock {
+			badRequest = errors.New("from_block is archived")
+			return nil
+		}
+
+		job, err = q.ReingestionJob().Insert(data.ReingestionJob{
+			FromBlock: request.FromBlock,
+			ToBlock:   request.ToBlock,
+		})
+		if err != nil {
+			return err
+		}
+
+		return audit(q, r, data.AuditActionReingest, map[string]uint64{
+			"job_id":               uint64(job.ID),
+			"from_block":           job.FromBlock,
+			"to_block":             job.ToBlock,
+			"last_processed_block": lastProcessedBlock,
+		})
+	})
+	if pgdb.IsConstraintErr(err, "reingestion_jobs_active_index") {
+		ape.RenderErr(w, problems.Conflict())
+		return
+	}
+	if err != nil {
+		log.WithError(err).Error("failed to create re-ingestion job")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+	if badRequest != nil {
+		ape.RenderErr(w, problems.BadRequest(badRequest)...)
+		return
+	}
+
+	ape.Render(w, job)
+}
+
+func ListReingestions(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	request, err := requests.NewPageRequest(r)
+	if err != nil {
+		log.WithError(err).Error("failed to parse request")
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	pageParams := request.GetPageParams()
+
+	jobs, err := db.ReingestionJob().Page(&pageParams).Select()
+	if err != nil {
+		log.WithError(err).Error("failed to get re-ingestion jobs")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+
+	ape.Render(w, jobs)
+}
+
+func GetReingestion(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	id, err := requests.IDParam(r, "id")
+	if err != nil {
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	job, err := db.ReingestionJob().FilterByID(id).Get()
+	if err != nil {
+		log.WithError(err).Error("failed to get re-ingestion job")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+	if job == nil {
+		ape.RenderErr(w, problems.NotFound())
+		return
+	}
+
+	ape.Render(w, job)
+}
+
+func ListAuditLog(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	request, err := requests.NewListAuditLogRequest(r)
+	if err != nil {
+		log.WithError(err).Error("failed to parse request")
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	auditQ := db.AuditLog()
+	if request.Action != "" {
+		auditQ = auditQ.FilterByAction(request.Action)
+	}
+
+	pageParams := request.GetPageParams()
+
+	entries, err := auditQ.Page(&pageParams).Select()
+	if err != nil {
+		log.WithError(err).Error("failed to get audit log")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+
+	ape.Render(w, entries)
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/requests"
+	"gitlab.com/distributed_lab/ape"
+	"gitlab.com/distributed_lab/ape/problems"
+)
+
+func CreateAlertRule(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	request, err := requests.NewCreateAlertRuleRequest(r)
+	if err != nil {
+		log.WithError(err).Error("failed to parse request")
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	rule, err := db.AlertRule().Insert(data.AlertRule{
+		Name:          request.Name,
+		Kind:          request.Kind,
+		MinAmount:     request.MinAmount,
+		Addresses:     request.Addresses,
+		TransferCount: request.TransferCount,
+		TimeWindow:    request.TimeWindow,
+		Notifiers:     request.Notifiers,
+	})
+	if err != nil {
+		log.WithError(err).Error("failed to create alert rule")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+
+	ape.Render(w, rule)
+}
+
+func ListAlertRules(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	request, err := requests.NewPageRequest(r)
+	if err != nil {
+		log.WithError(err).Error("failed to parse request")
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	pageParams := request.GetPageParams()
+
+	rules, err := db.AlertRule().Page(&pageParams).Select()
+	if err != nil {
+		log.WithError(err).Error("failed to get alert rules")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+
+	ape.Render(w, rules)
+}
+
+func GetAlertRule(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	id, err := requests.IDParam(r, "id")
+	if err != nil {
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	rule, err := db.AlertRule().FilterByID(id).Get()
+	if err != nil {
+		log.WithError(err).Error("failed to get alert rule")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+	if rule == nil {
+		ape.RenderErr(w, problems.NotFound())
+		return
+	}
+
+	ape.Render(w, rule)
+}
+
+func DeleteAlertRule(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	id, err := requests.IDParam(r, "id")
+	if err != nil {
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	rule, err := db.AlertRule().FilterByID(id).Get()
+	if err != nil {
+		log.WithError(err).Error("failed to get alert rule")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+	if rule == nil {
+		ape.RenderErr(w, problems.NotFound())
+		return
+	}
+
+	if err := db.AlertRule().FilterByID(id).Delete(); err != nil {
+		log.WithError(err).Error("failed to delete alert rule")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func ListAlerts(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	request, err := requests.NewListAlertsRequest(r)
+	if err != nil {
+		log.WithError(err).Error("failed to parse request")
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	alertsQ := db.Alert()
+	if request.RuleID != 0 {
+		alertsQ = alertsQ.FilterByRuleID(request.RuleID)
+	}
+	if request.Address != "" {
+		alertsQ = alertsQ.FilterByAddress(request.Address)
+	}
+
+	pageParams := request.GetPageParams()
+
+	alerts, err := alertsQ.Page(&pageParams).Select()
+	if err != nil {
+		log.WithError(err).Error("failed to get alerts")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+
+	ape.Render(w, alerts)
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/auth"
+	"gitlab.com/distributed_lab/ape"
+	"gitlab.com/distributed_lab/ape/problems"
+)
+
+// StripQueryKey moves the api_key query parameter out of the URL before the
+// request is logged or traced, only AuthenticateStream reads it afterwards
+func StripQueryKey(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if !query.Has("api_key") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		key := query.Get("api_key")
+		query.Del("api_key")
+
+		r = r.WithContext(context.WithValue(r.Context(), queryKeyCtxKey, key))
+		u := *r.URL
+		u.RawQuery = query.Encode()
+		r.URL = &u
+		r.RequestURI = u.RequestURI()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Authenticate resolves the API key of the request and takes a token from
+// its rate limit bucket. Keys are passed as a bearer token or in X-API-Key.
+func Authenticate(next http.Handler) http.Handler {
+	return authenticate(next, false)
+}
+
+// AuthenticateStream also accepts the key in the api_key query parameter,
+// browser EventSource and WebSocket clients can't set headers
+func AuthenticateStream(next http.Handler) http.Handler {
+	return authenticate(next, true)
+}
+
+func authenticate(next http.Handler, queryKey bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cfg := Auth(r)
+		if cfg.Disabled {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		key := requestKey(r, queryKey)
+		if key == "" {
+			ape.RenderErr(w, problems.Unauthorized())
+			return
+		}
+
+		apiKey, err := DB(r).APIKey().FilterByHash(auth.Hash(key)).FilterActive().Get()
+		if err != nil {
+			Log(r).WithError(err).Error("failed to get API key")
+			ape.RenderErr(w, problems.InternalError())
+			return
+		}
+		if apiKey == nil {
+			ape.RenderErr(w, problems.Unauthorized())
+			return
+		}
+
+		rate, burst := cfg.Rate, cfg.Burst
+		if apiKey.Rate != nil {
+			rate = *apiKey.Rate
+		}
+		if apiKey.Burst != nil {
+			burst = *apiKey.Burst
+		}
+
+		quota := Limiter(r).Take(apiKey.ID, rate, burst)
+		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(quota.Limit))
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(quota.Remaining))
+		if !quota.Allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(quota.RetryAfter.Seconds()))))
+			ape.RenderErr(w, problems.TooManyRequests())
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), apiKeyCtxKey, apiKey)))
+	})
+}
+
+func requestKey(r *http.Request, queryKey bool) string {
+	if header := r.Header.Get("Authorization"); strings.HasPrefix(header, "Bearer ") {
+		return strings.TrimPrefix(header, "Bearer ")
+	}
+	if header := r.Header.Get("X-API-Key"); header != "" {
+		return header
+	}
+	if !queryKey {
+		return ""
+	}
+	key, _ := r.Context().Value(queryKeyCtxKey).(string)
+	return key
+}
+
+// RequireScope rejects requests whose API key lacks the scope, it has to
+// run after Authenticate
+func RequireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if Auth(r).Disabled {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if apiKey := APIKey(r); apiKey == nil || !apiKey.HasScope(scope) {
+				ape.RenderErr(w, problems.Forbidden())
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// APIKey returns the key of the request, nil when authentication is
+// disabled
+func APIKey(r *http.Request) *data.APIKey {
+	apiKey, _ := r.Context().Value(apiKeyCtxKey).(*data.APIKey)
+	return apiKey
+}
+
+// CORS allows browsers on the configured origins to call the API and read
+// the rate limit headers, preflight requests are answered right away
+func CORS(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin != "" && allowedOrigin(Auth(r).AllowedOrigins, origin) {
+			header := w.Header()
+			header.Set("Access-Control-Allow-Origin", origin)
+			header.Add("Vary", "Origin")
+			header.Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE")
+			header.Set("Access-Control-Allow-Headers", "Authorization, X-API-Key, Content-Type, Last-Event-ID")
+			header.Set("Access-Control-Expose-Headers", "X-RateLimit-Limit, X-RateLimit-Remaining, Retry-After")
+		}
+
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func allowedOrigin(allowed []string, origin string) bool {
+	for _, o := range allowed {
+		if o == "*" || o == origin {
+			return true
+		}
+	}
+	return false
+}
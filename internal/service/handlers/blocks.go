@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/requests"
+	"gitlab.com/distributed_lab/ape"
+	"gitlab.com/distributed_lab/ape/problems"
+)
+
+// ListBlocks returns processed blocks with their transfer summary, most
+// recent first
+func ListBlocks(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	request, err := requests.NewListBlocksRequest(r)
+	if err != nil {
+		log.WithError(err).Error("failed to parse request")
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	blocksQ := db.Block()
+
+	if request.From != nil {
+		blocksQ = blocksQ.FilterByMinTimestamp(*request.From)
+	}
+	if request.To != nil {
+		blocksQ = blocksQ.FilterByTimestampBefore(*request.To)
+	}
+
+	pageParams := request.GetPageParams()
+
+	blocks, err := blocksQ.Page(&pageParams).Select()
+	if err != nil {
+		log.WithError(err).Error("failed to get blocks")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+
+	ape.Render(w, blocks)
+}
+
+func GetBlock(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	number, err := requests.BlockNumberParam(r, "number")
+	if err != nil {
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	block, err := db.Block().FilterByNumber(number).Get()
+	if err != nil {
+		log.WithError(err).Error("failed to get block")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+	if block == nil {
+		ape.RenderErr(w, problems.NotFound())
+		return
+	}
+
+	ape.Render(w, block)
+}
@@ -4,7 +4,13 @@ import (
 	"context"
 	"net/http"
 
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/config"
 	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/metrics"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/broadcaster"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/ratelimit"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/status"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/tracing"
 	"gitlab.com/distributed_lab/logan/v3"
 )
 
@@ -13,6 +19,17 @@ type ctxKey int
 const (
     logCtxKey ctxKey = iota
     dbCtxKey
+    broadcasterCtxKey
+    streamingCtxKey
+    healthCtxKey
+    statusCtxKey
+    ethClientCtxKey
+    authCtxKey
+    limiterCtxKey
+    apiKeyCtxKey
+    queryKeyCtxKey
+    retentionCtxKey
+    ethereumCtxKey
 )
 
 func CtxLog(entry *logan.Entry) func(context.Context) context.Context {
@@ -22,7 +39,7 @@ func CtxLog(entry *logan.Entry) func(context.Context) context.Context {
 }
 
 func Log(r *http.Request) *logan.Entry {
-    return r.Context().Value(logCtxKey).(*logan.Entry)
+    return tracing.Log(r.Context(), r.Context().Value(logCtxKey).(*logan.Entry))
 }
 
 func CtxDB(entry data.MasterQ) func(context.Context) context.Context {
@@ -32,5 +49,95 @@ func CtxDB(entry data.MasterQ) func(context.Context) context.Context {
 }
 
 func DB(r *http.Request) data.MasterQ {
-    return r.Context().Value(dbCtxKey).(data.MasterQ).New()
-}
\ No newline at end of file
+    return r.Context().Value(dbCtxKey).(data.MasterQ).New().WithContext(r.Context())
+}
+
+func CtxBroadcaster(entry *broadcaster.Broadcaster) func(context.Context) context.Context {
+    return func(ctx context.Context) context.Context {
+        return context.WithValue(ctx, broadcasterCtxKey, entry)
+    }
+}
+
+func Broadcaster(r *http.Request) *broadcaster.Broadcaster {
+    return r.Context().Value(broadcasterCtxKey).(*broadcaster.Broadcaster)
+}
+
+func CtxStreaming(entry *config.Streaming) func(context.Context) context.Context {
+    return func(ctx context.Context) context.Context {
+        return context.WithValue(ctx, streamingCtxKey, entry)
+    }
+}
+
+func Streaming(r *http.Request) *config.Streaming {
+    return r.Context().Value(streamingCtxKey).(*config.Streaming)
+}
+
+func CtxHealth(entry *config.Health) func(context.Context) context.Context {
+    return func(ctx context.Context) context.Context {
+        return context.WithValue(ctx, healthCtxKey, entry)
+    }
+}
+
+func Health(r *http.Request) *config.Health {
+    return r.Context().Value(healthCtxKey).(*config.Health)
+}
+
+func CtxStatus(entry *status.Status) func(context.Context) context.Context {
+    return func(ctx context.Context) context.Context {
+        return context.WithValue(ctx, statusCtxKey, entry)
+    }
+}
+
+func Status(r *http.Request) *status.Status {
+    return r.Context().Value(statusCtxKey).(*status.Status)
+}
+
+func CtxEthClient(entry *metrics.EthClient) func(context.Context) context.Context {
+    return func(ctx context.Context) context.Context {
+        return context.WithValue(ctx, ethClientCtxKey, entry)
+    }
+}
+
+func EthClient(r *http.Request) *metrics.EthClient {
+    return r.Context().Value(ethClientCtxKey).(*metrics.EthClient)
+}
+
+func CtxAuth(entry *config.Auth) func(context.Context) context.Context {
+    return func(ctx context.Context) context.Context {
+        return context.WithValue(ctx, authCtxKey, entry)
+    }
+}
+
+func Auth(r *http.Request) *config.Auth {
+    return r.Context().Value(authCtxKey).(*config.Auth)
+}
+
+func CtxLimiter(entry *ratelimit.Limiter) func(context.Context) context.Context {
+    return func(ctx context.Context) context.Context {
+        return context.WithValue(ctx, limiterCtxKey, entry)
+    }
+}
+
+func Limiter(r *http.Request) *ratelimit.Limiter {
+    return r.Context().Value(limiterCtxKey).(*ratelimit.Limiter)
+}
+
+func CtxRetention(entry *config.Retention) func(context.Context) context.Context {
+    return func(ctx context.Context) context.Context {
+        return context.WithValue(ctx, retentionCtxKey, entry)
+    }
+}
+
+func Retention(r *http.Request) *config.Retention {
+    return r.Context().Value(retentionCtxKey).(*config.Retention)
+}
+
+func CtxEthereum(entry *config.Ethereum) func(context.Context) context.Context {
+    return func(ctx context.Context) context.Context {
+        return context.WithValue(ctx, ethereumCtxKey, entry)
+    }
+}
+
+func Ethereum(r *http.Request) *config.Ethereum {
+    return r.Context().Value(ethereumCtxKey).(*config.Ethereum)
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/export"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/requests"
+	"gitlab.com/distributed_lab/ape"
+	"gitlab.com/distributed_lab/ape/problems"
+)
+
+// ExportTransfers streams every transfer matching the filters as a file.
+// Once the file has started the status can't change, so a failure
+// past that point only cuts the file short. Archived transfers are not
+// exported, a range archived entirely is redirected to its archives and
+// the archives of a range archived in part are linked.
+func ExportTransfers(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	request, err := requests.NewExportTransfersRequest(r)
+	if err != nil {
+		log.WithError(err).Error("failed to parse request")
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	archivedBlock, err := db.TransferArchive().LastBlock()
+	if err != nil {
+		log.WithError(err).Error("failed to get last archived block")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+	if fromBlock, toBlock := request.Bounds(); renderArchived(w, r, archivedBlock, fromBlock, toBlock) {
+		return
+	}
+
+	w.Header().Set("Content-Type", export.ContentType(request.Format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="transfers.%s"`, request.Format))
+
+	response := &exportResponse{ResponseWriter: w}
+	err = db.Transaction(func(q data.MasterQ) error {
+		return export.Export(request.Apply(q.USDTTransfer()), request.Format, response)
+	})
+	if err != nil {
+		log.WithError(err).Error("failed to export transfers")
+		if !response.written {
+			w.Header().Del("Content-Disposition")
+			ape.RenderErr(w, problems.InternalError())
+		}
+	}
+}
+
+// exportResponse records whether the export has started, until then an
+// error can still be rendered
+type exportResponse struct {
+	http.ResponseWriter
+	written bool
+}
+
+func (w *exportResponse) Write(b []byte) (int, error) {
+	w.written = true
+	return w.ResponseWriter.Write(b)
+}
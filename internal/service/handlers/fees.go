@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/requests"
+	"gitlab.com/distributed_lab/ape"
+	"gitlab.com/distributed_lab/ape/problems"
+)
+
+// ListFeeParams returns the basisPointsRate and maximumFee history, most
+// recent first
+func ListFeeParams(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	request, err := requests.NewPageRequest(r)
+	if err != nil {
+		log.WithError(err).Error("failed to parse request")
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	pageParams := request.GetPageParams()
+
+	params, err := db.FeeParams().Page(&pageParams).Select()
+	if err != nil {
+		log.WithError(err).Error("failed to get fee params")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+
+	ape.Render(w, params)
+}
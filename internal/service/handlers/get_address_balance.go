@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/requests"
+	"github.com/pkg/errors"
+	"gitlab.com/distributed_lab/ape"
+	"gitlab.com/distributed_lab/ape/problems"
+	"gitlab.com/distributed_lab/kit/pgdb"
+)
+
+type AddressBalance struct {
+	Address     string `json:"address"`
+	BlockNumber uint64 `json:"block_number"`
+	Balance     string `json:"balance"`
+}
+
+// GetAddressBalance returns the address balance at the requested block or
+// moment, defaulting to the last processed block. Balances are known from the
+// block right before the starting block, where the baselines are seeded.
+func GetAddressBalance(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	request, err := requests.NewGetAddressBalanceRequest(r)
+	if err != nil {
+		log.WithError(err).Error("failed to parse request")
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	lastProcessedBlock, err := db.LastProcessedBlock().Get()
+	if err != nil {
+		log.WithError(err).Error("failed to get last processed block")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+
+	baselineBlock := Ethereum(r).StartingBlock - 1
+
+	blockNumber := lastProcessedBlock
+	switch {
+	case request.Block != nil:
+		if *request.Block > lastProcessedBlock {
+			ape.RenderErr(w, problems.BadRequest(errors.New("block is not processed yet"))...)
+			return
+		}
+		if *request.Block < baselineBlock {
+			ape.RenderErr(w, problems.BadRequest(errors.Errorf("block is before the starting block %d", baselineBlock+1))...)
+			return
+		}
+		blockNumber = *request.Block
+	case request.At != nil:
+		// block timestamps are whole seconds
+		pageParams := pgdb.OffsetPageParams{Limit: 1, Order: pgdb.OrderTypeDesc}
+		block, err := db.Block().FilterByTimestampBefore(request.At.Truncate(time.Second).Add(time.Second)).Page(&pageParams).Get()
+		if err != nil {
+			log.WithError(err).Error("failed to get last block before timestamp")
+			ape.RenderErr(w, problems.InternalError())
+			return
+		}
+		if block == nil {
+			ape.RenderErr(w, problems.BadRequest(errors.New("at is before the first processed block"))...)
+			return
+		}
+		blockNumber = block.Number
+	}
+
+	// the baseline is seeded the first time the address moves funds, before
+	// that the balance is not derived from the events
+	baselines, err := db.BalanceChange().FilterByAddress(request.Address).FilterByBlockNumber(baselineBlock).Select()
+	if err != nil {
+		log.WithError(err).Error("failed to get balance baseline")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+	if len(baselines) == 0 {
+		problem := problems.NotFound()
+		problem.Detail = "balance is unknown, the address has not moved funds since the starting block"
+		ape.RenderErr(w, problem)
+		return
+	}
+
+	balance, err := data.BalanceAt(db, request.Address, blockNumber)
+	if err != nil {
+		log.WithError(err).Error("failed to get historical balance")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+
+	ape.Render(w, AddressBalance{
+		Address:     request.Address,
+		BlockNumber: blockNumber,
+		Balance:     balance.String(),
+	})
+}
@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/config"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-chi/chi"
+	"gitlab.com/distributed_lab/kit/pgdb"
+	"gitlab.com/distributed_lab/logan/v3"
+)
+
+var (
+	holder   = common.HexToAddress("0x5754284f345afc66a98fbb0a0afe71e0f007b949").Hex()
+	stranger = common.HexToAddress("0x1").Hex()
+)
+
+// balancesQ keeps the processed blocks and balance changes in memory, there
+// are no checkpoints
+type balancesQ struct {
+	data.MasterQ
+	lastProcessedBlock uint64
+	blocks             []data.Block
+	changes            []data.BalanceChange
+}
+
+func (q *balancesQ) New() data.MasterQ {
+	return q
+}
+
+func (q *balancesQ) WithContext(context.Context) data.MasterQ {
+	return q
+}
+
+func (q *balancesQ) LastProcessedBlock() data.LastProcessedBlockQ {
+	return lastProcessedBlockQ{block: q.lastProcessedBlock}
+}
+
+func (q *balancesQ) Block() data.BlockQ {
+	return &blocksQ{blocks: q.blocks}
+}
+
+func (q *balancesQ) BalanceChange() data.BalanceChangeQ {
+	return &balanceChangesQ{changes: q.changes}
+}
+
+func (q *balancesQ) BalanceCheckpoint() data.BalanceCheckpointQ {
+	return checkpointsQ{}
+}
+
+type lastProcessedBlockQ struct {
+	data.LastProcessedBlockQ
+	block uint64
+}
+
+func (q lastProcessedBlockQ) Get() (uint64, error) {
+	return q.block, nil
+}
+
+// blocksQ returns the first or the latest block matching the filters, the
+// blocks are ordered by number
+type blocksQ struct {
+	data.BlockQ
+	blocks []data.Block
+	desc   bool
+}
+
+func (q *blocksQ) filter(keep func(data.Block) bool) data.BlockQ {
+	var blocks []data.Block
+	for _, block := range q.blocks {
+		if keep(block) {
+			blocks = append(blocks, block)
+		}
+	}
+	return &blocksQ{blocks: blocks}
+}
+
+func (q *blocksQ) FilterByMinTimestamp(timestamp time.Time) data.BlockQ {
+	return q.filter(func(block data.Block) bool {
+		return !block.Timestamp.Before(timestamp)
+	})
+}
+
+func (q *blocksQ) FilterByTimestampBefore(timestamp time.Time) data.BlockQ {
+	return q.filter(func(block data.Block) bool {
+		return block.Timestamp.Before(timestamp)
+	})
+}
+
+func (q *blocksQ) Page(pageParams *pgdb.OffsetPageParams) data.BlockQ {
+	q.desc = pageParams.Order == pgdb.OrderTypeDesc
+	return q
+}
+
+func (q *blocksQ) Get() (*data.Block, error) {
+	if len(q.blocks) == 0 {
+		return nil, nil
+	}
+	if q.desc {
+		return &q.blocks[len(q.blocks)-1], nil
+	}
+	return &q.blocks[0], nil
+}
+
+type balanceChangesQ struct {
+	data.BalanceChangeQ
+	changes []data.BalanceChange
+}
+
+func (q *balanceChangesQ) filter(keep func(data.BalanceChange) bool) data.BalanceChangeQ {
+	var changes []data.BalanceChange
+	for _, change := range q.changes {
+		if keep(change) {
+			changes = append(changes, change)
+		}
+	}
+	return &balanceChangesQ{changes: changes}
+}
+
+func (q *balanceChangesQ) FilterByAddress(addresses ...string) data.BalanceChangeQ {
+	return q.filter(func(change data.BalanceChange) bool {
+		return change.Address == addresses[0]
+	})
+}
+
+func (q *balanceChangesQ) FilterByBlockNumber(blockNumber uint64) data.BalanceChangeQ {
+	return q.filter(func(change data.BalanceChange) bool {
+		return change.BlockNumber == blockNumber
+	})
+}
+
+func (q *balanceChangesQ) FilterByBlockRange(fromBlock, toBlock uint64) data.BalanceChangeQ {
+	return q.filter(func(change data.BalanceChange) bool {
+		return change.BlockNumber >= fromBlock && change.BlockNumber <= toBlock
+	})
+}
+
+func (q *balanceChangesQ) Select() ([]data.BalanceChange, error) {
+	return q.changes, nil
+}
+
+func (q *balanceChangesQ) Sum() (string, error) {
+	sum := new(big.Int)
+	for _, change := range q.changes {
+		delta, _ := new(big.Int).SetString(change.Delta, 10)
+		sum.Add(sum, delta)
+	}
+	return sum.String(), nil
+}
+
+type checkpointsQ struct {
+	data.BalanceCheckpointQ
+}
+
+func (q checkpointsQ) FilterByAddress(string) data.BalanceCheckpointQ {
+	return q
+}
+
+func (q checkpointsQ) FilterByMaxBlockNumber(uint64) data.BalanceCheckpointQ {
+	return q
+}
+
+func (q checkpointsQ) Latest() (*data.BalanceCheckpoint, error) {
+	return nil, nil
+}
+
+func getAddressBalance(t *testing.T, address, query string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	// the listener started at block 100, the holder was seeded with 50 and
+	// received 7 at block 101
+	db := &balancesQ{
+		lastProcessedBlock: 102,
+		blocks: []data.Block{
+			{Number: 100, Timestamp: time.Unix(1000, 0).UTC()},
+			{Number: 101, Timestamp: time.Unix(1012, 0).UTC()},
+			{Number: 102, Timestamp: time.Unix(1024, 0).UTC()},
+		},
+		changes: []data.BalanceChange{
+			{Address: holder, Delta: "50", BlockNumber: 99},
+			{Address: holder, Delta: "7", BlockNumber: 101},
+		},
+	}
+
+	routeCtx := chi.NewRouteContext()
+	routeCtx.URLParams.Add("address", address)
+
+	ctx := context.WithValue(context.Background(), chi.RouteCtxKey, routeCtx)
+	ctx = CtxLog(logan.New())(ctx)
+	ctx = CtxDB(db)(ctx)
+	ctx = CtxEthereum(&config.Ethereum{StartingBlock: 100})(ctx)
+
+	r := httptest.NewRequest(http.MethodGet, "/addresses/"+address+"/balance?"+query, nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+	GetAddressBalance(w, r)
+	return w
+}
+
+func TestGetAddressBalance(t *testing.T) {
+	cases := []struct {
+		query   string
+		block   uint64
+		balance string
+	}{
+		{query: "", block: 102, balance: "57"},
+		{query: "block=99", block: 99, balance: "50"},
+		{query: "block=100", block: 100, balance: "50"},
+		{query: "at=1012", block: 101, balance: "57"},
+	}
+
+	for _, c := range cases {
+		w := getAddressBalance(t, holder, c.query)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%q: got status %d, want 200", c.query, w.Code)
+		}
+
+		var balance AddressBalance
+		if err := json.Unmarshal(w.Body.Bytes(), &balance); err != nil {
+			t.Fatalf("%q: failed to decode balance: %v", c.query, err)
+		}
+		if balance.BlockNumber != c.block || balance.Balance != c.balance {
+			t.Errorf("%q: got %s at block %d, want %s at block %d",
+				c.query, balance.Balance, balance.BlockNumber, c.balance, c.block)
+		}
+	}
+}
+
+func TestGetAddressBalanceBeforeStartingBlock(t *testing.T) {
+	for _, query := range []string{"block=98", "block=0", "at=999"} {
+		if w := getAddressBalance(t, holder, query); w.Code != http.StatusBadRequest {
+			t.Errorf("%q: got status %d, want 400", query, w.Code)
+		}
+	}
+}
+
+func TestGetAddressBalanceNotSeeded(t *testing.T) {
+	// the address has not moved funds since the starting block, its balance
+	// was never read from the contract
+	if w := getAddressBalance(t, stranger, "block=101"); w.Code != http.StatusNotFound {
+		t.Errorf("got status %d, want 404", w.Code)
+	}
+}
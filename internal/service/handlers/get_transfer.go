@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
 	"github.com/go-chi/chi"
 	"gitlab.com/distributed_lab/ape"
 	"gitlab.com/distributed_lab/ape/problems"
@@ -29,9 +30,16 @@ func GetUSDTTransfer(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if transfer == nil {
-		ape.RenderErr(w, problems.NotFound())
+		getArchivedTransfer(w, r, id)
 		return
 	}
 
-	ape.Render(w, transfer)
+	detailed, err := detailTransfers(db, []data.USDTTransfer{*transfer})
+	if err != nil {
+		log.WithError(err).Error("failed to get transfer details")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+
+	ape.Render(w, detailed[0])
 }
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/requests"
+	"gitlab.com/distributed_lab/ape"
+	"gitlab.com/distributed_lab/ape/problems"
+)
+
+// GetVolumeStats returns transfer volume and activity rollups for the range
+func GetVolumeStats(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	request, err := requests.NewIntervalRangeRequest(r)
+	if err != nil {
+		log.WithError(err).Error("failed to parse request")
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	stats, err := db.TransferStats().
+		FilterByGranularity(request.Interval).
+		FilterByBucketRange(request.From, request.To).
+		Select()
+	if err != nil {
+		log.WithError(err).Error("failed to get transfer stats")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+
+	ape.Render(w, stats)
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/jsonapi"
+	"gitlab.com/distributed_lab/ape"
+)
+
+type healthCheck struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+type readiness struct {
+	Ready              bool        `json:"ready"`
+	DB                 healthCheck `json:"db"`
+	RPC                healthCheck `json:"rpc"`
+	Lag                healthCheck `json:"lag"`
+	Head               uint64      `json:"head"`
+	LastProcessedBlock uint64      `json:"last_processed_block"`
+}
+
+// Live reports that the process serves requests, it checks nothing else so
+// a stuck dependency doesn't get the service restarted
+func Live(w http.ResponseWriter, r *http.Request) {
+	ape.Render(w, map[string]string{"status": "ok"})
+}
+
+// Ready checks the DB and the RPC node and that the listener is no further
+// behind the head than health.max_lag, it responds 503 if any check fails
+func Ready(w http.ResponseWriter, r *http.Request) {
+	health := Health(r)
+
+	ctx, cancel := context.WithTimeout(r.Context(), health.Timeout)
+	defer cancel()
+
+	var result readiness
+
+	lastProcessedBlock, err := DB(r).WithContext(ctx).LastProcessedBlock().Get()
+	if err != nil {
+		result.DB.Error = err.Error()
+	} else {
+		result.DB.OK = true
+		result.LastProcessedBlock = lastProcessedBlock
+	}
+
+	head, err := EthClient(r).BlockNumber(ctx)
+	if err != nil {
+		result.RPC.Error = err.Error()
+	} else {
+		result.RPC.OK = true
+		result.Head = head
+	}
+
+	switch {
+	case !result.DB.OK || !result.RPC.OK:
+		result.Lag.Error = "unknown without DB and RPC"
+	case head > lastProcessedBlock && head-lastProcessedBlock > health.MaxLag:
+		result.Lag.Error = "listener is behind the head"
+	default:
+		result.Lag.OK = true
+	}
+
+	result.Ready = result.DB.OK && result.RPC.OK && result.Lag.OK
+	if !result.Ready {
+		Log(r).WithField("readiness", result).Warn("service is not ready")
+		// ape.Render can't set the header once the status is written
+		w.Header().Set("Content-Type", jsonapi.MediaType)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	ape.Render(w, result)
+}
+
+// GetStatus returns the sync progress of the listener for status pages
+func GetStatus(w http.ResponseWriter, r *http.Request) {
+	ape.Render(w, Status(r).Snapshot())
+}
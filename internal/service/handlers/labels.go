@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/requests"
+	"gitlab.com/distributed_lab/ape"
+	"gitlab.com/distributed_lab/ape/problems"
+)
+
+// ImportResult is the response of the CSV import endpoints
+type ImportResult struct {
+	Imported int
+}
+
+func SetAddressLabel(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	request, err := requests.NewSetAddressLabelRequest(r)
+	if err != nil {
+		log.WithError(err).Error("failed to parse request")
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	if err := db.AddressLabel().Upsert(data.AddressLabel{Address: request.Address, Label: request.Label}); err != nil {
+		log.WithError(err).Error("failed to set address label")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+
+	label, err := db.AddressLabel().FilterByAddress(request.Address).Get()
+	if err != nil || label == nil {
+		log.WithError(err).Error("failed to get address label")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+
+	ape.Render(w, label)
+}
+
+func ListAddressLabels(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	request, err := requests.NewListAddressLabelsRequest(r)
+	if err != nil {
+		log.WithError(err).Error("failed to parse request")
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	labelsQ := db.AddressLabel()
+	if request.Label != "" {
+		labelsQ = labelsQ.FilterByLabel(request.Label)
+	}
+
+	pageParams := request.GetPageParams()
+
+	labels, err := labelsQ.Page(&pageParams).Select()
+	if err != nil {
+		log.WithError(err).Error("failed to get address labels")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+
+	ape.Render(w, labels)
+}
+
+func GetAddressLabel(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	address, err := requests.AddressParam(r, "address")
+	if err != nil {
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	label, err := db.AddressLabel().FilterByAddress(address).Get()
+	if err != nil {
+		log.WithError(err).Error("failed to get address label")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+	if label == nil {
+		ape.RenderErr(w, problems.NotFound())
+		return
+	}
+
+	ape.Render(w, label)
+}
+
+func DeleteAddressLabel(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	address, err := requests.AddressParam(r, "address")
+	if err != nil {
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	label, err := db.AddressLabel().FilterByAddress(address).Get()
+	if err != nil {
+		log.WithError(err).Error("failed to get address label")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+	if label == nil {
+		ape.RenderErr(w, problems.NotFound())
+		return
+	}
+
+	if err := db.AddressLabel().FilterByAddress(address).Delete(); err != nil {
+		log.WithError(err).Error("failed to delete address label")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ImportAddressLabels sets labels from a CSV body in a single transaction
+func ImportAddressLabels(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	labels, err := requests.NewImportAddressLabelsRequest(r)
+	if err != nil {
+		log.WithError(err).Error("failed to parse request")
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	err = db.Transaction(func(q data.MasterQ) error {
+		return q.AddressLabel().Upsert(labels...)
+	})
+	if err != nil {
+		log.WithError(err).Error("failed to import address labels")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+
+	ape.Render(w, ImportResult{Imported: len(labels)})
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/requests"
+	"gitlab.com/distributed_lab/ape"
+	"gitlab.com/distributed_lab/ape/problems"
+)
+
+// ListBalances returns the top USDT holders ordered by balance
+func ListBalances(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	request, err := requests.NewPageRequest(r)
+	if err != nil {
+		log.WithError(err).Error("failed to parse request")
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	pageParams := request.GetPageParams()
+
+	balances, err := db.Balance().FilterPositive().Page(&pageParams).Select()
+	if err != nil {
+		log.WithError(err).Error("failed to get balances")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+
+	ape.Render(w, balances)
+}
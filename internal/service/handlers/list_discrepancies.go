@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/requests"
+	"gitlab.com/distributed_lab/ape"
+	"gitlab.com/distributed_lab/ape/problems"
+)
+
+// ListDiscrepancies returns mismatches found by the balance reconciler
+func ListDiscrepancies(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	request, err := requests.NewListDiscrepanciesRequest(r)
+	if err != nil {
+		log.WithError(err).Error("failed to parse request")
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	discrepanciesQ := db.BalanceDiscrepancy()
+
+	if request.Address != "" {
+		discrepanciesQ = discrepanciesQ.FilterByAddress(request.Address)
+	}
+
+	pageParams := request.GetPageParams()
+
+	discrepancies, err := discrepanciesQ.Page(&pageParams).Select()
+	if err != nil {
+		log.WithError(err).Error("failed to get balance discrepancies")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+
+	ape.Render(w, discrepancies)
+}
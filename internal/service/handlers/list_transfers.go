@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"math"
 	"net/http"
 
 	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/requests"
@@ -19,10 +20,21 @@ func ListUSDTTransfers(w http.ResponseWriter, r *http.Request) {
         return
     }
 
-    transfersQ := db.USDTTransfer()
+    // the list isn't limited to blocks, archived transfers are only linked
+    archivedBlock, err := db.TransferArchive().LastBlock()
+    if err != nil {
+        log.WithError(err).Error("failed to get last archived block")
+        ape.RenderErr(w, problems.InternalError())
+        return
+    }
+    renderArchived(w, r, archivedBlock, 0, math.MaxInt64)
 
-    if request.Address != "" {
-        transfersQ = transfersQ.FilterByFromAddress(request.Address)
+    transfersQ := request.TransferFilters.Apply(db.USDTTransfer())
+    if request.Watchlist != 0 {
+        transfersQ = transfersQ.FilterByWatchlist(request.Watchlist, request.Direction)
+    }
+    if request.Label != "" {
+        transfersQ = transfersQ.FilterByLabel(request.Label, request.Direction)
     }
 
     pageParams := request.GetPageParams()
@@ -34,5 +46,12 @@ func ListUSDTTransfers(w http.ResponseWriter, r *http.Request) {
         return
     }
 
-    ape.Render(w, transfers)
+    detailed, err := detailTransfers(db, transfers)
+    if err != nil {
+        log.WithError(err).Error("failed to get transfer details")
+        ape.RenderErr(w, problems.InternalError())
+        return
+    }
+
+    ape.Render(w, detailed)
 }
@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"net/http"
+
+	"gitlab.com/distributed_lab/ape"
+	"gitlab.com/distributed_lab/ape/problems"
+)
+
+// ListUSDTContracts returns the USDT contract lineage watched by the listener
+func ListUSDTContracts(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	lineage, err := db.USDTContract().Select()
+	if err != nil {
+		log.WithError(err).Error("failed to get USDT contracts")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+
+	ape.Render(w, lineage)
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/requests"
+	"gitlab.com/distributed_lab/ape"
+	"gitlab.com/distributed_lab/ape/problems"
+)
+
+// GetScreeningReport summarizes flagged transfers and parties for the range.
+// Archived transfers are not in the report, a range archived entirely is
+// redirected to its archives and the archives of a range archived in part
+// are linked.
+func GetScreeningReport(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	request, err := requests.NewGetScreeningReportRequest(r)
+	if err != nil {
+		log.WithError(err).Error("failed to parse request")
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	archivedBlock, err := db.TransferArchive().LastBlock()
+	if err != nil {
+		log.WithError(err).Error("failed to get last archived block")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+	if archivedBlock != 0 {
+		fromBlock, toBlock, err := timeRangeBlocks(db, request.From, request.To)
+		if err != nil {
+			log.WithError(err).Error("failed to get blocks of the range")
+			ape.RenderErr(w, problems.InternalError())
+			return
+		}
+		if toBlock != 0 && renderArchived(w, r, archivedBlock, fromBlock, toBlock) {
+			return
+		}
+	}
+
+	report, err := db.Screening().Report(request.From, request.To, request.Limit)
+	if err != nil {
+		log.WithError(err).Error("failed to build screening report")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+
+	report.Lists, err = db.SanctionsList().Select()
+	if err != nil {
+		log.WithError(err).Error("failed to get sanctions lists")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+
+	ape.Render(w, report)
+}
+
+func ListSanctionsLists(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	lists, err := db.SanctionsList().Select()
+	if err != nil {
+		log.WithError(err).Error("failed to get sanctions lists")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+
+	ape.Render(w, lists)
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/broadcaster"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/requests"
+	"github.com/google/jsonapi"
+	"github.com/pkg/errors"
+	"gitlab.com/distributed_lab/ape"
+	"gitlab.com/distributed_lab/ape/problems"
+)
+
+const (
+	streamReplayBatchSize = 500
+	streamKeepAlivePeriod = 15 * time.Second
+)
+
+// StreamTransfers pushes committed transfers as Server-Sent Events. Clients
+// resuming with Last-Event-ID get the missed transfers from the db first.
+// Transfers rolled back by a reorg are announced with retraction events.
+// A cursor within the archived blocks is answered with 410 and a link to
+// the archives, the missed transfers are no longer in the db.
+func StreamTransfers(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	request, err := requests.NewStreamTransfersRequest(r)
+	if err != nil {
+		log.WithError(err).Error("failed to parse request")
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	// the transfers after an archived cursor can't be replayed from the db
+	if cursor := request.LastEventID; cursor != nil {
+		archivedBlock, err := db.TransferArchive().LastBlock()
+		if err != nil {
+			log.WithError(err).Error("failed to get last archived block")
+			ape.RenderErr(w, problems.InternalError())
+			return
+		}
+		if cursor.BlockNumber <= archivedBlock {
+			w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="archives"`, archivesURL(r, cursor.BlockNumber, archivedBlock)))
+			ape.RenderErr(w, &jsonapi.ErrorObject{
+				Title:  http.StatusText(http.StatusGone),
+				Status: strconv.Itoa(http.StatusGone),
+				Detail: "transfers after Last-Event-ID are archived",
+			})
+			return
+		}
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		log.Error("response writer does not support flushing")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+
+	// Subscribe before the replay so nothing committed meanwhile is lost
+	events := Broadcaster(r)
+	sub := events.Subscribe()
+	defer events.Unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	cursor := request.LastEventID
+	for cursor != nil {
+		transfers, err := request.TransferFilters.Apply(db.USDTTransfer()).
+			FilterAfterCursor(cursor.BlockNumber, cursor.LogIndex).
+			OrderByCursor().
+			Limit(streamReplayBatchSize).
+			Select()
+		if err != nil {
+			log.WithError(err).Error("failed to replay USDT transfers")
+			return
+		}
+
+		for _, transfer := range transfers {
+			if err := writeTransferEvent(w, broadcaster.EventTransfer, transfer); err != nil {
+				return
+			}
+			next := requests.CursorOf(transfer)
+			cursor = &next
+		}
+
+		if len(transfers) < streamReplayBatchSize {
+			break
+		}
+	}
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(streamKeepAlivePeriod)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case event, ok := <-sub.Events():
+			if !ok {
+				if sub.Dropped() {
+					fmt.Fprint(w, "event: error\ndata: {\"error\":\"consumer is too slow\"}\n\n")
+				}
+				flusher.Flush()
+				return
+			}
+
+			if !request.TransferFilters.Matches(event.Transfer) {
+				continue
+			}
+
+			position := requests.CursorOf(event.Transfer)
+			if event.Type == broadcaster.EventRetraction {
+				// Transfers of the block will be committed again after the reorg
+				retracted := requests.Cursor{BlockNumber: position.BlockNumber, LogIndex: -1}
+				if cursor != nil && retracted.Less(*cursor) {
+					cursor = &retracted
+				}
+			} else if cursor != nil && !cursor.Less(position) {
+				// Already sent during the replay
+				continue
+			}
+
+			if err := writeTransferEvent(w, event.Type, event.Transfer); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+func writeTransferEvent(w http.ResponseWriter, eventType broadcaster.EventType, transfer data.USDTTransfer) error {
+	payload, err := json.Marshal(transfer)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal transfer")
+	}
+
+	id := requests.CursorOf(transfer)
+	if eventType == broadcaster.EventRetraction {
+		id.LogIndex = -1
+	}
+
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", id, eventType, payload)
+	return err
+}
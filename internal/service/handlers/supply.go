@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/requests"
+	"gitlab.com/distributed_lab/ape"
+	"gitlab.com/distributed_lab/ape/problems"
+)
+
+// GetSupply returns the total supply with minted and burned amounts per bucket
+func GetSupply(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	request, err := requests.NewIntervalRangeRequest(r)
+	if err != nil {
+		log.WithError(err).Error("failed to parse request")
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	series, err := db.Supply().Series(request.Interval, request.From, request.To)
+	if err != nil {
+		log.WithError(err).Error("failed to get supply series")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+
+	ape.Render(w, series)
+}
+
+// ListSupplyCheckpoints returns comparisons of the indexed total supply with
+// the contract totalSupply
+func ListSupplyCheckpoints(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	request, err := requests.NewListSupplyCheckpointsRequest(r)
+	if err != nil {
+		log.WithError(err).Error("failed to parse request")
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	checkpointsQ := db.SupplyCheckpoint()
+
+	if request.Mismatched {
+		checkpointsQ = checkpointsQ.FilterMismatched()
+	}
+
+	pageParams := request.GetPageParams()
+
+	checkpoints, err := checkpointsQ.Page(&pageParams).Select()
+	if err != nil {
+		log.WithError(err).Error("failed to get supply checkpoints")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+
+	ape.Render(w, checkpoints)
+}
@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/export"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/requests"
+	"github.com/go-chi/chi"
+	"github.com/pkg/errors"
+	"gitlab.com/distributed_lab/ape"
+	"gitlab.com/distributed_lab/ape/problems"
+	"gitlab.com/distributed_lab/kit/pgdb"
+)
+
+// errFound stops reading an archive once the transfer is found
+var errFound = errors.New("found")
+
+// TransferArchive is an archive file of transfers removed from the db
+type TransferArchive struct {
+	ID            int64     `json:"id"`
+	Month         string    `json:"month"`
+	FromBlock     uint64    `json:"from_block"`
+	ToBlock       uint64    `json:"to_block"`
+	MinTransferID int64     `json:"min_transfer_id"`
+	MaxTransferID int64     `json:"max_transfer_id"`
+	TransferCount uint64    `json:"transfer_count"`
+	Size          int64     `json:"size"`
+	CreatedAt     time.Time `json:"created_at"`
+	// URL is where the Parquet file is downloaded from
+	URL string `json:"url"`
+}
+
+func newTransferArchive(r *http.Request, archive data.TransferArchive) TransferArchive {
+	return TransferArchive{
+		ID:            archive.ID,
+		Month:         archive.Month.Format("2006-01"),
+		FromBlock:     archive.FromBlock,
+		ToBlock:       archive.ToBlock,
+		MinTransferID: archive.MinTransferID,
+		MaxTransferID: archive.MaxTransferID,
+		TransferCount: archive.TransferCount,
+		Size:          archive.Size,
+		CreatedAt:     archive.CreatedAt,
+		URL:           archiveURL(r, archive),
+	}
+}
+
+// ListTransferArchives returns the archive files overlapping the block
+// range, the most recent first
+func ListTransferArchives(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	request, err := requests.NewListTransferArchivesRequest(r)
+	if err != nil {
+		log.WithError(err).Error("failed to parse request")
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	pageParams := request.GetPageParams()
+
+	archives, err := db.TransferArchive().FilterByBlockRange(request.Bounds()).Page(&pageParams).Select()
+	if err != nil {
+		log.WithError(err).Error("failed to get transfer archives")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+
+	response := make([]TransferArchive, 0, len(archives))
+	for _, archive := range archives {
+		response = append(response, newTransferArchive(r, archive))
+	}
+
+	ape.Render(w, response)
+}
+
+func GetTransferArchive(w http.ResponseWriter, r *http.Request) {
+	archive, ok := getTransferArchive(w, r)
+	if !ok {
+		return
+	}
+
+	ape.Render(w, newTransferArchive(r, *archive))
+}
+
+// DownloadTransferArchive serves the Parquet file, or redirects to the
+// published copy when the archive directory is published
+func DownloadTransferArchive(w http.ResponseWriter, r *http.Request) {
+	archive, ok := getTransferArchive(w, r)
+	if !ok {
+		return
+	}
+
+	if Retention(r).URL != "" {
+		http.Redirect(w, r, archiveURL(r, *archive), http.StatusFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", export.ContentType(export.FormatParquet))
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filepath.Base(archive.Path)))
+	http.ServeFile(w, r, archiveFilePath(r, *archive))
+}
+
+func getTransferArchive(w http.ResponseWriter, r *http.Request) (*data.TransferArchive, bool) {
+	log := Log(r)
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		log.WithError(err).Error("failed to parse id")
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return nil, false
+	}
+
+	archive, err := DB(r).TransferArchive().FilterByID(id).Get()
+	if err != nil {
+		log.WithError(err).Error("failed to get transfer archive")
+		ape.RenderErr(w, problems.InternalError())
+		return nil, false
+	}
+	if archive == nil {
+		ape.RenderErr(w, problems.NotFound())
+		return nil, false
+	}
+
+	return archive, true
+}
+
+// getArchivedTransfer serves a transfer missing from the db from the
+// archive holding it. An archive missing from the directory is redirected
+// to, it can only be published elsewhere then.
+func getArchivedTransfer(w http.ResponseWriter, r *http.Request, id int64) {
+	log := Log(r)
+	db := DB(r)
+
+	// id ranges overlap once re-ingested blocks are archived
+	archives, err := db.TransferArchive().FilterByTransferID(id).Select()
+	if err != nil {
+		log.WithError(err).Error("failed to get transfer archives")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+
+	for _, archive := range archives {
+		var transfer *data.USDTTransfer
+		err := export.ReadParquet(archiveFilePath(r, archive), func(batch []data.USDTTransfer) error {
+			for i := range batch {
+				if batch[i].ID == id {
+					transfer = &batch[i]
+					return errFound
+				}
+			}
+			return nil
+		})
+		if os.IsNotExist(errors.Cause(err)) {
+			http.Redirect(w, r, archiveURL(r, archive), http.StatusSeeOther)
+			return
+		}
+		if err != nil && err != errFound {
+			log.WithError(err).Error("failed to read transfer archive")
+			ape.RenderErr(w, problems.InternalError())
+			return
+		}
+		if transfer == nil {
+			continue
+		}
+
+		detailed, err := detailTransfers(db, []data.USDTTransfer{*transfer})
+		if err != nil {
+			log.WithError(err).Error("failed to get transfer details")
+			ape.RenderErr(w, problems.InternalError())
+			return
+		}
+		ape.Render(w, detailed[0])
+		return
+	}
+
+	ape.RenderErr(w, problems.NotFound())
+}
+
+func archiveFilePath(r *http.Request, archive data.TransferArchive) string {
+	return filepath.Join(Retention(r).Dir, filepath.FromSlash(archive.Path))
+}
+
+// archiveURL is where the file is downloaded from, the published copy if
+// the archive directory is published
+func archiveURL(r *http.Request, archive data.TransferArchive) string {
+	if publicURL := Retention(r).URL; publicURL != "" {
+		return strings.TrimSuffix(publicURL, "/") + "/" + archive.Path
+	}
+	return fmt.Sprintf("%s/transfers/archives/%d/file", servicePath(r), archive.ID)
+}
+
+// archivesURL lists the archives of the block range
+func archivesURL(r *http.Request, fromBlock, toBlock uint64) string {
+	query := url.Values{}
+	if fromBlock != 0 {
+		query.Set("from_block", strconv.FormatUint(fromBlock, 10))
+	}
+	query.Set("to_block", strconv.FormatUint(toBlock, 10))
+	return servicePath(r) + "/transfers/archives?" + query.Encode()
+}
+
+// renderArchived redirects a block range archived entirely to its archives
+// and links the archives of a range archived in part. It reports whether
+// the response is rendered.
+func renderArchived(w http.ResponseWriter, r *http.Request, archivedBlock, fromBlock, toBlock uint64) bool {
+	if archivedBlock == 0 || fromBlock > archivedBlock {
+		return false
+	}
+
+	archives := archivesURL(r, fromBlock, min(toBlock, archivedBlock))
+	if toBlock <= archivedBlock {
+		http.Redirect(w, r, archives, http.StatusSeeOther)
+		return true
+	}
+	w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="archives"`, archives))
+	return false
+}
+
+// timeRangeBlocks returns the first and the last block of the time range,
+// the end excluded, zeros when no block is in it
+func timeRangeBlocks(db data.MasterQ, from, to time.Time) (uint64, uint64, error) {
+	first, err := db.Block().FilterByMinTimestamp(from).Page(&pgdb.OffsetPageParams{Limit: 1, Order: pgdb.OrderTypeAsc}).Get()
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "failed to get first block of the range")
+	}
+	last, err := db.Block().FilterByTimestampBefore(to).Page(&pgdb.OffsetPageParams{Limit: 1, Order: pgdb.OrderTypeDesc}).Get()
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "failed to get last block of the range")
+	}
+	if first == nil || last == nil || first.Number > last.Number {
+		return 0, 0, nil
+	}
+	return first.Number, last.Number, nil
+}
+
+// servicePath is the path the service routes are mounted at
+func servicePath(r *http.Request) string {
+	return strings.TrimSuffix(chi.RouteContext(r.Context()).RoutePatterns[0], "/*")
+}
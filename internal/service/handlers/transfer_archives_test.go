@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/go-chi/chi"
+	"gitlab.com/distributed_lab/kit/pgdb"
+	"gitlab.com/distributed_lab/logan/v3"
+)
+
+// archivedQ has the transfers archived up to archivedBlock, none are left
+// in the db
+type archivedQ struct {
+	data.MasterQ
+	archivedBlock uint64
+	blocks        []data.Block
+}
+
+func (q *archivedQ) New() data.MasterQ {
+	return q
+}
+
+func (q *archivedQ) WithContext(context.Context) data.MasterQ {
+	return q
+}
+
+func (q *archivedQ) TransferArchive() data.TransferArchiveQ {
+	return archiveQ{lastBlock: q.archivedBlock}
+}
+
+func (q *archivedQ) USDTTransfer() data.USDTTransferQ {
+	return transfersQ{}
+}
+
+func (q *archivedQ) Block() data.BlockQ {
+	return &blocksQ{blocks: q.blocks}
+}
+
+type archiveQ struct {
+	data.TransferArchiveQ
+	lastBlock uint64
+}
+
+func (q archiveQ) LastBlock() (uint64, error) {
+	return q.lastBlock, nil
+}
+
+type transfersQ struct {
+	data.USDTTransferQ
+}
+
+func (q transfersQ) Page(*pgdb.OffsetPageParams) data.USDTTransferQ {
+	return q
+}
+
+func (q transfersQ) Select() ([]data.USDTTransfer, error) {
+	return nil, nil
+}
+
+// serveArchived runs the handler on the service routes with the blocks from
+// 100 to 109 stored a minute apart, the first five archived
+func serveArchived(handler http.HandlerFunc, target string, header ...string) *httptest.ResponseRecorder {
+	db := &archivedQ{archivedBlock: 104}
+	for number := uint64(100); number < 110; number++ {
+		db.blocks = append(db.blocks, data.Block{
+			Number:    number,
+			Timestamp: time.Unix(int64(number)*60, 0).UTC(),
+		})
+	}
+
+	routeCtx := chi.NewRouteContext()
+	routeCtx.RoutePatterns = []string{"/integrations/usdt-listener-svc/*"}
+
+	ctx := context.WithValue(context.Background(), chi.RouteCtxKey, routeCtx)
+	ctx = CtxLog(logan.New())(ctx)
+	ctx = CtxDB(db)(ctx)
+
+	r := httptest.NewRequest(http.MethodGet, target, nil).WithContext(ctx)
+	for i := 0; i+1 < len(header); i += 2 {
+		r.Header.Set(header[i], header[i+1])
+	}
+	w := httptest.NewRecorder()
+	handler(w, r)
+	return w
+}
+
+func TestListTransfersLinksArchives(t *testing.T) {
+	w := serveArchived(ListUSDTTransfers, "/transfers")
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want 200", w.Code)
+	}
+
+	want := `</integrations/usdt-listener-svc/transfers/archives?to_block=104>; rel="archives"`
+	if link := w.Header().Get("Link"); link != want {
+		t.Errorf("got Link %q, want %q", link, want)
+	}
+}
+
+func TestScreeningReportOfArchivedRange(t *testing.T) {
+	// blocks 101 to 103 are in the range and archived
+	w := serveArchived(GetScreeningReport, "/screening/report?from=6060&to=6240")
+	if w.Code != http.StatusSeeOther {
+		t.Fatalf("got status %d, want 303", w.Code)
+	}
+
+	want := "/integrations/usdt-listener-svc/transfers/archives?from_block=101&to_block=103"
+	if location := w.Header().Get("Location"); location != want {
+		t.Errorf("got Location %q, want %q", location, want)
+	}
+}
+
+func TestStreamTransfersFromArchivedCursor(t *testing.T) {
+	w := serveArchived(StreamTransfers, "/transfers/stream", "Last-Event-ID", "103:5")
+	if w.Code != http.StatusGone {
+		t.Fatalf("got status %d, want 410", w.Code)
+	}
+
+	want := `</integrations/usdt-listener-svc/transfers/archives?from_block=103&to_block=104>; rel="archives"`
+	if link := w.Header().Get("Link"); link != want {
+		t.Errorf("got Link %q, want %q", link, want)
+	}
+}
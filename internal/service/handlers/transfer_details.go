@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+)
+
+// DetailedTransfer is a transfer with the labels of its addresses, nil for
+// unlabeled ones, and its transaction, nil unless enrichment is enabled
+type DetailedTransfer struct {
+	data.USDTTransfer
+	FromLabel   *string
+	ToLabel     *string
+	Transaction *data.EthTransaction
+}
+
+// detailTransfers looks up labels of all addresses and the transactions of
+// the transfers at once
+func detailTransfers(db data.MasterQ, transfers []data.USDTTransfer) ([]DetailedTransfer, error) {
+	result := make([]DetailedTransfer, 0, len(transfers))
+	if len(transfers) == 0 {
+		return result, nil
+	}
+
+	addresses := make([]string, 0, 2*len(transfers))
+	hashes := make([]string, 0, len(transfers))
+	for _, transfer := range transfers {
+		addresses = append(addresses, transfer.FromAddress, transfer.ToAddress)
+		hashes = append(hashes, transfer.TransactionHash)
+	}
+
+	labels, err := db.AddressLabel().FilterByAddress(addresses...).Select()
+	if err != nil {
+		return nil, err
+	}
+
+	byAddress := make(map[string]string, len(labels))
+	for _, label := range labels {
+		byAddress[label.Address] = label.Label
+	}
+
+	lookup := func(address string) *string {
+		if label, ok := byAddress[address]; ok {
+			return &label
+		}
+		return nil
+	}
+
+	transactions, err := db.EthTransaction().FilterByHash(hashes...).Select()
+	if err != nil {
+		return nil, err
+	}
+
+	byHash := make(map[string]*data.EthTransaction, len(transactions))
+	for i := range transactions {
+		byHash[transactions[i].Hash] = &transactions[i]
+	}
+
+	for _, transfer := range transfers {
+		result = append(result, DetailedTransfer{
+			USDTTransfer: transfer,
+			FromLabel:    lookup(transfer.FromAddress),
+			ToLabel:      lookup(transfer.ToAddress),
+			Transaction:  byHash[transfer.TransactionHash],
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/requests"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	webSocketPongWait       = 60 * time.Second
+	webSocketPingPeriod     = webSocketPongWait * 9 / 10
+	webSocketMaxMessageSize = 4096
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     checkWebSocketOrigin,
+}
+
+// checkWebSocketOrigin lets browsers connect from the origins CORS allows.
+// Browsers always send Origin on a WebSocket handshake, other clients don't.
+func checkWebSocketOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	return origin == "" || allowedOrigin(Auth(r).AllowedOrigins, origin)
+}
+
+// webSocketMessage is a server message. Transfer and retraction messages
+// list ids of the connection filters the transfer matched.
+type webSocketMessage struct {
+	Type          string             `json:"type"`
+	ID            string             `json:"id,omitempty"`
+	Subscriptions []string           `json:"subscriptions,omitempty"`
+	Transfer      *data.USDTTransfer `json:"transfer,omitempty"`
+	Error         string             `json:"error,omitempty"`
+}
+
+// webSocketInbound is a parsed client message or the reason it was rejected
+type webSocketInbound struct {
+	command requests.WebSocketCommand
+	err     error
+}
+
+// TransfersWebSocket lets a client manage a set of transfer filters on one
+// connection and pushes every committed or retracted transfer matching any
+// of them. Clients that lag behind the listener or don't read their socket
+// are disconnected.
+func TransfersWebSocket(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	limits := Streaming(r)
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.WithError(err).Debug("failed to upgrade connection")
+		return
+	}
+	defer conn.Close()
+
+	events := Broadcaster(r)
+	sub := events.Subscribe()
+	defer events.Unsubscribe(sub)
+
+	inbound := make(chan webSocketInbound)
+	stop := make(chan struct{})
+	defer close(stop)
+	go readWebSocket(conn, inbound, stop)
+
+	write := func(message webSocketMessage) bool {
+		conn.SetWriteDeadline(time.Now().Add(limits.WriteTimeout))
+		return conn.WriteJSON(message) == nil
+	}
+
+	ping := time.NewTicker(webSocketPingPeriod)
+	defer ping.Stop()
+
+	filters := make(map[string]requests.TransferFilters)
+
+	for {
+		select {
+		case message, ok := <-inbound:
+			if !ok {
+				return
+			}
+
+			reply := webSocketMessage{Type: "error", ID: message.command.ID}
+			switch {
+			case message.err != nil:
+				reply.Error = message.err.Error()
+			case message.command.Action == requests.ActionUnsubscribe:
+				delete(filters, message.command.ID)
+				reply.Type = "unsubscribed"
+			case len(filters) >= limits.MaxFilters && !hasFilter(filters, message.command.ID):
+				reply.Error = "too many filters"
+			default:
+				filters[message.command.ID] = message.command.Filter
+				reply.Type = "subscribed"
+			}
+			if !write(reply) {
+				return
+			}
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(limits.WriteTimeout)); err != nil {
+				return
+			}
+		case event, ok := <-sub.Events():
+			if !ok {
+				if sub.Dropped() {
+					conn.WriteControl(websocket.CloseMessage,
+						websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "consumer is too slow"),
+						time.Now().Add(limits.WriteTimeout))
+				}
+				return
+			}
+
+			matched := matchingFilters(filters, event.Transfer)
+			if len(matched) == 0 {
+				continue
+			}
+
+			transfer := event.Transfer
+			if !write(webSocketMessage{Type: string(event.Type), Subscriptions: matched, Transfer: &transfer}) {
+				return
+			}
+		}
+	}
+}
+
+// readWebSocket parses client messages until the connection breaks
+func readWebSocket(conn *websocket.Conn, inbound chan<- webSocketInbound, stop <-chan struct{}) {
+	defer close(inbound)
+
+	conn.SetReadLimit(webSocketMaxMessageSize)
+	conn.SetReadDeadline(time.Now().Add(webSocketPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(webSocketPongWait))
+	})
+
+	for {
+		_, raw, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		command, err := requests.NewWebSocketCommand(raw)
+		select {
+		case inbound <- webSocketInbound{command: command, err: err}:
+		case <-stop:
+			return
+		}
+	}
+}
+
+func hasFilter(filters map[string]requests.TransferFilters, id string) bool {
+	_, ok := filters[id]
+	return ok
+}
+
+func matchingFilters(filters map[string]requests.TransferFilters, transfer data.USDTTransfer) []string {
+	var matched []string
+	for id, filter := range filters {
+		if filter.Matches(transfer) {
+			matched = append(matched, id)
+		}
+	}
+	sort.Strings(matched)
+	return matched
+}
@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/requests"
+	"gitlab.com/distributed_lab/ape"
+	"gitlab.com/distributed_lab/ape/problems"
+)
+
+func CreateWatchlist(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	request, err := requests.NewCreateWatchlistRequest(r)
+	if err != nil {
+		log.WithError(err).Error("failed to parse request")
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	existing, err := db.Watchlist().FilterByName(request.Name).Get()
+	if err != nil {
+		log.WithError(err).Error("failed to get watchlist")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+	if existing != nil {
+		ape.RenderErr(w, problems.Conflict())
+		return
+	}
+
+	var watchlist *data.Watchlist
+	err = db.Transaction(func(q data.MasterQ) error {
+		var err error
+		watchlist, err = q.Watchlist().Insert(data.Watchlist{
+			Name:        request.Name,
+			Description: request.Description,
+		})
+		if err != nil {
+			return err
+		}
+		return q.WatchlistAddress().Insert(watchlist.ID, request.Addresses...)
+	})
+	if err != nil {
+		log.WithError(err).Error("failed to create watchlist")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+
+	ape.Render(w, watchlist)
+}
+
+func ListWatchlists(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	request, err := requests.NewPageRequest(r)
+	if err != nil {
+		log.WithError(err).Error("failed to parse request")
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	pageParams := request.GetPageParams()
+
+	watchlists, err := db.Watchlist().Page(&pageParams).Select()
+	if err != nil {
+		log.WithError(err).Error("failed to get watchlists")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+
+	ape.Render(w, watchlists)
+}
+
+func GetWatchlist(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	id, err := requests.IDParam(r, "id")
+	if err != nil {
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	watchlist, err := db.Watchlist().FilterByID(id).Get()
+	if err != nil {
+		log.WithError(err).Error("failed to get watchlist")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+	if watchlist == nil {
+		ape.RenderErr(w, problems.NotFound())
+		return
+	}
+
+	ape.Render(w, watchlist)
+}
+
+func DeleteWatchlist(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	id, err := requests.IDParam(r, "id")
+	if err != nil {
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	watchlist, err := db.Watchlist().FilterByID(id).Get()
+	if err != nil {
+		log.WithError(err).Error("failed to get watchlist")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+	if watchlist == nil {
+		ape.RenderErr(w, problems.NotFound())
+		return
+	}
+
+	if err := db.Watchlist().FilterByID(id).Delete(); err != nil {
+		log.WithError(err).Error("failed to delete watchlist")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func ListWatchlistAddresses(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	request, err := requests.NewListWatchlistAddressesRequest(r)
+	if err != nil {
+		log.WithError(err).Error("failed to parse request")
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	pageParams := request.GetPageParams()
+
+	addresses, err := db.WatchlistAddress().FilterByWatchlistID(request.WatchlistID).Page(&pageParams).Select()
+	if err != nil {
+		log.WithError(err).Error("failed to get watchlist addresses")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+
+	ape.Render(w, addresses)
+}
+
+func AddWatchlistAddresses(w http.ResponseWriter, r *http.Request) {
+	request, err := requests.NewAddWatchlistAddressesRequest(r)
+	if err != nil {
+		Log(r).WithError(err).Error("failed to parse request")
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	addWatchlistAddresses(w, r, request)
+}
+
+// ImportWatchlistAddresses adds addresses from a CSV body in a single
+// transaction
+func ImportWatchlistAddresses(w http.ResponseWriter, r *http.Request) {
+	request, err := requests.NewImportWatchlistAddressesRequest(r)
+	if err != nil {
+		Log(r).WithError(err).Error("failed to parse request")
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	addWatchlistAddresses(w, r, request)
+}
+
+func addWatchlistAddresses(w http.ResponseWriter, r *http.Request, request requests.AddWatchlistAddressesRequest) {
+	log := Log(r)
+	db := DB(r)
+
+	watchlist, err := db.Watchlist().FilterByID(request.WatchlistID).Get()
+	if err != nil {
+		log.WithError(err).Error("failed to get watchlist")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+	if watchlist == nil {
+		ape.RenderErr(w, problems.NotFound())
+		return
+	}
+
+	err = db.Transaction(func(q data.MasterQ) error {
+		return q.WatchlistAddress().Insert(request.WatchlistID, request.Addresses...)
+	})
+	if err != nil {
+		log.WithError(err).Error("failed to add watchlist addresses")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+
+	ape.Render(w, ImportResult{Imported: len(request.Addresses)})
+}
+
+func RemoveWatchlistAddress(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	id, err := requests.IDParam(r, "id")
+	if err != nil {
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+	address, err := requests.AddressParam(r, "address")
+	if err != nil {
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	if err := db.WatchlistAddress().FilterByWatchlistID(id).FilterByAddress(address).Delete(); err != nil {
+		log.WithError(err).Error("failed to remove watchlist address")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/requests"
+	"gitlab.com/distributed_lab/ape"
+	"gitlab.com/distributed_lab/ape/problems"
+)
+
+// CreatedWebhook is the only response exposing the signing secret
+type CreatedWebhook struct {
+	data.Webhook
+	Secret string
+}
+
+func CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	request, err := requests.NewCreateWebhookRequest(r)
+	if err != nil {
+		log.WithError(err).Error("failed to parse request")
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	secret := request.Secret
+	if secret == "" {
+		raw := make([]byte, 32)
+		if _, err := rand.Read(raw); err != nil {
+			log.WithError(err).Error("failed to generate webhook secret")
+			ape.RenderErr(w, problems.InternalError())
+			return
+		}
+		secret = hex.EncodeToString(raw)
+	}
+
+	webhook, err := db.Webhook().Insert(data.Webhook{
+		URL:       request.URL,
+		Secret:    secret,
+		Addresses: request.Addresses,
+		MinAmount: request.MinAmount,
+	})
+	if err != nil {
+		log.WithError(err).Error("failed to create webhook")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+
+	ape.Render(w, CreatedWebhook{Webhook: *webhook, Secret: webhook.Secret})
+}
+
+func ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	request, err := requests.NewPageRequest(r)
+	if err != nil {
+		log.WithError(err).Error("failed to parse request")
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	pageParams := request.GetPageParams()
+
+	webhooks, err := db.Webhook().Page(&pageParams).Select()
+	if err != nil {
+		log.WithError(err).Error("failed to get webhooks")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+
+	ape.Render(w, webhooks)
+}
+
+func GetWebhook(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	id, err := requests.IDParam(r, "id")
+	if err != nil {
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	webhook, err := db.Webhook().FilterByID(id).Get()
+	if err != nil {
+		log.WithError(err).Error("failed to get webhook")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+	if webhook == nil {
+		ape.RenderErr(w, problems.NotFound())
+		return
+	}
+
+	ape.Render(w, webhook)
+}
+
+func DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	id, err := requests.IDParam(r, "id")
+	if err != nil {
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	webhook, err := db.Webhook().FilterByID(id).Get()
+	if err != nil {
+		log.WithError(err).Error("failed to get webhook")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+	if webhook == nil {
+		ape.RenderErr(w, problems.NotFound())
+		return
+	}
+
+	if err := db.Webhook().FilterByID(id).Delete(); err != nil {
+		log.WithError(err).Error("failed to delete webhook")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func ListWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	request, err := requests.NewListWebhookDeliveriesRequest(r)
+	if err != nil {
+		log.WithError(err).Error("failed to parse request")
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	deliveriesQ := db.WebhookDelivery().FilterByWebhookID(request.WebhookID)
+	if request.Status != "" {
+		deliveriesQ = deliveriesQ.FilterByStatus(request.Status)
+	}
+
+	pageParams := request.GetPageParams()
+
+	deliveries, err := deliveriesQ.Page(&pageParams).Select()
+	if err != nil {
+		log.WithError(err).Error("failed to get webhook deliveries")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+
+	ape.Render(w, deliveries)
+}
+
+// RedeliverWebhookDelivery schedules a dead or pending delivery again with
+// no attempts made, e.g. after the receiver fixed the cause of dead
+// deliveries. Delivered ones are not sent twice.
+func RedeliverWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	log := Log(r)
+	db := DB(r)
+
+	id, err := requests.IDParam(r, "delivery_id")
+	if err != nil {
+		ape.RenderErr(w, problems.BadRequest(err)...)
+		return
+	}
+
+	delivery, err := db.WebhookDelivery().Redeliver(id)
+	if err != nil {
+		log.WithError(err).Error("failed to reset webhook delivery")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+	if delivery == nil {
+		renderNotRedelivered(w, r, id)
+		return
+	}
+
+	ape.Render(w, delivery)
+}
+
+// renderNotRedelivered tells a delivered delivery from a missing one
+func renderNotRedelivered(w http.ResponseWriter, r *http.Request, id int64) {
+	delivery, err := DB(r).WebhookDelivery().FilterByID(id).Get()
+	if err != nil {
+		Log(r).WithError(err).Error("failed to get webhook delivery")
+		ape.RenderErr(w, problems.InternalError())
+		return
+	}
+	if delivery == nil {
+		ape.RenderErr(w, problems.NotFound())
+		return
+	}
+
+	problem := problems.Conflict()
+	problem.Detail = "delivery is already delivered"
+	ape.RenderErr(w, problem)
+}
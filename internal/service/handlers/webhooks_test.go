@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/go-chi/chi"
+	"gitlab.com/distributed_lab/logan/v3"
+)
+
+// deliveriesQ redelivers the stored deliveries unless they are delivered
+type deliveriesQ struct {
+	data.MasterQ
+	deliveries map[int64]data.WebhookDelivery
+}
+
+func (q *deliveriesQ) New() data.MasterQ {
+	return q
+}
+
+func (q *deliveriesQ) WithContext(context.Context) data.MasterQ {
+	return q
+}
+
+func (q *deliveriesQ) WebhookDelivery() data.WebhookDeliveryQ {
+	return &deliveryQ{deliveries: q.deliveries}
+}
+
+type deliveryQ struct {
+	data.WebhookDeliveryQ
+	deliveries map[int64]data.WebhookDelivery
+	id         int64
+}
+
+func (q *deliveryQ) FilterByID(id int64) data.WebhookDeliveryQ {
+	q.id = id
+	return q
+}
+
+func (q *deliveryQ) Get() (*data.WebhookDelivery, error) {
+	delivery, ok := q.deliveries[q.id]
+	if !ok {
+		return nil, nil
+	}
+	return &delivery, nil
+}
+
+func (q *deliveryQ) Redeliver(id int64) (*data.WebhookDelivery, error) {
+	delivery, ok := q.deliveries[id]
+	if !ok || delivery.Status == data.DeliveryStatusDelivered {
+		return nil, nil
+	}
+	delivery.Status = data.DeliveryStatusPending
+	delivery.LastError = nil
+	return &delivery, nil
+}
+
+func TestRedeliverWebhookDelivery(t *testing.T) {
+	reason := "status 500"
+	db := &deliveriesQ{deliveries: map[int64]data.WebhookDelivery{
+		1: {ID: 1, Status: data.DeliveryStatusDead, LastError: &reason},
+		2: {ID: 2, Status: data.DeliveryStatusDelivered},
+	}}
+
+	cases := []struct {
+		id     string
+		status int
+	}{
+		{id: "1", status: http.StatusOK},
+		{id: "2", status: http.StatusConflict},
+		{id: "3", status: http.StatusNotFound},
+	}
+
+	for _, c := range cases {
+		routeCtx := chi.NewRouteContext()
+		routeCtx.URLParams.Add("delivery_id", c.id)
+
+		ctx := context.WithValue(context.Background(), chi.RouteCtxKey, routeCtx)
+		ctx = CtxLog(logan.New())(ctx)
+		ctx = CtxDB(db)(ctx)
+
+		r := httptest.NewRequest(http.MethodPost, "/webhooks/deliveries/"+c.id+"/redeliver", nil).WithContext(ctx)
+		w := httptest.NewRecorder()
+		RedeliverWebhookDelivery(w, r)
+		if w.Code != c.status {
+			t.Errorf("delivery %s: got status %d, want %d", c.id, w.Code, c.status)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package listener
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/contracts"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// baselineBatchSize is the number of balanceOf calls sent in one RPC batch
+const baselineBatchSize = 100
+
+// logsToBalanceChanges converts the block logs to signed balance movements.
+// Issue and Redeem credit and debit the contract owner at that block,
+// DestroyedBlackFunds wipes the blacklisted user's balance.
+func (l *Listener) logsToBalanceChanges(ctx context.Context, logs []types.Log, blockNum uint64) ([]data.BalanceChange, error) {
+	changes := make([]data.BalanceChange, 0, 2*len(logs))
+
+	var owner *common.Address
+
+	for _, log := range logs {
+		change := func(address common.Address, delta *big.Int) {
+			changes = append(changes, data.BalanceChange{
+				Address:     address.Hex(),
+				Delta:       delta.String(),
+				BlockNumber: blockNum,
+				LogIndex:    uint64(log.Index),
+			})
+		}
+
+		switch {
+		case isEvent(log, transferEventTopic):
+			event, err := l.usdt.ParseTransfer(log)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to parse Transfer event")
+			}
+			change(event.From, new(big.Int).Neg(event.Value))
+			change(event.To, event.Value)
+		case isEvent(log, issueEventTopic), isEvent(log, redeemEventTopic):
+			if owner == nil {
+				address, err := l.usdt.Owner(&bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(blockNum)})
+				if err != nil {
+					return nil, errors.Wrap(err, "failed to get contract owner")
+				}
+				owner = &address
+			}
+			amount := new(big.Int).SetBytes(log.Data)
+			if isEvent(log, redeemEventTopic) {
+				amount.Neg(amount)
+			}
+			change(*owner, amount)
+		case isEvent(log, destroyedBlackFundsEventTopic):
+			event, err := l.usdt.ParseDestroyedBlackFunds(log)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to parse DestroyedBlackFunds event")
+			}
+			change(event.BlackListedUser, new(big.Int).Neg(event.Balance))
+		}
+	}
+
+	return changes, nil
+}
+
+// balanceBaselines returns the balances before the starting block of the
+// changed addresses seen for the first time. The listener only applies the
+// events starting from that block, so the balance read from the contract
+// right before it is the base the changes are added to.
+func (l *Listener) balanceBaselines(ctx context.Context, changes []data.BalanceChange) ([]data.BalanceChange, error) {
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	baselineBlock := l.config.Ethereum().StartingBlock - 1
+
+	addresses := make([]string, 0, len(changes))
+	seen := make(map[string]bool, len(changes))
+	for _, change := range changes {
+		if !seen[change.Address] {
+			seen[change.Address] = true
+			addresses = append(addresses, change.Address)
+		}
+	}
+
+	seeded, err := l.db.WithContext(ctx).BalanceChange().FilterByAddress(addresses...).FilterByBlockNumber(baselineBlock).Select()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to select balance baselines")
+	}
+	for _, baseline := range seeded {
+		seen[baseline.Address] = false
+	}
+
+	missing := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		if seen[address] {
+			missing = append(missing, address)
+		}
+	}
+
+	balances, err := l.balancesAt(ctx, missing, baselineBlock)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get baseline balances")
+	}
+
+	baselines := make([]data.BalanceChange, 0, len(missing))
+	for i, address := range missing {
+		// zero balances are stored too, so the contract isn't asked again
+		baselines = append(baselines, data.BalanceChange{
+			Address:     address,
+			Delta:       balances[i].String(),
+			BlockNumber: baselineBlock,
+		})
+	}
+
+	return baselines, nil
+}
+
+// balancesAt reads balanceOf of the addresses at the block in RPC batches of
+// baselineBatchSize calls, during the first blocks most addresses are new
+// and calling them one by one would keep the listener behind the head
+func (l *Listener) balancesAt(ctx context.Context, addresses []string, blockNum uint64) ([]*big.Int, error) {
+	contractABI, err := contracts.ContractsMetaData.GetAbi()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get contract ABI")
+	}
+	contract := common.HexToAddress(USDTContractAddress)
+	block := hexutil.EncodeBig(new(big.Int).SetUint64(blockNum))
+
+	results := make([]hexutil.Bytes, len(addresses))
+	for start := 0; start < len(addresses); start += baselineBatchSize {
+		batch := make([]rpc.BatchElem, 0, baselineBatchSize)
+		for i := start; i < len(addresses) && i < start+baselineBatchSize; i++ {
+			input, err := contractABI.Pack("balanceOf", common.HexToAddress(addresses[i]))
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to pack balanceOf call")
+			}
+			batch = append(batch, rpc.BatchElem{
+				Method: "eth_call",
+				Args: []interface{}{
+					map[string]interface{}{"to": contract, "data": hexutil.Bytes(input)},
+					block,
+				},
+				Result: &results[i],
+			})
+		}
+
+		if err := l.client.BatchCallContext(ctx, batch); err != nil {
+			return nil, errors.Wrap(err, "failed to call balanceOf")
+		}
+		for i, elem := range batch {
+			if elem.Error != nil {
+				return nil, errors.Wrap(elem.Error, "failed to call balanceOf", logan.F{"address": addresses[start+i]})
+			}
+		}
+	}
+
+	balances := make([]*big.Int, len(addresses))
+	for i, result := range results {
+		values, err := contractABI.Unpack("balanceOf", result)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to unpack balanceOf result", logan.F{"address": addresses[i]})
+		}
+		balances[i] = values[0].(*big.Int)
+	}
+	return balances, nil
+}
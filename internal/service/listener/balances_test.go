@@ -0,0 +1,74 @@
+package listener
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/contracts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// balancesChain answers batched balanceOf calls with the last byte of the
+// address as the balance
+type balancesChain struct {
+	Chain
+	batches []int
+}
+
+func (c *balancesChain) BatchCallContext(_ context.Context, batch []rpc.BatchElem) error {
+	contractABI, err := contracts.ContractsMetaData.GetAbi()
+	if err != nil {
+		return err
+	}
+
+	c.batches = append(c.batches, len(batch))
+	for i, elem := range batch {
+		call := elem.Args[0].(map[string]interface{})
+		if elem.Method != "eth_call" || elem.Args[1] != "0x63" {
+			return fmt.Errorf("unexpected call %s at %v", elem.Method, elem.Args[1])
+		}
+		args, err := contractABI.Methods["balanceOf"].Inputs.Unpack(call["data"].(hexutil.Bytes)[4:])
+		if err != nil {
+			return err
+		}
+		address := args[0].(common.Address)
+		output, err := contractABI.Methods["balanceOf"].Outputs.Pack(big.NewInt(int64(address[19])))
+		if err != nil {
+			return err
+		}
+		raw, _ := json.Marshal(hexutil.Bytes(output))
+		if err := json.Unmarshal(raw, batch[i].Result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestBalancesAtBatchesCalls(t *testing.T) {
+	addresses := make([]string, baselineBatchSize+50)
+	for i := range addresses {
+		addresses[i] = common.BigToAddress(big.NewInt(int64(i%200 + 1))).Hex()
+	}
+
+	chain := &balancesChain{}
+	l := &Listener{client: chain}
+
+	balances, err := l.balancesAt(context.Background(), addresses, 99)
+	if err != nil {
+		t.Fatalf("failed to get balances: %v", err)
+	}
+
+	if len(chain.batches) != 2 || chain.batches[0] != baselineBatchSize || chain.batches[1] != 50 {
+		t.Errorf("got batches %v, want %d and 50 calls", chain.batches, baselineBatchSize)
+	}
+	for i, balance := range balances {
+		if want := int64(i%200 + 1); balance.Int64() != want {
+			t.Errorf("balance of %s is %s, want %d", addresses[i], balance, want)
+		}
+	}
+}
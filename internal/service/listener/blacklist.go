@@ -0,0 +1,42 @@
+package listener
+
+import (
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/ethereum/go-ethereum/core/types"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// logsToBlacklistEvents extracts AddedBlackList and RemovedBlackList events
+// used to flag transfers of blacklisted addresses
+func (l *Listener) logsToBlacklistEvents(logs []types.Log, blockNum uint64) ([]data.BlacklistEvent, error) {
+	var events []data.BlacklistEvent
+
+	for _, log := range logs {
+		switch {
+		case isEvent(log, addedBlackListEventTopic):
+			event, err := l.usdt.ParseAddedBlackList(log)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to parse AddedBlackList event")
+			}
+			events = append(events, data.BlacklistEvent{
+				Address:     event.User.Hex(),
+				Blacklisted: true,
+				BlockNumber: blockNum,
+				LogIndex:    int64(log.Index),
+			})
+		case isEvent(log, removedBlackListEventTopic):
+			event, err := l.usdt.ParseRemovedBlackList(log)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to parse RemovedBlackList event")
+			}
+			events = append(events, data.BlacklistEvent{
+				Address:     event.User.Hex(),
+				Blacklisted: false,
+				BlockNumber: blockNum,
+				LogIndex:    int64(log.Index),
+			})
+		}
+	}
+
+	return events, nil
+}
@@ -0,0 +1,20 @@
+package listener
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Chain is the source of blocks, logs and contract state of the listener.
+// It is the RPC node when running live and a recording when replaying, see
+// the recording package.
+type Chain interface {
+	bind.ContractBackend
+
+	ChainID(ctx context.Context) (*big.Int, error)
+	BlockNumber(ctx context.Context) (uint64, error)
+	BatchCallContext(ctx context.Context, batch []rpc.BatchElem) error
+}
@@ -0,0 +1,127 @@
+package listener
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/contracts"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// loadContracts reads the USDT contract lineage watched by the listener
+func (l *Listener) loadContracts() error {
+	lineage, err := l.db.USDTContract().Select()
+	if err != nil {
+		return errors.Wrap(err, "failed to select USDT contracts")
+	}
+	l.contracts = lineage
+	return nil
+}
+
+// syncUpgrades follows deprecated and upgradedAddress of the last known
+// contract right before the block. It catches upgrades made before the
+// listener started, later ones are picked up from Deprecate events.
+func (l *Listener) syncUpgrades(ctx context.Context, blockNum uint64) error {
+	if err := l.loadContracts(); err != nil {
+		return err
+	}
+
+	opts := &bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(blockNum - 1)}
+
+	for {
+		last := l.contracts[len(l.contracts)-1]
+
+		caller, err := contracts.NewContractsCaller(common.HexToAddress(last.Address), l.client)
+		if err != nil {
+			return errors.Wrap(err, "failed to bind USDT contract")
+		}
+		deprecated, err := caller.Deprecated(opts)
+		if err != nil {
+			return errors.Wrap(err, "failed to get deprecated")
+		}
+		if !deprecated {
+			return nil
+		}
+		upgraded, err := caller.UpgradedAddress(opts)
+		if err != nil {
+			return errors.Wrap(err, "failed to get upgraded address")
+		}
+		if l.isWatched(upgraded.Hex()) {
+			return nil
+		}
+
+		contract := data.USDTContract{
+			Address:     upgraded.Hex(),
+			StartBlock:  blockNum,
+			Predecessor: &last.Address,
+		}
+		if err := l.db.USDTContract().Upgrade(contract); err != nil {
+			return errors.Wrap(err, "failed to insert upgraded contract")
+		}
+		l.log.WithFields(logan.F{
+			"deprecated": last.Address,
+			"upgraded":   contract.Address,
+		}).Info("USDT contract is deprecated, watching the upgraded contract")
+
+		if err := l.loadContracts(); err != nil {
+			return err
+		}
+	}
+}
+
+// watchedAddresses returns the contracts of the lineage started at or
+// before the block
+func (l *Listener) watchedAddresses(blockNum uint64) []common.Address {
+	addresses := make([]common.Address, 0, len(l.contracts))
+	for _, contract := range l.contracts {
+		if contract.StartBlock <= blockNum {
+			addresses = append(addresses, common.HexToAddress(contract.Address))
+		}
+	}
+	return addresses
+}
+
+func (l *Listener) isWatched(address string) bool {
+	for _, contract := range l.contracts {
+		if contract.Address == address {
+			return true
+		}
+	}
+	return false
+}
+
+// logsToUpgrades converts Deprecate events of the watched contracts to the
+// upgraded contracts to watch starting from the block
+func (l *Listener) logsToUpgrades(logs []types.Log, blockNum uint64) ([]data.USDTContract, error) {
+	var upgrades []data.USDTContract
+
+	for _, log := range logs {
+		if !isEvent(log, deprecateEventTopic) {
+			continue
+		}
+
+		event, err := l.usdt.ParseDeprecate(log)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse Deprecate event")
+		}
+		if l.isWatched(event.NewAddress.Hex()) {
+			continue
+		}
+
+		predecessor := log.Address.Hex()
+		logIndex := int64(log.Index)
+		upgrades = append(upgrades, data.USDTContract{
+			Address:     event.NewAddress.Hex(),
+			StartBlock:  blockNum,
+			Predecessor: &predecessor,
+			LogIndex:    &logIndex,
+		})
+	}
+
+	return upgrades, nil
+}
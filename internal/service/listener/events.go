@@ -0,0 +1,32 @@
+package listener
+
+import (
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/contracts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Topics of the USDT contract events the listener decodes.
+var (
+	transferEventTopic            = eventTopic("Transfer")
+	issueEventTopic               = eventTopic("Issue")
+	redeemEventTopic              = eventTopic("Redeem")
+	destroyedBlackFundsEventTopic = eventTopic("DestroyedBlackFunds")
+	addedBlackListEventTopic      = eventTopic("AddedBlackList")
+	removedBlackListEventTopic    = eventTopic("RemovedBlackList")
+	paramsEventTopic              = eventTopic("Params")
+	deprecateEventTopic           = eventTopic("Deprecate")
+)
+
+func eventTopic(name string) common.Hash {
+	contractABI, err := contracts.ContractsMetaData.GetAbi()
+	if err != nil {
+		panic(err)
+	}
+	return contractABI.Events[name].ID
+}
+
+// isEvent reports whether the log was emitted by the event with the given topic
+func isEvent(log types.Log, topic common.Hash) bool {
+	return len(log.Topics) > 0 && log.Topics[0] == topic
+}
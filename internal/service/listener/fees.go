@@ -0,0 +1,143 @@
+package listener
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+var basisPointsDenominator = big.NewInt(10000)
+
+// logsToFeeParams extracts Params events changing basisPointsRate and maximumFee
+func (l *Listener) logsToFeeParams(logs []types.Log, blockNum uint64) ([]data.FeeParams, error) {
+	var params []data.FeeParams
+
+	for _, log := range logs {
+		if !isEvent(log, paramsEventTopic) {
+			continue
+		}
+
+		event, err := l.usdt.ParseParams(log)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse Params event")
+		}
+		params = append(params, data.FeeParams{
+			BlockNumber: blockNum,
+			LogIndex:    int64(log.Index),
+			BasisPoints: event.FeeBasisPoints.String(),
+			MaximumFee:  event.MaxFee.String(),
+		})
+	}
+
+	return params, nil
+}
+
+// feeParamsBefore returns the fee parameters in effect before the block.
+// When the history is still empty they are read from the contract right
+// before the block and reported as a baseline to be stored.
+func (l *Listener) feeParamsBefore(ctx context.Context, blockNum uint64) (params *data.FeeParams, baseline bool, err error) {
+	params, err = l.db.WithContext(ctx).FeeParams().Latest()
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to get latest fee params")
+	}
+	if params != nil {
+		return params, false, nil
+	}
+
+	opts := &bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(blockNum - 1)}
+
+	basisPoints, err := l.usdt.BasisPointsRate(opts)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to get basis points rate")
+	}
+	maximumFee, err := l.usdt.MaximumFee(opts)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to get maximum fee")
+	}
+
+	return &data.FeeParams{
+		BlockNumber: blockNum - 1,
+		LogIndex:    -1,
+		BasisPoints: basisPoints.String(),
+		MaximumFee:  maximumFee.String(),
+	}, true, nil
+}
+
+// linkFees marks fee transfers and sets the fee of their principal transfers.
+// params holds the parameters in effect before the first transfer followed
+// by the changes within the block.
+//
+// The contract emits the fee Transfer to the owner right before the principal
+// Transfer from the same sender, a pair is linked only when the fee matches
+// the parameters in effect at that point of the block.
+func (l *Listener) linkFees(ctx context.Context, transfers []data.USDTTransfer, params []data.FeeParams, blockNum uint64) error {
+	current, changes := params[0], params[1:]
+	var owner string
+
+	for i := 0; i+1 < len(transfers); i++ {
+		for len(changes) > 0 && changes[0].LogIndex < int64(transfers[i].LogIndex) {
+			current, changes = changes[0], changes[1:]
+		}
+		if current.BasisPoints == "0" {
+			continue
+		}
+
+		fee, principal := &transfers[i], &transfers[i+1]
+		if principal.TransactionHash != fee.TransactionHash ||
+			principal.LogIndex != fee.LogIndex+1 ||
+			principal.FromAddress != fee.FromAddress {
+			continue
+		}
+
+		if owner == "" {
+			address, err := l.usdt.Owner(&bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(blockNum)})
+			if err != nil {
+				return errors.Wrap(err, "failed to get contract owner")
+			}
+			owner = address.Hex()
+		}
+		if fee.ToAddress != owner || !isFee(fee.Amount, principal.Amount, current) {
+			continue
+		}
+
+		principalLogIndex := int64(principal.LogIndex)
+		fee.PrincipalLogIndex = &principalLogIndex
+		principal.Fee = fee.Amount
+		i++
+	}
+
+	return nil
+}
+
+// isFee reports whether fee is what the contract charges for transferring
+// fee + amount: min(value * basisPointsRate / 10000, maximumFee)
+func isFee(fee, amount string, params data.FeeParams) bool {
+	feeValue, ok := new(big.Int).SetString(fee, 10)
+	if !ok || feeValue.Sign() == 0 {
+		return false
+	}
+	amountValue, ok := new(big.Int).SetString(amount, 10)
+	if !ok {
+		return false
+	}
+	basisPoints, ok := new(big.Int).SetString(params.BasisPoints, 10)
+	if !ok {
+		return false
+	}
+	maximumFee, ok := new(big.Int).SetString(params.MaximumFee, 10)
+	if !ok {
+		return false
+	}
+
+	expected := new(big.Int).Add(feeValue, amountValue)
+	expected.Mul(expected, basisPoints).Div(expected, basisPointsDenominator)
+	if expected.Cmp(maximumFee) > 0 {
+		expected = maximumFee
+	}
+
+	return expected.Cmp(feeValue) == 0
+}
@@ -5,14 +5,20 @@ import (
 	"math/big"
 	"time"
 
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/contracts"
 	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/config"
 	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/metrics"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/broadcaster"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/recording"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/status"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/tracing"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"gitlab.com/distributed_lab/logan/v3"
 	"gitlab.com/distributed_lab/logan/v3/errors"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const (
@@ -20,35 +26,87 @@ const (
     BlockTime           = 12 * time.Second // Ethereum block time
 )
 
+var errReorg = errors.New("chain reorganization detected")
+
+// BlockObserver is notified after every committed and rolled back block.
+// Observers are called from the listener loop and must not block it for long.
+type BlockObserver interface {
+    BlockCommitted(blockNum uint64)
+    BlockReverted(blockNum uint64, transfers []data.USDTTransfer)
+}
+
 // Listener struct
 type Listener struct {
-    client *ethclient.Client
+    client Chain
+    usdt   *contracts.Contracts
     db     data.MasterQ
     log    *logan.Entry
     config config.Config
+    events *broadcaster.Broadcaster
+    status *status.Status
+
+    observers []BlockObserver
+    // stopBlock is the last block to process, zero to follow the chain
+    stopBlock uint64
+    // contracts is the USDT contract lineage, upgraded contracts are watched
+    // starting from their deprecation block
+    contracts []data.USDTContract
 }
 
-// NewListener creates a new Listener instance
-func NewListener(config config.Config, db data.MasterQ, log *logan.Entry) (*Listener, error) {
-    client, err := ethclient.Dial(config.Ethereum().RPCURL)
+// NewListener creates a new Listener instance reading the chain from the RPC node
+func NewListener(config config.Config, db data.MasterQ, log *logan.Entry, events *broadcaster.Broadcaster, status *status.Status) (*Listener, error) {
+    client, err := metrics.DialEthClient(config.Ethereum().RPCURL)
     if err != nil {
         return nil, errors.Wrap(err, "failed to connect to Ethereum client")
     }
+    return NewChainListener(config, client, db, log, events, status)
+}
+
+// NewChainListener creates a Listener reading the chain from the given source
+func NewChainListener(config config.Config, client Chain, db data.MasterQ, log *logan.Entry, events *broadcaster.Broadcaster, status *status.Status) (*Listener, error) {
+    usdt, err := contracts.NewContracts(common.HexToAddress(USDTContractAddress), client)
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to bind USDT contract")
+    }
     return &Listener{
         client: client,
+        usdt:   usdt,
         db:     db,
         log:    log,
         config: config,
+        events: events,
+        status: status,
     }, nil
 }
 
+// AddObserver registers an observer, it has to be called before Listen
+func (l *Listener) AddObserver(observer BlockObserver) {
+    l.observers = append(l.observers, observer)
+}
+
+// StopAt makes Listen return once the block is processed, it has to be
+// called before Listen
+func (l *Listener) StopAt(blockNum uint64) {
+    l.stopBlock = blockNum
+}
+
 // Listen starts the main loop for listening to USDT transfers
 func (l *Listener) Listen(ctx context.Context, processHist bool, configStartingBlock uint64) error {
+    chainID, err := l.client.ChainID(ctx)
+    if err != nil {
+        return errors.Wrap(err, "failed to get chain ID")
+    }
+    l.status.SetChainID(chainID.Uint64())
+
     startBlock, err := l.getStartingBlock(ctx, configStartingBlock)
     if err != nil {
         return errors.Wrap(err, "failed to get starting block")
     }
 
+    if err := l.syncUpgrades(ctx, startBlock); err != nil {
+        return errors.Wrap(err, "failed to sync USDT contract upgrades")
+    }
+
     l.log.WithFields(logan.F{
         "configStartingBlock": configStartingBlock,
         "actualStartingBlock": startBlock,
@@ -71,28 +129,75 @@ func (l *Listener) getStartingBlock(ctx context.Context, configStartingBlock uin
         return 0, errors.Wrap(err, "failed to get current block number")
     }
 
-    return min(startingBlock, currentBlock), nil
+    // a chain still at its genesis is followed from the first block
+    return max(min(startingBlock, currentBlock), 1), nil
 }
 
 // processBlocks continuously processes blocks
 func (l *Listener) processBlocks(ctx context.Context, startBlock uint64) error {
     l.log.WithField("startingBlock", startBlock).Info("Starting to process blocks")
 
+    // reorgDepth counts blocks rolled back since the last processed block
+    var reorgDepth uint64
+
     for {
+        select {
+        case <-ctx.Done():
+            return ctx.Err()
+        default:
+            // Continue processing
+        }
+
+        paused, err := l.db.LastProcessedBlock().Paused()
+        if err != nil {
+            l.log.WithError(err).Error("Failed to get listener pause from DB")
+            l.status.SetError(err)
+            time.Sleep(time.Second)
+            continue
+        }
+        l.status.SetPaused(paused)
+        if paused {
+            time.Sleep(time.Second)
+            continue
+        }
+
+        job, err := l.db.ReingestionJob().FilterActive().Get()
+        if err != nil {
+            l.log.WithError(err).Error("Failed to get re-ingestion job from DB")
+            l.status.SetError(err)
+            time.Sleep(time.Second)
+            continue
+        }
+        if job != nil && job.Status != data.ReingestionStatusReprocessing {
+            if err := l.rewind(ctx, *job); err != nil {
+                l.log.WithError(err).WithField("jobID", job.ID).Error("Failed to roll back blocks for re-ingestion")
+                l.status.SetError(err)
+                time.Sleep(time.Second)
+                continue
+            }
+            startBlock = job.FromBlock
+            continue
+        }
+
         currentBlock, err := l.client.BlockNumber(ctx)
         if err != nil {
             l.log.WithError(err).Error("Failed to get current block number")
+            l.status.SetError(err)
             time.Sleep(BlockTime)
             continue
         }
+        metrics.HeadBlock.Set(float64(currentBlock))
+        l.status.SetHead(currentBlock)
 
         // Double-check that we're starting from the correct block
         lastProcessedBlock, err := l.db.LastProcessedBlock().Get()
         if err != nil {
             l.log.WithError(err).Error("Failed to get last processed block from DB")
+            l.status.SetError(err)
             time.Sleep(time.Second)
             continue
         }
+        l.status.SetLastProcessedBlock(lastProcessedBlock)
 
         if lastProcessedBlock >= startBlock {
             l.log.WithFields(logan.F{
@@ -102,58 +207,288 @@ func (l *Listener) processBlocks(ctx context.Context, startBlock uint64) error {
             startBlock = lastProcessedBlock + 1
         }
 
+        if l.stopBlock != 0 && startBlock > l.stopBlock {
+            l.log.WithField("stopBlock", l.stopBlock).Info("Stop block processed")
+            return nil
+        }
+
+        if startBlock > currentBlock {
+            time.Sleep(BlockTime)
+            continue
+        }
+
         // Log processing only if we're actually processing a block
         l.log.WithFields(logan.F{
             "currentNetworkBlock": currentBlock,
             "processingBlock":     startBlock,
         }).Info("Processing block")
 
-        if err := l.processBlock(ctx, startBlock); err != nil {
-            l.log.WithError(err).WithField("blockNumber", startBlock).Error("Failed to process block")
+        blockCtx, span := tracing.Start(ctx, "processBlock", attribute.Int64("block.number", int64(startBlock)))
+        err = l.processBlock(blockCtx, startBlock)
+        if errors.Cause(err) == errReorg {
+            span.SetAttributes(attribute.Bool("block.reorg", true))
+            tracing.Log(blockCtx, l.log).WithField("blockNumber", startBlock-1).Warn("Chain reorganization detected, rolling back block")
+            err = l.rollbackBlock(blockCtx, startBlock-1)
+            tracing.End(span, err)
+            if err != nil {
+                tracing.Log(blockCtx, l.log).WithError(err).WithField("blockNumber", startBlock-1).Error("Failed to roll back block")
+                l.status.SetError(err)
+                time.Sleep(time.Second)
+                continue
+            }
+            if reorgDepth == 0 {
+                metrics.Reorgs.Inc()
+            }
+            reorgDepth++
+            startBlock--
+            continue
+        }
+        tracing.End(span, err)
+        if errors.Cause(err) == recording.ErrNotRecorded {
+            // retrying can't help, the block is missing from the recording
+            return errors.Wrap(err, "failed to replay block", logan.F{"blockNumber": startBlock})
+        }
+        if err != nil {
+            tracing.Log(blockCtx, l.log).WithError(err).WithField("blockNumber", startBlock).Error("Failed to process block")
+            l.status.SetError(err)
             time.Sleep(time.Second)
             continue
         }
 
-        // Increment the block number after successful processing
-        startBlock++
-
-        // Update the last processed block
-        if err := l.db.LastProcessedBlock().Update(startBlock); err != nil {
-            l.log.WithError(err).Error("Failed to update last processed block")
+        if reorgDepth > 0 {
+            metrics.ReorgDepth.Observe(float64(reorgDepth))
+            reorgDepth = 0
         }
-
-        select {
-        case <-ctx.Done():
-            return ctx.Err()
-        default:
-            // Continue processing
+        metrics.BlocksProcessed.Inc()
+        metrics.LastProcessedBlock.Set(float64(startBlock))
+        metrics.Lag.Set(float64(currentBlock - startBlock))
+        l.status.SetLastProcessedBlock(startBlock)
+
+        if job != nil {
+            if err := l.reingestionProgress(*job, startBlock); err != nil {
+                l.log.WithError(err).WithField("jobID", job.ID).Error("Failed to update re-ingestion job progress")
+            }
         }
+
+        // Increment the block number after successful processing
+        startBlock++
     }
 }
 
 // processBlock processes a single block
 func (l *Listener) processBlock(ctx context.Context, blockNum uint64) error {
-    return l.db.Transaction(func(q data.MasterQ) error {
-        // Get logs for the block
-        logs, err := l.getBlockLogs(ctx, blockNum)
-        if err != nil {
+    // the baselines and the parent check read the block before
+    if blockNum == 0 {
+        return errors.New("the genesis block can't be processed")
+    }
+
+    db := l.db.WithContext(ctx)
+
+    header, err := l.client.HeaderByNumber(ctx, new(big.Int).SetUint64(blockNum))
+    if err != nil {
+        return errors.Wrap(err, "failed to get block header")
+    }
+
+    parent, err := db.Block().FilterByNumber(blockNum - 1).Get()
+    if err != nil {
+        return errors.Wrap(err, "failed to get parent block")
+    }
+    if parent != nil && parent.Hash != header.ParentHash.Hex() {
+        return errReorg
+    }
+
+    // Get logs for the block, by hash so they match the header
+    addresses := l.watchedAddresses(blockNum)
+    logs, err := l.getBlockLogs(ctx, header.Hash(), addresses)
+    if err != nil {
+        return errors.Wrap(err, "failed to get block logs")
+    }
+
+    metrics.LogsPerBlock.Observe(float64(len(logs)))
+
+    upgrades, err := l.logsToUpgrades(logs, blockNum)
+    if err != nil {
+        return errors.Wrap(err, "failed to convert logs to contract upgrades")
+    }
+    if len(upgrades) > 0 {
+        // Upgraded contracts are indexed starting from the deprecation block
+        for _, upgrade := range upgrades {
+            addresses = append(addresses, common.HexToAddress(upgrade.Address))
+        }
+        if logs, err = l.getBlockLogs(ctx, header.Hash(), addresses); err != nil {
             return errors.Wrap(err, "failed to get block logs")
         }
+    }
 
-        // Convert logs to USDT transfers
-        transfers, err := l.logsToTransfers(ctx, logs, blockNum)
-        if err != nil {
-            return errors.Wrap(err, "failed to convert logs to transfers")
+    // Convert logs to USDT transfers
+    transfers := l.logsToTransfers(logs, header.Time)
+
+    feeParams, err := l.logsToFeeParams(logs, blockNum)
+    if err != nil {
+        return errors.Wrap(err, "failed to convert logs to fee params")
+    }
+
+    currentFeeParams, feeParamsBaseline, err := l.feeParamsBefore(ctx, blockNum)
+    if err != nil {
+        return errors.Wrap(err, "failed to get fee params")
+    }
+
+    if err := l.linkFees(ctx, transfers, append([]data.FeeParams{*currentFeeParams}, feeParams...), blockNum); err != nil {
+        return errors.Wrap(err, "failed to link fee transfers")
+    }
+    if feeParamsBaseline {
+        feeParams = append([]data.FeeParams{*currentFeeParams}, feeParams...)
+    }
+
+    var transactions []data.EthTransaction
+    if l.config.Enrichment().Enabled && len(transfers) > 0 {
+        if transactions, err = l.blockTransactions(ctx, blockNum, transfers); err != nil {
+            return errors.Wrap(err, "failed to get block transactions")
+        }
+    }
+
+    balanceChanges, err := l.logsToBalanceChanges(ctx, logs, blockNum)
+    if err != nil {
+        return errors.Wrap(err, "failed to convert logs to balance changes")
+    }
+
+    balanceBaselines, err := l.balanceBaselines(ctx, balanceChanges)
+    if err != nil {
+        return errors.Wrap(err, "failed to get balance baselines")
+    }
+
+    blacklistEvents, err := l.logsToBlacklistEvents(logs, blockNum)
+    if err != nil {
+        return errors.Wrap(err, "failed to convert logs to blacklist events")
+    }
+
+    supplyChanges, err := l.logsToSupplyChanges(logs, blockNum, header.Time)
+    if err != nil {
+        return errors.Wrap(err, "failed to convert logs to supply changes")
+    }
+
+    supplyBaseline, err := l.supplyBaseline(ctx, blockNum)
+    if err != nil {
+        return errors.Wrap(err, "failed to get supply baseline")
+    }
+
+    onchainSupply, err := l.onchainSupply(ctx, blockNum)
+    if err != nil {
+        return errors.Wrap(err, "failed to get on-chain supply")
+    }
+
+    events := make([]broadcaster.Event, 0, len(transfers))
+    var supplyCheckpoint *data.SupplyCheckpoint
+
+    err = db.Transaction(func(q data.MasterQ) error {
+        for _, upgrade := range upgrades {
+            if err := q.USDTContract().Upgrade(upgrade); err != nil {
+                return errors.Wrap(err, "failed to record USDT contract upgrade")
+            }
+        }
+
+        if err := q.FeeParams().InsertBatch(feeParams); err != nil {
+            return errors.Wrap(err, "failed to insert fee params")
         }
 
         // Insert transfers into the database
         for _, transfer := range transfers {
-            _, err := q.USDTTransfer().Insert(transfer)
-            if err != nil {
+            if _, err := q.USDTTransfer().Insert(transfer); err != nil {
                 return errors.Wrap(err, "failed to insert transfer")
             }
         }
 
+        if err := q.EthTransaction().InsertBatch(transactions); err != nil {
+            return errors.Wrap(err, "failed to insert transactions")
+        }
+
+        // Flags are set before anything derived from the transfers reads them
+        if err := q.BlacklistEvent().InsertBatch(blacklistEvents); err != nil {
+            return errors.Wrap(err, "failed to insert blacklist events")
+        }
+        if err := q.Screening().ScreenBlock(blockNum); err != nil {
+            return errors.Wrap(err, "failed to screen transfers")
+        }
+
+        screened, err := q.USDTTransfer().FilterByBlockNumber(blockNum).OrderByCursor().Select()
+        if err != nil {
+            return errors.Wrap(err, "failed to select screened transfers")
+        }
+        for _, transfer := range screened {
+            events = append(events, broadcaster.Event{Type: broadcaster.EventTransfer, Transfer: transfer})
+        }
+
+        if err := q.WebhookDelivery().EnqueueBlock(blockNum, string(broadcaster.EventTransfer)); err != nil {
+            return errors.Wrap(err, "failed to enqueue webhook deliveries")
+        }
+
+        if err := q.TransferStats().ApplyBlock(blockNum); err != nil {
+            return errors.Wrap(err, "failed to update transfer stats")
+        }
+        // buckets closed before the last final block can't be reverted anymore
+        if blockNum > data.FinalityDepth {
+            final, err := q.Block().FilterByNumber(blockNum - data.FinalityDepth).Get()
+            if err != nil {
+                return errors.Wrap(err, "failed to get final block")
+            }
+            if final != nil {
+                if err := q.TransferStats().Prune(final.Timestamp); err != nil {
+                    return errors.Wrap(err, "failed to prune transfer stats participants")
+                }
+            }
+        }
+        if err := q.Alert().EvaluateBlock(blockNum); err != nil {
+            return errors.Wrap(err, "failed to evaluate alert rules")
+        }
+
+        if err := q.BalanceChange().InsertBatch(balanceBaselines); err != nil {
+            return errors.Wrap(err, "failed to insert balance baselines")
+        }
+        if err := q.Balance().Seed(balanceBaselines); err != nil {
+            return errors.Wrap(err, "failed to seed balances")
+        }
+        if err := q.BalanceChange().InsertBatch(balanceChanges); err != nil {
+            return errors.Wrap(err, "failed to insert balance changes")
+        }
+        if err := q.Balance().ApplyBlock(blockNum); err != nil {
+            return errors.Wrap(err, "failed to apply balance changes")
+        }
+
+        if interval := l.config.Checkpoints().Interval; blockNum%interval == 0 {
+            if err := q.BalanceCheckpoint().Snapshot(blockNum, blockNum-interval); err != nil {
+                return errors.Wrap(err, "failed to snapshot balances")
+            }
+        }
+
+        if supplyBaseline != nil {
+            if err := q.Supply().Insert(*supplyBaseline); err != nil {
+                return errors.Wrap(err, "failed to insert supply baseline")
+            }
+        }
+        if err := q.SupplyChange().InsertBatch(supplyChanges); err != nil {
+            return errors.Wrap(err, "failed to insert supply changes")
+        }
+        if err := q.Supply().ApplyBlock(blockNum); err != nil {
+            return errors.Wrap(err, "failed to apply supply changes")
+        }
+        if onchainSupply != nil {
+            var err error
+            supplyCheckpoint, err = q.SupplyCheckpoint().Insert(blockNum, onchainSupply.String())
+            if err != nil {
+                return errors.Wrap(err, "failed to insert supply checkpoint")
+            }
+        }
+
+        err = q.Block().Insert(data.Block{
+            Number:     blockNum,
+            Hash:       header.Hash().Hex(),
+            ParentHash: header.ParentHash.Hex(),
+            Timestamp:  time.Unix(int64(header.Time), 0),
+        })
+        if err != nil {
+            return errors.Wrap(err, "failed to insert block")
+        }
+
         // Update the last processed block
         if err := q.LastProcessedBlock().Update(blockNum); err != nil {
             return errors.Wrap(err, "failed to update last processed block")
@@ -161,18 +496,144 @@ func (l *Listener) processBlock(ctx context.Context, blockNum uint64) error {
 
         return nil
     })
+    if err != nil {
+        return err
+    }
+
+    for _, upgrade := range upgrades {
+        l.log.WithFields(logan.F{
+            "blockNumber": blockNum,
+            "deprecated":  *upgrade.Predecessor,
+            "upgraded":    upgrade.Address,
+        }).Warn("USDT contract deprecated, indexing the upgraded contract")
+    }
+    l.contracts = append(l.contracts, upgrades...)
+
+    if supplyCheckpoint != nil && !supplyCheckpoint.Matches() {
+        l.log.WithFields(logan.F{
+            "blockNumber":   blockNum,
+            "indexedSupply": supplyCheckpoint.IndexedSupply,
+            "onchainSupply": supplyCheckpoint.OnchainSupply,
+        }).Warn("Indexed total supply does not match the contract")
+    }
+
+    l.events.Publish(events...)
+    for _, observer := range l.observers {
+        observer.BlockCommitted(blockNum)
+    }
+
+    return nil
+}
+
+// rollbackBlock reverts everything derived from an orphaned block and moves
+// the checkpoint one block back
+func (l *Listener) rollbackBlock(ctx context.Context, blockNum uint64) error {
+    var retracted []data.USDTTransfer
+
+    err := l.db.WithContext(ctx).Transaction(func(q data.MasterQ) error {
+        var err error
+        retracted, err = q.USDTTransfer().FilterByBlockNumber(blockNum).OrderByCursor().Select()
+        if err != nil {
+            return errors.Wrap(err, "failed to select transfers")
+        }
+
+        // Stats, alerts and retractions are derived from the transfers, so they go first
+        if err := q.WebhookDelivery().EnqueueBlock(blockNum, string(broadcaster.EventRetraction)); err != nil {
+            return errors.Wrap(err, "failed to enqueue webhook retractions")
+        }
+        if err := q.PublisherRetraction().EnqueueBlock(blockNum); err != nil {
+            return errors.Wrap(err, "failed to enqueue publisher retractions")
+        }
+        if err := q.TransferStats().RevertBlock(blockNum); err != nil {
+            return errors.Wrap(err, "failed to revert transfer stats")
+        }
+        if err := q.Alert().DeleteByBlockNumber(blockNum); err != nil {
+            return errors.Wrap(err, "failed to delete alerts")
+        }
+        if err := q.USDTTransfer().DeleteLastProcessedBlock(blockNum); err != nil {
+            return errors.Wrap(err, "failed to delete transfers")
+        }
+
+        if err := q.Balance().RevertBlock(blockNum); err != nil {
+            return errors.Wrap(err, "failed to revert balances")
+        }
+        if err := q.BalanceChange().DeleteByBlockNumber(blockNum); err != nil {
+            return errors.Wrap(err, "failed to delete balance changes")
+        }
+        if err := q.BalanceCheckpoint().DeleteByBlockNumber(blockNum); err != nil {
+            return errors.Wrap(err, "failed to delete balance checkpoints")
+        }
+        if err := q.BlacklistEvent().DeleteByBlockNumber(blockNum); err != nil {
+            return errors.Wrap(err, "failed to delete blacklist events")
+        }
+        if err := q.EthTransaction().DeleteByBlockNumber(blockNum); err != nil {
+            return errors.Wrap(err, "failed to delete transactions")
+        }
+        if err := q.USDTContract().RevertBlock(blockNum); err != nil {
+            return errors.Wrap(err, "failed to revert USDT contract upgrades")
+        }
+        if err := q.FeeParams().DeleteByBlockNumber(blockNum); err != nil {
+            return errors.Wrap(err, "failed to delete fee params")
+        }
+        if err := q.SupplyCheckpoint().DeleteByBlockNumber(blockNum); err != nil {
+            return errors.Wrap(err, "failed to delete supply checkpoint")
+        }
+        if err := q.Supply().DeleteByBlockNumber(blockNum); err != nil {
+            return errors.Wrap(err, "failed to delete supply point")
+        }
+        if err := q.SupplyChange().DeleteByBlockNumber(blockNum); err != nil {
+            return errors.Wrap(err, "failed to delete supply changes")
+        }
+
+        if err := q.Block().Delete(blockNum); err != nil {
+            return errors.Wrap(err, "failed to delete block")
+        }
+        if err := q.PublisherPosition().Rewind(blockNum); err != nil {
+            return errors.Wrap(err, "failed to rewind publisher positions")
+        }
+        if err := q.LastProcessedBlock().Update(blockNum - 1); err != nil {
+            return errors.Wrap(err, "failed to update last processed block")
+        }
+
+        return nil
+    })
+    if err != nil {
+        return err
+    }
+
+    if err := l.loadContracts(); err != nil {
+        l.log.WithError(err).Error("Failed to reload USDT contracts")
+    }
+
+    events := make([]broadcaster.Event, 0, len(retracted))
+    for _, transfer := range retracted {
+        events = append(events, broadcaster.Event{Type: broadcaster.EventRetraction, Transfer: transfer})
+    }
+    l.events.Publish(events...)
+    for _, observer := range l.observers {
+        observer.BlockReverted(blockNum, retracted)
+    }
+
+    return nil
 }
 
-// getBlockLogs retrieves logs for a specific block
-func (l *Listener) getBlockLogs(ctx context.Context, blockNum uint64) ([]types.Log, error) {
-    contractAddress := common.HexToAddress(USDTContractAddress)
+// getBlockLogs retrieves logs of the contracts for a specific block
+func (l *Listener) getBlockLogs(ctx context.Context, blockHash common.Hash, addresses []common.Address) (logs []types.Log, err error) {
+    ctx, span := tracing.Start(ctx, "getBlockLogs",
+        attribute.String("block.hash", blockHash.Hex()),
+        attribute.Int("addresses", len(addresses)),
+    )
+    defer func() {
+        span.SetAttributes(attribute.Int("logs", len(logs)))
+        tracing.End(span, err)
+    }()
+
     query := ethereum.FilterQuery{
-        FromBlock: big.NewInt(int64(blockNum)),
-        ToBlock:   big.NewInt(int64(blockNum)),
-        Addresses: []common.Address{contractAddress},
+        BlockHash: &blockHash,
+        Addresses: addresses,
     }
 
-    logs, err := l.client.FilterLogs(ctx, query)
+    logs, err = l.client.FilterLogs(ctx, query)
     if err != nil {
         return nil, errors.Wrap(err, "failed to filter logs")
     }
@@ -181,16 +642,15 @@ func (l *Listener) getBlockLogs(ctx context.Context, blockNum uint64) ([]types.L
 }
 
 // logsToTransfers converts Ethereum logs to USDT transfers
-func (l *Listener) logsToTransfers(ctx context.Context, logs []types.Log, blockNum uint64) ([]data.USDTTransfer, error) {
+func (l *Listener) logsToTransfers(logs []types.Log, blockTime uint64) []data.USDTTransfer {
     transfers := make([]data.USDTTransfer, 0, len(logs))
 
-    block, err := l.client.BlockByNumber(ctx, big.NewInt(int64(blockNum)))
-    if err != nil {
-        return nil, errors.Wrap(err, "failed to get block")
-    }
-
     for _, log := range logs {
-        transfer, err := l.logToTransfer(log, block.Time())
+        if !isEvent(log, transferEventTopic) {
+            continue
+        }
+
+        transfer, err := l.logToTransfer(log, blockTime)
         if err != nil {
             l.log.WithError(err).WithField("txHash", log.TxHash.Hex()).Error("Failed to convert log to transfer")
             continue
@@ -198,7 +658,7 @@ func (l *Listener) logsToTransfers(ctx context.Context, logs []types.Log, blockN
         transfers = append(transfers, transfer)
     }
 
-    return transfers, nil
+    return transfers
 }
 
 // logToTransfer converts a single Ethereum log to a USDT transfer
@@ -219,6 +679,7 @@ func (l *Listener) logToTransfer(log types.Log, blockTime uint64) (data.USDTTran
         BlockNumber:     log.BlockNumber,
         LogIndex:        uint64(log.Index),
         Timestamp:       time.Unix(int64(blockTime), 0),
+        Fee:             "0",
     }, nil
 }
 
@@ -0,0 +1,63 @@
+package listener
+
+import (
+	"context"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// rewind rolls back every block from the checkpoint down to the first block
+// of the job, the listener then processes them again. Balances, supply and
+// stats are cumulative, so blocks after the range are re-ingested as well.
+// The progress is stored after every block, so an interrupted job resumes
+// where it stopped.
+func (l *Listener) rewind(ctx context.Context, job data.ReingestionJob) error {
+    lastProcessedBlock, err := l.db.LastProcessedBlock().Get()
+    if err != nil {
+        return errors.Wrap(err, "failed to get last processed block")
+    }
+
+    l.log.WithFields(logan.F{
+        "jobID":              job.ID,
+        "fromBlock":          job.FromBlock,
+        "toBlock":            job.ToBlock,
+        "lastProcessedBlock": lastProcessedBlock,
+    }).Warn("Rolling back blocks for re-ingestion")
+
+    // participants of closed buckets are pruned, reverting needs them back
+    if err := l.db.TransferStats().Restore(job.FromBlock); err != nil {
+        return errors.Wrap(err, "failed to restore transfer stats participants")
+    }
+
+    for blockNum := lastProcessedBlock; blockNum >= job.FromBlock; blockNum-- {
+        if err := ctx.Err(); err != nil {
+            return err
+        }
+        if err := l.rollbackBlock(ctx, blockNum); err != nil {
+            return errors.Wrap(err, "failed to roll back block", logan.F{"blockNumber": blockNum})
+        }
+        l.status.SetLastProcessedBlock(blockNum - 1)
+
+        err := l.db.ReingestionJob().UpdateProgress(job.ID, data.ReingestionStatusRollingBack, blockNum)
+        if err != nil {
+            return errors.Wrap(err, "failed to update re-ingestion job progress")
+        }
+    }
+
+    err = l.db.ReingestionJob().UpdateProgress(job.ID, data.ReingestionStatusReprocessing, job.FromBlock-1)
+    return errors.Wrap(err, "failed to update re-ingestion job progress")
+}
+
+// reingestionProgress records a block processed again by the job, the job
+// is done once the whole range is processed
+func (l *Listener) reingestionProgress(job data.ReingestionJob, blockNum uint64) error {
+    status := data.ReingestionStatusReprocessing
+    if blockNum >= job.ToBlock {
+        status = data.ReingestionStatusDone
+        l.log.WithField("jobID", job.ID).Info("Re-ingestion job done")
+    }
+
+    return l.db.ReingestionJob().UpdateProgress(job.ID, status, blockNum)
+}
@@ -0,0 +1,305 @@
+package listener
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/broadcaster"
+	"gitlab.com/distributed_lab/logan/v3"
+)
+
+// steps records the queries of a rollback in order, inTx marks the ones run
+// within the transaction
+type steps struct {
+	names []string
+	inTx  map[string]bool
+	tx    bool
+}
+
+func (s *steps) add(name string) {
+	s.names = append(s.names, name)
+	s.inTx[name] = s.tx
+}
+
+func (s *steps) index(name string) int {
+	for i, n := range s.names {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// rollbackQ serves the queries of rollbackBlock from memory
+type rollbackQ struct {
+	data.MasterQ
+	steps     *steps
+	transfers []data.USDTTransfer
+}
+
+func (q rollbackQ) WithContext(ctx context.Context) data.MasterQ { return q }
+
+func (q rollbackQ) Transaction(fn func(db data.MasterQ) error) error {
+	q.steps.tx = true
+	defer func() { q.steps.tx = false }()
+	return fn(q)
+}
+
+func (q rollbackQ) USDTTransfer() data.USDTTransferQ {
+	return transferQ{steps: q.steps, transfers: q.transfers}
+}
+func (q rollbackQ) WebhookDelivery() data.WebhookDeliveryQ { return deliveryQ{steps: q.steps} }
+func (q rollbackQ) TransferStats() data.TransferStatsQ     { return statsQ{steps: q.steps} }
+func (q rollbackQ) Alert() data.AlertQ                     { return alertQ{steps: q.steps} }
+func (q rollbackQ) Balance() data.BalanceQ                 { return balanceQ{steps: q.steps} }
+func (q rollbackQ) BalanceChange() data.BalanceChangeQ     { return balanceChangeQ{steps: q.steps} }
+func (q rollbackQ) BalanceCheckpoint() data.BalanceCheckpointQ {
+	return balanceCheckpointQ{steps: q.steps}
+}
+func (q rollbackQ) BlacklistEvent() data.BlacklistEventQ { return blacklistQ{steps: q.steps} }
+func (q rollbackQ) EthTransaction() data.EthTransactionQ { return ethTransactionQ{steps: q.steps} }
+func (q rollbackQ) USDTContract() data.USDTContractQ     { return contractQ{steps: q.steps} }
+func (q rollbackQ) FeeParams() data.FeeParamsQ           { return feeParamsQ{steps: q.steps} }
+func (q rollbackQ) SupplyCheckpoint() data.SupplyCheckpointQ {
+	return supplyCheckpointQ{steps: q.steps}
+}
+func (q rollbackQ) Supply() data.SupplyQ             { return supplyQ{steps: q.steps} }
+func (q rollbackQ) SupplyChange() data.SupplyChangeQ { return supplyChangeQ{steps: q.steps} }
+func (q rollbackQ) Block() data.BlockQ               { return blockQ{steps: q.steps} }
+func (q rollbackQ) PublisherPosition() data.PublisherPositionQ {
+	return positionQ{steps: q.steps}
+}
+func (q rollbackQ) PublisherRetraction() data.PublisherRetractionQ {
+	return retractionQ{steps: q.steps}
+}
+func (q rollbackQ) LastProcessedBlock() data.LastProcessedBlockQ {
+	return checkpointQ{steps: q.steps}
+}
+
+type transferQ struct {
+	data.USDTTransferQ
+	steps     *steps
+	transfers []data.USDTTransfer
+}
+
+func (q transferQ) FilterByBlockNumber(uint64) data.USDTTransferQ { return q }
+func (q transferQ) OrderByCursor() data.USDTTransferQ             { return q }
+func (q transferQ) Select() ([]data.USDTTransfer, error) {
+	q.steps.add("select transfers")
+	return q.transfers, nil
+}
+func (q transferQ) DeleteLastProcessedBlock(uint64) error {
+	q.steps.add("delete transfers")
+	return nil
+}
+
+type deliveryQ struct {
+	data.WebhookDeliveryQ
+	steps *steps
+}
+
+func (q deliveryQ) EnqueueBlock(_ uint64, eventType string) error {
+	q.steps.add("enqueue " + eventType)
+	return nil
+}
+
+type statsQ struct {
+	data.TransferStatsQ
+	steps *steps
+}
+
+func (q statsQ) RevertBlock(uint64) error { q.steps.add("revert stats"); return nil }
+
+type alertQ struct {
+	data.AlertQ
+	steps *steps
+}
+
+func (q alertQ) DeleteByBlockNumber(uint64) error { q.steps.add("delete alerts"); return nil }
+
+type balanceQ struct {
+	data.BalanceQ
+	steps *steps
+}
+
+func (q balanceQ) RevertBlock(uint64) error { q.steps.add("revert balances"); return nil }
+
+type balanceChangeQ struct {
+	data.BalanceChangeQ
+	steps *steps
+}
+
+func (q balanceChangeQ) DeleteByBlockNumber(uint64) error {
+	q.steps.add("delete balance changes")
+	return nil
+}
+
+type balanceCheckpointQ struct {
+	data.BalanceCheckpointQ
+	steps *steps
+}
+
+func (q balanceCheckpointQ) DeleteByBlockNumber(uint64) error {
+	q.steps.add("delete balance checkpoints")
+	return nil
+}
+
+type blacklistQ struct {
+	data.BlacklistEventQ
+	steps *steps
+}
+
+func (q blacklistQ) DeleteByBlockNumber(uint64) error {
+	q.steps.add("delete blacklist events")
+	return nil
+}
+
+type ethTransactionQ struct {
+	data.EthTransactionQ
+	steps *steps
+}
+
+func (q ethTransactionQ) DeleteByBlockNumber(uint64) error {
+	q.steps.add("delete transactions")
+	return nil
+}
+
+type contractQ struct {
+	data.USDTContractQ
+	steps *steps
+}
+
+func (q contractQ) RevertBlock(uint64) error { q.steps.add("revert contracts"); return nil }
+func (q contractQ) Select() ([]data.USDTContract, error) {
+	return []data.USDTContract{{Address: USDTContractAddress}}, nil
+}
+
+type feeParamsQ struct {
+	data.FeeParamsQ
+	steps *steps
+}
+
+func (q feeParamsQ) DeleteByBlockNumber(uint64) error { q.steps.add("delete fee params"); return nil }
+
+type supplyCheckpointQ struct {
+	data.SupplyCheckpointQ
+	steps *steps
+}
+
+func (q supplyCheckpointQ) DeleteByBlockNumber(uint64) error {
+	q.steps.add("delete supply checkpoint")
+	return nil
+}
+
+type supplyQ struct {
+	data.SupplyQ
+	steps *steps
+}
+
+func (q supplyQ) DeleteByBlockNumber(uint64) error { q.steps.add("delete supply"); return nil }
+
+type supplyChangeQ struct {
+	data.SupplyChangeQ
+	steps *steps
+}
+
+func (q supplyChangeQ) DeleteByBlockNumber(uint64) error {
+	q.steps.add("delete supply changes")
+	return nil
+}
+
+type blockQ struct {
+	data.BlockQ
+	steps *steps
+}
+
+func (q blockQ) Delete(uint64) error { q.steps.add("delete block"); return nil }
+
+type positionQ struct {
+	data.PublisherPositionQ
+	steps *steps
+}
+
+func (q positionQ) Rewind(uint64) error { q.steps.add("rewind positions"); return nil }
+
+type retractionQ struct {
+	data.PublisherRetractionQ
+	steps *steps
+}
+
+func (q retractionQ) EnqueueBlock(uint64) error {
+	q.steps.add("enqueue publisher retractions")
+	return nil
+}
+
+type checkpointQ struct {
+	data.LastProcessedBlockQ
+	steps *steps
+}
+
+func (q checkpointQ) Update(blockNumber uint64) error {
+	q.steps.add("update checkpoint")
+	return nil
+}
+
+type revertObserver struct {
+	reverted []data.USDTTransfer
+}
+
+func (o *revertObserver) BlockCommitted(uint64) {}
+
+func (o *revertObserver) BlockReverted(_ uint64, transfers []data.USDTTransfer) {
+	o.reverted = append(o.reverted, transfers...)
+}
+
+func TestRollbackBlockEnqueuesRetractions(t *testing.T) {
+	transfers := []data.USDTTransfer{
+		{ID: 1, BlockNumber: 100, LogIndex: 3},
+		{ID: 2, BlockNumber: 100, LogIndex: 5},
+	}
+	recorded := &steps{inTx: make(map[string]bool)}
+	events := broadcaster.New(10)
+	sub := events.Subscribe()
+	observer := &revertObserver{}
+
+	l := &Listener{
+		db:     rollbackQ{steps: recorded, transfers: transfers},
+		log:    logan.New(),
+		events: events,
+	}
+	l.AddObserver(observer)
+
+	if err := l.rollbackBlock(context.Background(), 100); err != nil {
+		t.Fatalf("rollback failed: %v", err)
+	}
+
+	enqueued := recorded.index("enqueue " + string(broadcaster.EventRetraction))
+	if enqueued == -1 {
+		t.Fatalf("retractions not enqueued, steps: %v", recorded.names)
+	}
+	if !recorded.inTx["enqueue "+string(broadcaster.EventRetraction)] {
+		t.Error("retractions enqueued outside of the rollback transaction")
+	}
+	// the outbox entries are built from the transfers of the block
+	if deleted := recorded.index("delete transfers"); deleted == -1 || deleted < enqueued {
+		t.Errorf("transfers deleted before retractions were enqueued, steps: %v", recorded.names)
+	}
+	// the outbox holds what was published before the positions move back
+	if retracted, rewound := recorded.index("enqueue publisher retractions"), recorded.index("rewind positions"); retracted == -1 || retracted > rewound || retracted > recorded.index("delete transfers") {
+		t.Errorf("publisher retractions not enqueued before the rewind, steps: %v", recorded.names)
+	}
+	if recorded.index("enqueue "+string(broadcaster.EventTransfer)) != -1 {
+		t.Error("transfer deliveries enqueued on rollback")
+	}
+
+	for _, transfer := range transfers {
+		event := <-sub.Events()
+		if event.Type != broadcaster.EventRetraction || event.Transfer.ID != transfer.ID {
+			t.Errorf("unexpected event %s of transfer %d, want retraction of %d", event.Type, event.Transfer.ID, transfer.ID)
+		}
+	}
+	if len(observer.reverted) != len(transfers) {
+		t.Errorf("observer got %d reverted transfers, want %d", len(observer.reverted), len(transfers))
+	}
+}
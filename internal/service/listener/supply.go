@@ -0,0 +1,97 @@
+package listener
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// logsToSupplyChanges extracts the events changing the total supply.
+// Issue mints, Redeem and DestroyedBlackFunds burn.
+func (l *Listener) logsToSupplyChanges(logs []types.Log, blockNum uint64, blockTime uint64) ([]data.SupplyChange, error) {
+	var changes []data.SupplyChange
+
+	for _, log := range logs {
+		change := func(kind string, amount *big.Int) {
+			changes = append(changes, data.SupplyChange{
+				BlockNumber: blockNum,
+				LogIndex:    uint64(log.Index),
+				Kind:        kind,
+				Amount:      amount.String(),
+				Timestamp:   time.Unix(int64(blockTime), 0),
+			})
+		}
+
+		switch {
+		case isEvent(log, issueEventTopic):
+			event, err := l.usdt.ParseIssue(log)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to parse Issue event")
+			}
+			change(data.SupplyChangeIssue, event.Amount)
+		case isEvent(log, redeemEventTopic):
+			event, err := l.usdt.ParseRedeem(log)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to parse Redeem event")
+			}
+			change(data.SupplyChangeRedeem, new(big.Int).Neg(event.Amount))
+		case isEvent(log, destroyedBlackFundsEventTopic):
+			event, err := l.usdt.ParseDestroyedBlackFunds(log)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to parse DestroyedBlackFunds event")
+			}
+			change(data.SupplyChangeDestroyedBlackFunds, new(big.Int).Neg(event.Balance))
+		}
+	}
+
+	return changes, nil
+}
+
+// supplyBaseline returns the on-chain total supply right before the block
+// when the supply history is still empty, nil otherwise
+func (l *Listener) supplyBaseline(ctx context.Context, blockNum uint64) (*data.SupplyPoint, error) {
+	latest, err := l.db.WithContext(ctx).Supply().Latest()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get latest supply point")
+	}
+	if latest != nil {
+		return nil, nil
+	}
+
+	parent := new(big.Int).SetUint64(blockNum - 1)
+
+	header, err := l.client.HeaderByNumber(ctx, parent)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get parent block header")
+	}
+	totalSupply, err := l.usdt.TotalSupply(&bind.CallOpts{Context: ctx, BlockNumber: parent})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get total supply")
+	}
+
+	return &data.SupplyPoint{
+		BlockNumber: blockNum - 1,
+		Timestamp:   time.Unix(int64(header.Time), 0),
+		Delta:       "0",
+		TotalSupply: totalSupply.String(),
+	}, nil
+}
+
+// onchainSupply returns the contract total supply at checkpoint blocks and
+// nil for the rest
+func (l *Listener) onchainSupply(ctx context.Context, blockNum uint64) (*big.Int, error) {
+	if blockNum%l.config.Checkpoints().Interval != 0 {
+		return nil, nil
+	}
+
+	totalSupply, err := l.usdt.TotalSupply(&bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(blockNum)})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get total supply")
+	}
+	return totalSupply, nil
+}
@@ -0,0 +1,72 @@
+package listener
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/tracing"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"gitlab.com/distributed_lab/logan/v3"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// logsChain returns the logs and keeps the span of the RPC call
+type logsChain struct {
+	Chain
+	logs []types.Log
+	span trace.SpanContext
+}
+
+func (c *logsChain) FilterLogs(ctx context.Context, _ ethereum.FilterQuery) ([]types.Log, error) {
+	c.span = trace.SpanContextFromContext(ctx)
+	return c.logs, nil
+}
+
+func TestGetBlockLogsIsChildSpan(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		provider.Shutdown(context.Background())
+	})
+
+	chain := &logsChain{logs: []types.Log{{Index: 1}, {Index: 2}}}
+	l := &Listener{client: chain, log: logan.New()}
+
+	ctx, span := tracing.Start(context.Background(), "processBlock")
+	if _, err := l.getBlockLogs(ctx, common.HexToHash("0x1"), []common.Address{common.HexToAddress(USDTContractAddress)}); err != nil {
+		t.Fatalf("failed to get logs: %v", err)
+	}
+	span.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	logs, root := spans[0], spans[1]
+	if logs.Name != "getBlockLogs" || root.Name != "processBlock" {
+		t.Fatalf("got spans %q and %q, want getBlockLogs and processBlock", logs.Name, root.Name)
+	}
+	if logs.Parent.SpanID() != root.SpanContext.SpanID() {
+		t.Error("getBlockLogs span is not a child of processBlock")
+	}
+	// the RPC call runs within the getBlockLogs span
+	if chain.span.SpanID() != logs.SpanContext.SpanID() {
+		t.Error("logs are not filtered within the getBlockLogs span")
+	}
+
+	attributes := make(map[string]int64)
+	for _, attr := range logs.Attributes {
+		attributes[string(attr.Key)] = attr.Value.AsInt64()
+	}
+	if attributes["logs"] != 2 || attributes["addresses"] != 1 {
+		t.Errorf("getBlockLogs span has attributes %v, want 2 logs of 1 address", attributes)
+	}
+}
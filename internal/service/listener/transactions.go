@@ -0,0 +1,116 @@
+package listener
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// rpcTransaction is a transaction returned by eth_getTransactionByHash
+// together with its sender
+type rpcTransaction struct {
+	tx   *types.Transaction
+	from common.Address
+}
+
+func (t *rpcTransaction) UnmarshalJSON(msg []byte) error {
+	if err := json.Unmarshal(msg, &t.tx); err != nil {
+		return err
+	}
+	extra := struct {
+		From *common.Address `json:"from"`
+	}{From: &t.from}
+	return json.Unmarshal(msg, &extra)
+}
+
+// blockTransactions fetches the transactions of the transfers and their
+// receipts in RPC batches of the configured size
+func (l *Listener) blockTransactions(ctx context.Context, blockNum uint64, transfers []data.USDTTransfer) ([]data.EthTransaction, error) {
+	hashes := make([]common.Hash, 0, len(transfers))
+	seen := make(map[string]bool, len(transfers))
+	for _, transfer := range transfers {
+		if !seen[transfer.TransactionHash] {
+			seen[transfer.TransactionHash] = true
+			hashes = append(hashes, common.HexToHash(transfer.TransactionHash))
+		}
+	}
+
+	txs := make([]*rpcTransaction, len(hashes))
+	receipts := make([]*types.Receipt, len(hashes))
+	batchSize := l.config.Enrichment().BatchSize
+
+	for start := 0; start < len(hashes); start += batchSize {
+		batch := make([]rpc.BatchElem, 0, 2*batchSize)
+		for i := start; i < len(hashes) && i < start+batchSize; i++ {
+			batch = append(batch,
+				rpc.BatchElem{
+					Method: "eth_getTransactionByHash",
+					Args:   []interface{}{hashes[i]},
+					Result: &txs[i],
+				},
+				rpc.BatchElem{
+					Method: "eth_getTransactionReceipt",
+					Args:   []interface{}{hashes[i]},
+					Result: &receipts[i],
+				},
+			)
+		}
+
+		if err := l.client.BatchCallContext(ctx, batch); err != nil {
+			return nil, errors.Wrap(err, "failed to get transactions")
+		}
+		for _, elem := range batch {
+			if elem.Error != nil {
+				return nil, errors.Wrap(elem.Error, "failed to call "+elem.Method, logan.F{"txHash": elem.Args[0]})
+			}
+		}
+	}
+
+	transactions := make([]data.EthTransaction, 0, len(hashes))
+	for i, hash := range hashes {
+		if txs[i] == nil || receipts[i] == nil {
+			return nil, errors.From(errors.New("transaction not found"), logan.F{"txHash": hash.Hex()})
+		}
+		transactions = append(transactions, toEthTransaction(txs[i], receipts[i], blockNum))
+	}
+
+	return transactions, nil
+}
+
+func toEthTransaction(rpcTx *rpcTransaction, receipt *types.Receipt, blockNum uint64) data.EthTransaction {
+	tx := rpcTx.tx
+
+	gasPrice := receipt.EffectiveGasPrice
+	if gasPrice == nil {
+		gasPrice = tx.GasPrice()
+	}
+	gasFee := new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(receipt.GasUsed))
+
+	transaction := data.EthTransaction{
+		Hash:              tx.Hash().Hex(),
+		BlockNumber:       blockNum,
+		FromAddress:       rpcTx.from.Hex(),
+		GasUsed:           receipt.GasUsed,
+		EffectiveGasPrice: gasPrice.String(),
+		GasFee:            gasFee.String(),
+		Status:            receipt.Status,
+	}
+	if to := tx.To(); to != nil {
+		address := to.Hex()
+		transaction.ToAddress = &address
+	}
+	if input := tx.Data(); len(input) >= 4 {
+		selector := hexutil.Encode(input[:4])
+		transaction.MethodSelector = &selector
+	}
+
+	return transaction
+}
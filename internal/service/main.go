@@ -7,7 +7,18 @@ import (
 
 	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/config"
 	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data/pg"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/metrics"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/alerts"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/archiver"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/broadcaster"
 	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/listener"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/partitioner"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/publisher"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/ratelimit"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/reconciler"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/status"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/webhooks"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/tracing"
 	"gitlab.com/distributed_lab/kit/copus/types"
 	"gitlab.com/distributed_lab/logan/v3"
 	"gitlab.com/distributed_lab/logan/v3/errors"
@@ -18,23 +29,66 @@ type service struct {
     copus    types.Copus
     listener net.Listener
     cfg      config.Config
+    events   *broadcaster.Broadcaster
+    status   *status.Status
+    limiter  *ratelimit.Limiter
 }
 
 func (s *service) run(cfg config.Config) error {
     s.log.Info("Service started")
-    r := s.router(cfg)
+    // client is used by the readiness check, the listener dials its own
+    client, err := metrics.DialEthClient(cfg.Ethereum().RPCURL)
+    if err != nil {
+        return errors.Wrap(err, "failed to connect to Ethereum client")
+    }
+
+    r := s.router(cfg, client)
 
     if err := s.copus.RegisterChi(r); err != nil {
         return errors.Wrap(err, "cop failed")
     }
 
+    // The relay is created up front, so a broken broker config fails the
+    // startup instead of leaving the listener stopped
+    var relay *publisher.Relay
+    if cfg.Publisher().Driver != config.PublisherDriverNone {
+        relay, err = s.newPublisherRelay()
+        if err != nil {
+            return errors.Wrap(err, "failed to create message broker publisher")
+        }
+    }
+
+    // The partitions of the next blocks are created before the listener
+    // starts, afterwards they are kept ahead of it in the background
+    transferPartitioner := partitioner.NewPartitioner(cfg, pg.NewMasterQ(cfg.DB()), s.log)
+    if err := transferPartitioner.Premake(); err != nil {
+        return errors.Wrap(err, "failed to create transfer partitions")
+    }
+    go s.runPartitioner(transferPartitioner)
+
     // Start the USDT listener
-    go s.runUSDTListener()
+    go s.runUSDTListener(relay)
+
+    if !cfg.Reconciliation().Disabled {
+        go s.runReconciler()
+    }
+
+    if !cfg.Webhooks().Disabled {
+        go s.runWebhookDispatcher()
+    }
+
+    if !cfg.Alerts().Disabled {
+        go s.runAlertDispatcher()
+    }
+
+    if cfg.Retention().Enabled() {
+        go s.runArchiver()
+    }
 
     return http.Serve(s.listener, r)
 }
 
-func (s *service) runUSDTListener() {
+func (s *service) runUSDTListener(relay *publisher.Relay) {
     db := pg.NewMasterQ(s.cfg.DB())
 
     ethereumConfig := s.cfg.Ethereum()
@@ -42,26 +96,103 @@ func (s *service) runUSDTListener() {
     
     startingBlock := ethereumConfig.StartingBlock
 
-    usdtListener, err := listener.NewListener(s.cfg, db, s.log)
+    usdtListener, err := listener.NewListener(s.cfg, db, s.log, s.events, s.status)
     if err != nil {
         s.log.WithError(err).Error("Failed to create USDT listener")
+        s.status.SetError(err)
         return
     }
 
+    if relay != nil {
+        usdtListener.AddObserver(relay)
+
+        go func() {
+            if err := relay.Run(context.Background()); err != nil {
+                s.log.WithError(err).Error("Message broker publisher stopped")
+            }
+        }()
+    }
+
     if err := usdtListener.Listen(context.Background(), true, startingBlock); err != nil {
         s.log.WithError(err).Error("USDT listener stopped")
+        s.status.SetError(err)
+    }
+}
+
+func (s *service) newPublisherRelay() (*publisher.Relay, error) {
+    brokerPublisher, err := publisher.New(s.cfg.Publisher())
+    if err != nil {
+        return nil, err
     }
+
+    return publisher.NewRelay(s.cfg.Publisher(), brokerPublisher, pg.NewMasterQ(s.cfg.DB()), s.log), nil
 }
+
+func (s *service) runReconciler() {
+    balanceReconciler, err := reconciler.NewReconciler(s.cfg, pg.NewMasterQ(s.cfg.DB()), s.log)
+    if err != nil {
+        s.log.WithError(err).Error("Failed to create balance reconciler")
+        return
+    }
+
+    if err := balanceReconciler.Run(context.Background()); err != nil {
+        s.log.WithError(err).Error("Balance reconciler stopped")
+    }
+}
+
+func (s *service) runWebhookDispatcher() {
+    dispatcher := webhooks.NewDispatcher(s.cfg.Webhooks(), pg.NewMasterQ(s.cfg.DB()), s.log)
+
+    if err := dispatcher.Run(context.Background()); err != nil {
+        s.log.WithError(err).Error("Webhook dispatcher stopped")
+    }
+}
+
+func (s *service) runAlertDispatcher() {
+    dispatcher := alerts.NewDispatcher(s.cfg.Alerts(), pg.NewMasterQ(s.cfg.DB()), s.log)
+
+    if err := dispatcher.Run(context.Background()); err != nil {
+        s.log.WithError(err).Error("Alert dispatcher stopped")
+    }
+}
+
+func (s *service) runPartitioner(transferPartitioner *partitioner.Partitioner) {
+    if err := transferPartitioner.Run(context.Background()); err != nil {
+        s.log.WithError(err).Error("Transfer partitioner stopped")
+    }
+}
+
+func (s *service) runArchiver() {
+    transferArchiver := archiver.NewArchiver(s.cfg, pg.NewMasterQ(s.cfg.DB()), s.log)
+
+    if err := transferArchiver.Run(context.Background()); err != nil {
+        s.log.WithError(err).Error("Transfer archiver stopped")
+    }
+}
+
 func newService(cfg config.Config) *service {
     return &service{
         log:      cfg.Log(),
         copus:    cfg.Copus(),
         listener: cfg.Listener(),
         cfg:      cfg,
+        events:   broadcaster.New(cfg.Streaming().BufferSize),
+        status:   status.New(cfg.Health().MaxLag),
+        limiter:  ratelimit.New(),
     }
 }
 
 func Run(cfg config.Config) {
+    shutdownTracing, err := tracing.Setup(cfg.Tracing())
+    if err != nil {
+        panic(errors.Wrap(err, "failed to set up tracing"))
+    }
+    defer func() {
+        if err := shutdownTracing(context.Background()); err != nil {
+            cfg.Log().WithError(err).Error("Failed to flush spans")
+        }
+    }()
+
     if err := newService(cfg).run(cfg); err != nil {
         panic(err)
     }
@@ -0,0 +1,105 @@
+package partitioner
+
+import (
+	"context"
+	"time"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/config"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// Partitioner creates the usdt_transfers partitions ahead of the listener,
+// so no partition DDL runs while a block is processed
+type Partitioner struct {
+	db     data.MasterQ
+	log    *logan.Entry
+	config config.Config
+}
+
+// NewPartitioner creates a new Partitioner instance
+func NewPartitioner(config config.Config, db data.MasterQ, log *logan.Entry) *Partitioner {
+	return &Partitioner{
+		db:     db,
+		log:    log.WithField("service", "partitioner"),
+		config: config,
+	}
+}
+
+// Run premakes the partitions every period until the context is cancelled
+func (p *Partitioner) Run(ctx context.Context) error {
+	ticker := time.NewTicker(p.config.Partitions().Period)
+	defer ticker.Stop()
+
+	for {
+		if err := p.Premake(); err != nil {
+			p.log.WithError(err).Error("Failed to create transfer partitions")
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Premake creates the missing partitions from the block after the last
+// processed one, or the starting block of an empty database, up to Premake
+// partitions ahead of it
+func (p *Partitioner) Premake() error {
+	lastProcessedBlock, err := p.db.LastProcessedBlock().Get()
+	if err != nil {
+		return errors.Wrap(err, "failed to get last processed block")
+	}
+
+	cfg := p.config.Partitions()
+	fromBlock := max(lastProcessedBlock+1, p.config.Ethereum().StartingBlock)
+	return p.Create(fromBlock, fromBlock+cfg.Premake*cfg.Blocks)
+}
+
+// Create creates the missing partitions holding the blocks from fromBlock
+// to toBlock, both included
+func (p *Partitioner) Create(fromBlock, toBlock uint64) error {
+	partitions, err := p.db.TransferPartition().Select()
+	if err != nil {
+		return err
+	}
+
+	size := p.config.Partitions().Blocks
+	for block := fromBlock; block <= toBlock; {
+		partitionFrom, partitionTo, exists := partitionOf(partitions, block, size)
+		if !exists {
+			if err := p.db.TransferPartition().Create(partitionFrom, partitionTo); err != nil {
+				return err
+			}
+			p.log.WithFields(logan.F{
+				"fromBlock": partitionFrom,
+				"toBlock":   partitionTo - 1,
+			}).Info("Transfer partition created")
+		}
+		block = partitionTo
+	}
+	return nil
+}
+
+// partitionOf returns the blocks of the partition holding the block, the
+// end excluded. A missing partition is aligned to the partition size and
+// fitted between the existing ones.
+func partitionOf(partitions []data.TransferPartition, blockNum, size uint64) (uint64, uint64, bool) {
+	fromBlock := blockNum / size * size
+	toBlock := fromBlock + size
+
+	for _, partition := range partitions {
+		switch {
+		case blockNum >= partition.FromBlock && blockNum < partition.ToBlock:
+			return partition.FromBlock, partition.ToBlock, true
+		case partition.ToBlock <= blockNum:
+			fromBlock = max(fromBlock, partition.ToBlock)
+		case partition.FromBlock > blockNum:
+			toBlock = min(toBlock, partition.FromBlock)
+		}
+	}
+	return fromBlock, toBlock, false
+}
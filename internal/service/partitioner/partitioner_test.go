@@ -0,0 +1,113 @@
+package partitioner
+
+import (
+	"testing"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/config"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"gitlab.com/distributed_lab/logan/v3"
+)
+
+type testConfig struct {
+	config.Config
+	partitions *config.Partitions
+	ethereum   *config.Ethereum
+}
+
+func (c testConfig) Partitions() *config.Partitions { return c.partitions }
+func (c testConfig) Ethereum() *config.Ethereum     { return c.ethereum }
+
+// partitionsQ keeps the partitions in memory
+type partitionsQ struct {
+	data.MasterQ
+	lastProcessedBlock uint64
+	partitions         *[]data.TransferPartition
+}
+
+func (q partitionsQ) TransferPartition() data.TransferPartitionQ { return partitionQ{q.partitions} }
+func (q partitionsQ) LastProcessedBlock() data.LastProcessedBlockQ {
+	return checkpointQ{blockNumber: q.lastProcessedBlock}
+}
+
+type partitionQ struct {
+	partitions *[]data.TransferPartition
+}
+
+func (q partitionQ) New() data.TransferPartitionQ        { return q }
+func (q partitionQ) Detach(data.TransferPartition) error { return nil }
+func (q partitionQ) Drop(string) error                   { return nil }
+func (q partitionQ) Select() ([]data.TransferPartition, error) {
+	return append([]data.TransferPartition(nil), *q.partitions...), nil
+}
+func (q partitionQ) Create(fromBlock, toBlock uint64) error {
+	*q.partitions = append(*q.partitions, data.TransferPartition{FromBlock: fromBlock, ToBlock: toBlock})
+	return nil
+}
+
+type checkpointQ struct {
+	data.LastProcessedBlockQ
+	blockNumber uint64
+}
+
+func (q checkpointQ) Get() (uint64, error) { return q.blockNumber, nil }
+
+func TestPremakeAfterAttachedPartition(t *testing.T) {
+	// the partition attached by the migration ends at a boundary of its own
+	partitions := []data.TransferPartition{{Name: "usdt_transfers_p0", FromBlock: 0, ToBlock: 2000000}}
+	p := NewPartitioner(testConfig{
+		partitions: &config.Partitions{Blocks: 1000000, Premake: 2},
+		ethereum:   &config.Ethereum{StartingBlock: 100},
+	}, partitionsQ{lastProcessedBlock: 1500000, partitions: &partitions}, logan.New())
+
+	if err := p.Premake(); err != nil {
+		t.Fatalf("failed to premake partitions: %v", err)
+	}
+
+	want := []data.TransferPartition{
+		{Name: "usdt_transfers_p0", FromBlock: 0, ToBlock: 2000000},
+		{FromBlock: 2000000, ToBlock: 3000000},
+		{FromBlock: 3000000, ToBlock: 4000000},
+	}
+	if len(partitions) != len(want) {
+		t.Fatalf("got partitions %v, want %v", partitions, want)
+	}
+	for i := range want {
+		if partitions[i] != want[i] {
+			t.Errorf("partition %d is %v, want %v", i, partitions[i], want[i])
+		}
+	}
+
+	// the partitions are there already
+	if err := p.Premake(); err != nil {
+		t.Fatalf("failed to premake partitions again: %v", err)
+	}
+	if len(partitions) != len(want) {
+		t.Errorf("got %d partitions after premaking again, want %d", len(partitions), len(want))
+	}
+}
+
+func TestPremakeEmptyDatabase(t *testing.T) {
+	var partitions []data.TransferPartition
+	p := NewPartitioner(testConfig{
+		partitions: &config.Partitions{Blocks: 1000000, Premake: 1},
+		ethereum:   &config.Ethereum{StartingBlock: 20400000},
+	}, partitionsQ{partitions: &partitions}, logan.New())
+
+	if err := p.Premake(); err != nil {
+		t.Fatalf("failed to premake partitions: %v", err)
+	}
+
+	// starts at the starting block, not at the empty checkpoint
+	want := []data.TransferPartition{
+		{FromBlock: 20000000, ToBlock: 21000000},
+		{FromBlock: 21000000, ToBlock: 22000000},
+	}
+	if len(partitions) != len(want) {
+		t.Fatalf("got partitions %v, want %v", partitions, want)
+	}
+	for i := range want {
+		if partitions[i] != want[i] {
+			t.Errorf("partition %d is %v, want %v", i, partitions[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package publisher
+
+import (
+	"context"
+
+	"github.com/segmentio/kafka-go"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// KafkaPublisher writes messages keyed by address, so all events of an
+// address land in the same partition and keep their order
+type KafkaPublisher struct {
+	writer *kafka.Writer
+}
+
+func NewKafkaPublisher(brokers []string, topic string) *KafkaPublisher {
+	return &KafkaPublisher{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(brokers...),
+			Topic:        topic,
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+		},
+	}
+}
+
+func (p *KafkaPublisher) Publish(ctx context.Context, messages []Message) error {
+	kafkaMessages := make([]kafka.Message, 0, len(messages))
+	for _, message := range messages {
+		kafkaMessages = append(kafkaMessages, kafka.Message{
+			Key:     []byte(message.Key),
+			Value:   message.Value,
+			Headers: []kafka.Header{{Key: "Message-Id", Value: []byte(message.ID)}},
+		})
+	}
+
+	err := p.writer.WriteMessages(ctx, kafkaMessages...)
+	return errors.Wrap(err, "failed to write kafka messages")
+}
+
+func (p *KafkaPublisher) Close() error {
+	return p.writer.Close()
+}
@@ -0,0 +1,57 @@
+package publisher
+
+import (
+	"context"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// NatsPublisher publishes every message to the "<subject>.<key>" subject of
+// a JetStream stream and waits for the stream to acknowledge it. Messages
+// carry the Nats-Msg-Id header, so the stream deduplicates redeliveries
+// within its duplicates window.
+type NatsPublisher struct {
+	conn    *nats.Conn
+	js      jetstream.JetStream
+	subject string
+}
+
+func NewNatsPublisher(url, subject string) (*NatsPublisher, error) {
+	conn, err := nats.Connect(url, nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to nats")
+	}
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, errors.Wrap(err, "failed to create jetstream context")
+	}
+
+	return &NatsPublisher{
+		conn:    conn,
+		js:      js,
+		subject: subject,
+	}, nil
+}
+
+// Publish returns once the stream stored every message of the batch, a
+// subject no stream captures fails with no responders
+func (p *NatsPublisher) Publish(ctx context.Context, messages []Message) error {
+	for _, message := range messages {
+		msg := nats.NewMsg(p.subject + "." + message.Key)
+		msg.Data = message.Value
+
+		if _, err := p.js.PublishMsg(ctx, msg, jetstream.WithMsgID(message.ID)); err != nil {
+			return errors.Wrap(err, "failed to publish nats message")
+		}
+	}
+
+	return nil
+}
+
+func (p *NatsPublisher) Close() error {
+	return p.conn.Drain()
+}
@@ -0,0 +1,125 @@
+package publisher
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+func runNatsServer(t *testing.T) *server.Server {
+	t.Helper()
+
+	srv, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      -1,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+		NoLog:     true,
+		NoSigs:    true,
+	})
+	if err != nil {
+		t.Fatalf("failed to create nats server: %v", err)
+	}
+	go srv.Start()
+	if !srv.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats server is not ready")
+	}
+	t.Cleanup(srv.Shutdown)
+	return srv
+}
+
+func createStream(t *testing.T, url string) jetstream.Stream {
+	t.Helper()
+
+	conn, err := nats.Connect(url)
+	if err != nil {
+		t.Fatalf("failed to connect to nats: %v", err)
+	}
+	t.Cleanup(conn.Close)
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		t.Fatalf("failed to create jetstream context: %v", err)
+	}
+	stream, err := js.CreateStream(context.Background(), jetstream.StreamConfig{
+		Name:       "TRANSFERS",
+		Subjects:   []string{"usdt.transfers.>"},
+		Duplicates: time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("failed to create stream: %v", err)
+	}
+	return stream
+}
+
+func streamMessages(t *testing.T, stream jetstream.Stream) uint64 {
+	t.Helper()
+
+	info, err := stream.Info(context.Background())
+	if err != nil {
+		t.Fatalf("failed to get stream info: %v", err)
+	}
+	return info.State.Msgs
+}
+
+func TestNatsPublisherStoresMessagesInStream(t *testing.T) {
+	srv := runNatsServer(t)
+	stream := createStream(t, srv.ClientURL())
+
+	publisher, err := NewNatsPublisher(srv.ClientURL(), "usdt.transfers")
+	if err != nil {
+		t.Fatalf("failed to create publisher: %v", err)
+	}
+	defer publisher.Close()
+
+	messages := []Message{
+		{ID: "transfer:0xabc:1:0x1", Key: "0x1", Value: []byte(`{"type":"transfer"}`)},
+		{ID: "transfer:0xabc:1:0x2", Key: "0x2", Value: []byte(`{"type":"transfer"}`)},
+	}
+	if err := publisher.Publish(context.Background(), messages); err != nil {
+		t.Fatalf("failed to publish: %v", err)
+	}
+	// Publish returns after the acks, so the messages are stored already
+	if got := streamMessages(t, stream); got != 2 {
+		t.Fatalf("stream has %d messages, want 2", got)
+	}
+
+	msg, err := stream.GetLastMsgForSubject(context.Background(), "usdt.transfers.0x2")
+	if err != nil {
+		t.Fatalf("failed to get message: %v", err)
+	}
+	if string(msg.Data) != `{"type":"transfer"}` || msg.Header.Get(nats.MsgIdHdr) != "transfer:0xabc:1:0x2" {
+		t.Errorf("unexpected message %q with id %q", msg.Data, msg.Header.Get(nats.MsgIdHdr))
+	}
+
+	// a batch published again after a crash is deduplicated by the stream
+	if err := publisher.Publish(context.Background(), messages); err != nil {
+		t.Fatalf("failed to publish again: %v", err)
+	}
+	if got := streamMessages(t, stream); got != 2 {
+		t.Errorf("stream has %d messages after a redelivery, want 2", got)
+	}
+}
+
+func TestNatsPublisherFailsWithoutStream(t *testing.T) {
+	srv := runNatsServer(t)
+
+	publisher, err := NewNatsPublisher(srv.ClientURL(), "usdt.transfers")
+	if err != nil {
+		t.Fatalf("failed to create publisher: %v", err)
+	}
+	defer publisher.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// nothing stores the message, so the position must not move
+	err = publisher.Publish(ctx, []Message{{ID: "transfer:0xabc:1:0x1", Key: "0x1", Value: []byte(`{}`)}})
+	if err == nil {
+		t.Fatal("publishing without a stream succeeded")
+	}
+}
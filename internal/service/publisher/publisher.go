@@ -0,0 +1,36 @@
+package publisher
+
+import (
+	"context"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/config"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// Message is a single event sent to the broker. ID is stable across retries
+// so brokers supporting deduplication can drop redelivered messages.
+type Message struct {
+	ID    string
+	Key   string
+	Value []byte
+}
+
+// Publisher sends messages to a message broker, Publish returns only after
+// the broker acknowledged the whole batch
+type Publisher interface {
+	Publish(ctx context.Context, messages []Message) error
+	Close() error
+}
+
+// New connects to the broker selected by the config driver
+func New(cfg *config.Publisher) (Publisher, error) {
+	switch cfg.Driver {
+	case config.PublisherDriverNats:
+		return NewNatsPublisher(cfg.URL, cfg.Topic)
+	case config.PublisherDriverKafka:
+		return NewKafkaPublisher(cfg.Brokers, cfg.Topic), nil
+	default:
+		return nil, errors.From(errors.New("unsupported publisher driver"), logan.F{"driver": cfg.Driver})
+	}
+}
@@ -0,0 +1,214 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/config"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/broadcaster"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// Event is the value of every published message. Transfers involving two
+// addresses are published twice, once keyed by each of them.
+type Event struct {
+	Type     broadcaster.EventType `json:"type"`
+	Address  string                `json:"address"`
+	Transfer data.USDTTransfer     `json:"transfer"`
+}
+
+// Relay publishes committed transfers in (block, log index) order and stores
+// the position of the last acknowledged one, so a restart resumes right
+// after it and at most the last batch is published twice. Retractions of
+// published transfers are written to an outbox by the listener rollback and
+// published before any transfer following them.
+type Relay struct {
+	publisher Publisher
+	db        data.MasterQ
+	log       *logan.Entry
+	config    *config.Publisher
+
+	name   string
+	notify chan struct{}
+}
+
+// PositionName is the name the position of the publisher is stored under
+func PositionName(config *config.Publisher) string {
+	return fmt.Sprintf("%s:%s", config.Driver, config.Topic)
+}
+
+// NewRelay creates a new Relay instance
+func NewRelay(config *config.Publisher, publisher Publisher, db data.MasterQ, log *logan.Entry) *Relay {
+	return &Relay{
+		publisher: publisher,
+		db:        db,
+		log:       log.WithField("service", "publisher"),
+		config:    config,
+		name:      PositionName(config),
+		notify:    make(chan struct{}, 1),
+	}
+}
+
+// BlockCommitted wakes the relay up, it never blocks the listener
+func (r *Relay) BlockCommitted(uint64) {
+	r.wake()
+}
+
+// BlockReverted wakes the relay up to publish the retractions the rollback
+// enqueued
+func (r *Relay) BlockReverted(uint64, []data.USDTTransfer) {
+	r.wake()
+}
+
+func (r *Relay) wake() {
+	select {
+	case r.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Run publishes new transfers and retractions whenever a block is committed
+// or reverted or the poll period passes until the context is cancelled
+func (r *Relay) Run(ctx context.Context) error {
+	defer func() {
+		if err := r.publisher.Close(); err != nil {
+			r.log.WithError(err).Error("Failed to close publisher")
+		}
+	}()
+
+	ticker := time.NewTicker(r.config.PollPeriod)
+	defer ticker.Stop()
+
+	for {
+		if err := r.publishPending(ctx); err != nil {
+			r.log.WithError(err).Error("Failed to publish transfers")
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-r.notify:
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Relay) publishPending(ctx context.Context) error {
+	for {
+		more, err := r.publishBatch(ctx)
+		if err != nil || !more {
+			return err
+		}
+	}
+}
+
+// publishBatch publishes a batch of retractions or, once there are none, of
+// transfers following the position. The position stays locked until the
+// broker acknowledged the batch and the position moved, a rollback waits for
+// it and then retracts exactly what was published.
+func (r *Relay) publishBatch(ctx context.Context) (more bool, err error) {
+	err = r.db.Transaction(func(q data.MasterQ) error {
+		position, err := r.lockPosition(q)
+		if err != nil {
+			return err
+		}
+
+		retractions, err := q.PublisherRetraction().FilterByName(r.name).Limit(r.config.BatchSize).Select()
+		if err != nil {
+			return errors.Wrap(err, "failed to select retractions")
+		}
+		if len(retractions) > 0 {
+			transfers := make([]data.USDTTransfer, 0, len(retractions))
+			ids := make([]int64, 0, len(retractions))
+			for _, retraction := range retractions {
+				transfers = append(transfers, retraction.USDTTransfer)
+				ids = append(ids, retraction.ID)
+			}
+
+			if err := r.publish(ctx, broadcaster.EventRetraction, transfers); err != nil {
+				return err
+			}
+			more = true
+			return q.PublisherRetraction().Delete(ids...)
+		}
+
+		transfers, err := q.USDTTransfer().
+			FilterAfterCursor(position.BlockNumber, position.LogIndex).
+			OrderByCursor().
+			Limit(r.config.BatchSize).
+			Select()
+		if err != nil {
+			return errors.Wrap(err, "failed to select transfers")
+		}
+		if len(transfers) == 0 {
+			return nil
+		}
+
+		if err := r.publish(ctx, broadcaster.EventTransfer, transfers); err != nil {
+			return err
+		}
+
+		last := transfers[len(transfers)-1]
+		position.BlockNumber = last.BlockNumber
+		position.LogIndex = int64(last.LogIndex)
+		more = uint64(len(transfers)) == r.config.BatchSize
+		return errors.Wrap(q.PublisherPosition().Upsert(*position), "failed to save publisher position")
+	})
+	return more, err
+}
+
+// lockPosition locks the stored position, a publisher that never ran starts
+// right after the last processed block
+func (r *Relay) lockPosition(q data.MasterQ) (*data.PublisherPosition, error) {
+	position, err := q.PublisherPosition().Lock(r.name)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to lock publisher position")
+	}
+	if position != nil {
+		return position, nil
+	}
+
+	lastBlock, err := q.LastProcessedBlock().Get()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get last processed block")
+	}
+
+	position = &data.PublisherPosition{Name: r.name, BlockNumber: lastBlock + 1, LogIndex: -1}
+	if err := q.PublisherPosition().Upsert(*position); err != nil {
+		return nil, errors.Wrap(err, "failed to save publisher position")
+	}
+	return q.PublisherPosition().Lock(r.name)
+}
+
+func (r *Relay) publish(ctx context.Context, eventType broadcaster.EventType, transfers []data.USDTTransfer) error {
+	if len(transfers) == 0 {
+		return nil
+	}
+
+	messages := make([]Message, 0, 2*len(transfers))
+	for _, transfer := range transfers {
+		addresses := []string{transfer.FromAddress}
+		if transfer.ToAddress != transfer.FromAddress {
+			addresses = append(addresses, transfer.ToAddress)
+		}
+
+		for _, address := range addresses {
+			value, err := json.Marshal(Event{Type: eventType, Address: address, Transfer: transfer})
+			if err != nil {
+				return errors.Wrap(err, "failed to marshal event")
+			}
+
+			messages = append(messages, Message{
+				ID:    fmt.Sprintf("%s:%s:%d:%s", eventType, transfer.TransactionHash, transfer.LogIndex, address),
+				Key:   address,
+				Value: value,
+			})
+		}
+	}
+
+	return r.publisher.Publish(ctx, messages)
+}
@@ -0,0 +1,63 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Limiter keeps a token bucket per API key. A bucket starts full, every
+// request takes a token and tokens come back at the rate of the key.
+type Limiter struct {
+	mu      sync.Mutex
+	buckets map[int64]*bucket
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Quota is the state of a bucket after a request
+type Quota struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter is the time until the next token when the request is not
+	// allowed
+	RetryAfter time.Duration
+}
+
+func New() *Limiter {
+	return &Limiter{
+		buckets: make(map[int64]*bucket),
+	}
+}
+
+// Take takes a token from the bucket of the key, rate is in tokens per
+// second and burst is the bucket size
+func (l *Limiter) Take(key int64, rate float64, burst int) Quota {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(burst), last: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = math.Min(float64(burst), b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+
+	quota := Quota{Limit: burst}
+	if b.tokens < 1 {
+		quota.RetryAfter = time.Duration((1 - b.tokens) / rate * float64(time.Second))
+		return quota
+	}
+
+	b.tokens--
+	quota.Allowed = true
+	quota.Remaining = int(b.tokens)
+	return quota
+}
@@ -0,0 +1,175 @@
+package reconciler
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/contracts"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/config"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/metrics"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/listener"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// Reconciler compares balances derived by the listener with the contract
+// balanceOf at the same block and records the mismatches.
+//
+// The listener only sees events starting from the configured starting block
+// and seeds the balances with the on-chain balance right before it. Addresses
+// which haven't moved funds since are compared with that baseline.
+type Reconciler struct {
+	usdt   *contracts.ContractsCaller
+	db     data.MasterQ
+	log    *logan.Entry
+	config config.Config
+}
+
+// NewReconciler creates a new Reconciler instance
+func NewReconciler(config config.Config, db data.MasterQ, log *logan.Entry) (*Reconciler, error) {
+	client, err := metrics.DialEthClient(config.Ethereum().RPCURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to Ethereum client")
+	}
+	usdt, err := contracts.NewContractsCaller(common.HexToAddress(listener.USDTContractAddress), client)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to bind USDT contract")
+	}
+	return &Reconciler{
+		usdt:   usdt,
+		db:     db,
+		log:    log,
+		config: config,
+	}, nil
+}
+
+// Run reconciles configured or sampled addresses every period until the
+// context is cancelled
+func (r *Reconciler) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.config.Reconciliation().Period)
+	defer ticker.Stop()
+
+	for {
+		addresses, err := r.Addresses()
+		if err != nil {
+			r.log.WithError(err).Error("Failed to get addresses to reconcile")
+		} else if _, err := r.Reconcile(ctx, addresses); err != nil {
+			r.log.WithError(err).Error("Failed to reconcile balances")
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Reconcile checks the addresses at the last processed block and returns
+// the discrepancies found
+func (r *Reconciler) Reconcile(ctx context.Context, addresses []string) ([]data.BalanceDiscrepancy, error) {
+	blockNumber, err := r.db.LastProcessedBlock().Get()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get last processed block")
+	}
+
+	baselineBlock := r.config.Ethereum().StartingBlock - 1
+	if blockNumber <= baselineBlock {
+		return nil, nil
+	}
+
+	discrepancies := make([]data.BalanceDiscrepancy, 0)
+
+	for _, address := range addresses {
+		fields := logan.F{"address": address, "blockNumber": blockNumber}
+
+		indexed, err := data.BalanceAt(r.db, address, blockNumber)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get indexed balance", fields)
+		}
+
+		// the listener stores the baseline once the address moves funds
+		seeded, err := r.db.BalanceChange().FilterByAddress(address).FilterByBlockNumber(baselineBlock).Select()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get stored baseline balance", fields)
+		}
+		if len(seeded) == 0 {
+			baseline, err := r.balanceOf(ctx, address, baselineBlock)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to get baseline balance", fields)
+			}
+			indexed.Add(indexed, baseline)
+		}
+
+		onchain, err := r.balanceOf(ctx, address, blockNumber)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get on-chain balance", fields)
+		}
+
+		if indexed.Cmp(onchain) == 0 {
+			continue
+		}
+
+		discrepancy, err := r.db.BalanceDiscrepancy().Insert(data.BalanceDiscrepancy{
+			Address:        address,
+			BlockNumber:    blockNumber,
+			IndexedBalance: indexed.String(),
+			OnchainBalance: onchain.String(),
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to record balance discrepancy", fields)
+		}
+
+		r.log.WithFields(fields).WithFields(logan.F{
+			"indexedBalance": discrepancy.IndexedBalance,
+			"onchainBalance": discrepancy.OnchainBalance,
+		}).Warn("Balance discrepancy found")
+
+		discrepancies = append(discrepancies, *discrepancy)
+	}
+
+	r.log.WithFields(logan.F{
+		"blockNumber":   blockNumber,
+		"checked":       len(addresses),
+		"discrepancies": len(discrepancies),
+	}).Info("Balances reconciled")
+
+	return discrepancies, nil
+}
+
+// Addresses returns the configured addresses or a random sample of holders
+func (r *Reconciler) Addresses() ([]string, error) {
+	cfg := r.config.Reconciliation()
+	if len(cfg.Addresses) > 0 {
+		addresses := make([]string, 0, len(cfg.Addresses))
+		for _, address := range cfg.Addresses {
+			addresses = append(addresses, common.HexToAddress(address).Hex())
+		}
+		return addresses, nil
+	}
+	if cfg.SampleSize == 0 {
+		return nil, nil
+	}
+
+	balances, err := r.db.Balance().Sample(cfg.SampleSize).Select()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sample balances")
+	}
+
+	addresses := make([]string, 0, len(balances))
+	for _, balance := range balances {
+		addresses = append(addresses, balance.Address)
+	}
+	return addresses, nil
+}
+
+func (r *Reconciler) balanceOf(ctx context.Context, address string, blockNumber uint64) (*big.Int, error) {
+	return r.usdt.BalanceOf(&bind.CallOpts{
+		Context:     ctx,
+		BlockNumber: new(big.Int).SetUint64(blockNumber),
+	}, common.HexToAddress(address))
+}
@@ -0,0 +1,230 @@
+package recording
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/metrics"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// Recorder is the RPC client writing every response the listener reads to
+// a recording directory. Responses are appended as they come, so a reorg
+// leaves the orphaned blocks in the recording, the replay picks the last
+// header of every height.
+type Recorder struct {
+	*metrics.EthClient
+	dir string
+
+	mu      sync.Mutex
+	chainID *big.Int
+	headers *jsonlWriter
+	logs    *jsonlWriter
+	calls   *jsonlWriter
+}
+
+// NewRecorder creates the recording directory, files of a previous recording
+// are overwritten
+func NewRecorder(client *metrics.EthClient, dir string) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, errors.Wrap(err, "failed to create recording directory")
+	}
+	// the manifest marks a complete recording
+	if err := os.Remove(filepath.Join(dir, ManifestFile)); err != nil && !os.IsNotExist(err) {
+		return nil, errors.Wrap(err, "failed to remove previous manifest")
+	}
+
+	r := &Recorder{EthClient: client, dir: dir}
+
+	var err error
+	if r.headers, err = newJSONLWriter(filepath.Join(dir, HeadersFile)); err != nil {
+		return nil, err
+	}
+	if r.logs, err = newJSONLWriter(filepath.Join(dir, LogsFile)); err != nil {
+		return nil, err
+	}
+	if r.calls, err = newJSONLWriter(filepath.Join(dir, CallsFile)); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *Recorder) ChainID(ctx context.Context) (*big.Int, error) {
+	chainID, err := r.EthClient.ChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.chainID = chainID
+	return chainID, nil
+}
+
+func (r *Recorder) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	header, err := r.EthClient.HeaderByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	return header, r.write(r.headers, header)
+}
+
+func (r *Recorder) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	logs, err := r.EthClient.FilterLogs(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	for i := range logs {
+		if err := r.write(r.logs, &logs[i]); err != nil {
+			return nil, err
+		}
+	}
+	return logs, nil
+}
+
+func (r *Recorder) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	result, err := r.EthClient.CallContract(ctx, msg, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	params := contractCall{To: msg.To, Data: msg.Data, Block: blockArg(blockNumber)}
+	return result, r.writeCall("eth_call", params, hexBytes(result))
+}
+
+// CodeAt is called by contract bindings when a call returns nothing
+func (r *Recorder) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	code, err := r.EthClient.CodeAt(ctx, contract, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	params := contractCall{To: &contract, Block: blockArg(blockNumber)}
+	return code, r.writeCall("eth_getCode", params, hexBytes(code))
+}
+
+// BatchCallContext records the raw result of every element, the results
+// are decoded into the element results afterwards
+func (r *Recorder) BatchCallContext(ctx context.Context, batch []rpc.BatchElem) error {
+	raw := make([]json.RawMessage, len(batch))
+	recorded := make([]rpc.BatchElem, len(batch))
+	for i, elem := range batch {
+		recorded[i] = rpc.BatchElem{Method: elem.Method, Args: elem.Args, Result: &raw[i]}
+	}
+
+	if err := r.EthClient.BatchCallContext(ctx, recorded); err != nil {
+		return err
+	}
+
+	for i, elem := range recorded {
+		batch[i].Error = elem.Error
+		if elem.Error != nil {
+			continue
+		}
+		if err := json.Unmarshal(raw[i], batch[i].Result); err != nil {
+			batch[i].Error = errors.Wrap(err, "failed to unmarshal result")
+			continue
+		}
+		if err := r.writeCall(elem.Method, elem.Args, raw[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Finish writes the manifest of the recorded range and closes the files
+func (r *Recorder) Finish(fromBlock, toBlock uint64) error {
+	if err := r.Close(); err != nil {
+		return err
+	}
+	if r.chainID == nil {
+		return errors.New("chain ID was not recorded")
+	}
+
+	manifest, err := json.MarshalIndent(Manifest{
+		ChainID:   r.chainID.Uint64(),
+		FromBlock: fromBlock,
+		ToBlock:   toBlock,
+	}, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal manifest")
+	}
+
+	err = os.WriteFile(filepath.Join(r.dir, ManifestFile), manifest, 0o644)
+	return errors.Wrap(err, "failed to write manifest")
+}
+
+// Close flushes and closes the files without marking the recording complete
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, w := range []*jsonlWriter{r.headers, r.logs, r.calls} {
+		if err := w.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Recorder) writeCall(method string, params interface{}, result json.RawMessage) error {
+	rawParams, err := marshalParams(params)
+	if err != nil {
+		return err
+	}
+	return r.write(r.calls, Call{Method: method, Params: rawParams, Result: result})
+}
+
+func (r *Recorder) write(w *jsonlWriter, value interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return w.Write(value)
+}
+
+func hexBytes(b []byte) json.RawMessage {
+	raw, _ := json.Marshal(hexutil.Bytes(b))
+	return raw
+}
+
+type jsonlWriter struct {
+	file    *os.File
+	buf     *bufio.Writer
+	encoder *json.Encoder
+	closed  bool
+}
+
+func newJSONLWriter(path string) (*jsonlWriter, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create recording file", logan.F{"path": path})
+	}
+	buf := bufio.NewWriter(file)
+	return &jsonlWriter{file: file, buf: buf, encoder: json.NewEncoder(buf)}, nil
+}
+
+func (w *jsonlWriter) Write(value interface{}) error {
+	return errors.Wrap(w.encoder.Encode(value), "failed to write recording line", logan.F{"file": w.file.Name()})
+}
+
+func (w *jsonlWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	if err := w.buf.Flush(); err != nil {
+		w.file.Close()
+		return errors.Wrap(err, "failed to flush recording file", logan.F{"file": w.file.Name()})
+	}
+	return errors.Wrap(w.file.Close(), "failed to close recording file", logan.F{"file": w.file.Name()})
+}
@@ -0,0 +1,69 @@
+package recording
+
+import (
+	"encoding/json"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// Files of a recording directory. Headers and logs hold one types.Header
+// and types.Log per line, calls hold the rest of the RPC responses the
+// listener reads, e.g. eth_call. The manifest is written once the whole
+// range is recorded.
+const (
+	HeadersFile  = "headers.jsonl"
+	LogsFile     = "logs.jsonl"
+	CallsFile    = "calls.jsonl"
+	ManifestFile = "manifest.json"
+)
+
+// ErrNotRecorded is returned by the replay for data missing from the
+// recording
+var ErrNotRecorded = errors.New("not recorded")
+
+// Manifest describes a complete recording
+type Manifest struct {
+	ChainID   uint64 `json:"chain_id"`
+	FromBlock uint64 `json:"from_block"`
+	ToBlock   uint64 `json:"to_block"`
+}
+
+// Call is an RPC response, Params and Result are kept as sent and received
+type Call struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+}
+
+func (c Call) key() string {
+	return callKey(c.Method, c.Params)
+}
+
+func callKey(method string, params json.RawMessage) string {
+	return method + string(params)
+}
+
+// contractCall is the params of eth_call and eth_getCode
+type contractCall struct {
+	To    *common.Address `json:"to"`
+	Data  hexutil.Bytes   `json:"data,omitempty"`
+	Block string          `json:"block"`
+}
+
+func blockArg(blockNumber *big.Int) string {
+	if blockNumber == nil {
+		return "latest"
+	}
+	return hexutil.EncodeBig(blockNumber)
+}
+
+func marshalParams(params interface{}) (json.RawMessage, error) {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal call params")
+	}
+	return raw, nil
+}
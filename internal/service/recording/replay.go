@@ -0,0 +1,249 @@
+package recording
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// maxLineSize bounds a line of a recording file, headers are the largest
+const maxLineSize = 16 * 1024 * 1024
+
+var errNotSupported = errors.New("not supported by a recording")
+
+// Replay answers the calls of the listener from a recording. The recording
+// is loaded into memory, large ranges are recorded and replayed in parts.
+type Replay struct {
+	manifest Manifest
+	headers  map[uint64]*types.Header
+	// logs are keyed by block hash and ordered by index
+	logs  map[common.Hash][]types.Log
+	calls map[string]json.RawMessage
+}
+
+// Open loads a complete recording
+func Open(dir string) (*Replay, error) {
+	raw, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if os.IsNotExist(err) {
+		return nil, errors.From(errors.New("recording is incomplete, manifest is missing"), logan.F{"dir": dir})
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read manifest")
+	}
+
+	r := &Replay{
+		headers: make(map[uint64]*types.Header),
+		logs:    make(map[common.Hash][]types.Log),
+		calls:   make(map[string]json.RawMessage),
+	}
+	if err := json.Unmarshal(raw, &r.manifest); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal manifest")
+	}
+
+	// later headers of a height replace the ones orphaned by a reorg
+	err = readJSONL(filepath.Join(dir, HeadersFile), func(line []byte) error {
+		var header types.Header
+		if err := json.Unmarshal(line, &header); err != nil {
+			return err
+		}
+		r.headers[header.Number.Uint64()] = &header
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[common.Hash]map[uint]bool)
+	err = readJSONL(filepath.Join(dir, LogsFile), func(line []byte) error {
+		var log types.Log
+		if err := json.Unmarshal(line, &log); err != nil {
+			return err
+		}
+		// logs of a block are fetched again when a contract is upgraded in it
+		if seen[log.BlockHash] == nil {
+			seen[log.BlockHash] = make(map[uint]bool)
+		}
+		if !seen[log.BlockHash][log.Index] {
+			seen[log.BlockHash][log.Index] = true
+			r.logs[log.BlockHash] = append(r.logs[log.BlockHash], log)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, logs := range r.logs {
+		sort.Slice(logs, func(i, j int) bool {
+			return logs[i].Index < logs[j].Index
+		})
+	}
+
+	err = readJSONL(filepath.Join(dir, CallsFile), func(line []byte) error {
+		var call Call
+		if err := json.Unmarshal(line, &call); err != nil {
+			return err
+		}
+		r.calls[call.key()] = call.Result
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *Replay) Manifest() Manifest {
+	return r.manifest
+}
+
+func (r *Replay) ChainID(ctx context.Context) (*big.Int, error) {
+	return new(big.Int).SetUint64(r.manifest.ChainID), nil
+}
+
+// BlockNumber reports the last recorded block as the head
+func (r *Replay) BlockNumber(ctx context.Context) (uint64, error) {
+	return r.manifest.ToBlock, nil
+}
+
+func (r *Replay) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	if number == nil {
+		return nil, errors.Wrap(errNotSupported, "latest header")
+	}
+
+	header, ok := r.headers[number.Uint64()]
+	if !ok {
+		return nil, errors.Wrap(ErrNotRecorded, "header", logan.F{"blockNumber": number.Uint64()})
+	}
+	return types.CopyHeader(header), nil
+}
+
+// FilterLogs supports queries by block hash only, as made by the listener.
+// The block is known to be recorded by its header, a block without logs has
+// none in the recording.
+func (r *Replay) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	if query.BlockHash == nil {
+		return nil, errors.Wrap(errNotSupported, "logs of a block range")
+	}
+
+	addresses := make(map[common.Address]bool, len(query.Addresses))
+	for _, address := range query.Addresses {
+		addresses[address] = true
+	}
+
+	var logs []types.Log
+	for _, log := range r.logs[*query.BlockHash] {
+		if len(addresses) == 0 || addresses[log.Address] {
+			logs = append(logs, log)
+		}
+	}
+	return logs, nil
+}
+
+func (r *Replay) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return r.bytesCall("eth_call", contractCall{To: msg.To, Data: msg.Data, Block: blockArg(blockNumber)})
+}
+
+func (r *Replay) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return r.bytesCall("eth_getCode", contractCall{To: &contract, Block: blockArg(blockNumber)})
+}
+
+// BatchCallContext sets ErrNotRecorded as the error of elements missing
+// from the recording
+func (r *Replay) BatchCallContext(ctx context.Context, batch []rpc.BatchElem) error {
+	for i, elem := range batch {
+		result, err := r.call(elem.Method, elem.Args)
+		if err != nil {
+			batch[i].Error = err
+			continue
+		}
+		if err := json.Unmarshal(result, elem.Result); err != nil {
+			batch[i].Error = errors.Wrap(err, "failed to unmarshal result")
+		}
+	}
+	return nil
+}
+
+func (r *Replay) bytesCall(method string, params interface{}) ([]byte, error) {
+	result, err := r.call(method, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var b hexutil.Bytes
+	if err := json.Unmarshal(result, &b); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal result")
+	}
+	return b, nil
+}
+
+func (r *Replay) call(method string, params interface{}) (json.RawMessage, error) {
+	rawParams, err := marshalParams(params)
+	if err != nil {
+		return nil, err
+	}
+
+	result, ok := r.calls[callKey(method, rawParams)]
+	if !ok {
+		return nil, errors.Wrap(ErrNotRecorded, method, logan.F{"params": string(rawParams)})
+	}
+	return result, nil
+}
+
+func (r *Replay) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return nil, errNotSupported
+}
+
+func (r *Replay) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return 0, errNotSupported
+}
+
+func (r *Replay) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return nil, errNotSupported
+}
+
+func (r *Replay) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return nil, errNotSupported
+}
+
+func (r *Replay) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	return 0, errNotSupported
+}
+
+func (r *Replay) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	return errNotSupported
+}
+
+func (r *Replay) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	return nil, errNotSupported
+}
+
+// readJSONL calls fn for every line of the file
+func readJSONL(path string, fn func(line []byte) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return errors.Wrap(err, "failed to open recording file", logan.F{"path": path})
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	for line := 1; scanner.Scan(); line++ {
+		if err := fn(scanner.Bytes()); err != nil {
+			return errors.Wrap(err, "failed to decode recording line", logan.F{"path": path, "line": line})
+		}
+	}
+	return errors.Wrap(scanner.Err(), "failed to read recording file", logan.F{"path": path})
+}
@@ -0,0 +1,66 @@
+package requests
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"gitlab.com/distributed_lab/urlval"
+)
+
+type SetCheckpointRequest struct {
+	BlockNumber *uint64 `json:"block_number"`
+}
+
+func NewSetCheckpointRequest(r *http.Request) (SetCheckpointRequest, error) {
+	var request SetCheckpointRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return request, errors.Wrap(err, "failed to unmarshal")
+	}
+	if request.BlockNumber == nil {
+		return request, errors.New("block_number is required")
+	}
+	return request, nil
+}
+
+type CreateReingestionRequest struct {
+	FromBlock uint64 `json:"from_block"`
+	ToBlock   uint64 `json:"to_block"`
+}
+
+func NewCreateReingestionRequest(r *http.Request) (CreateReingestionRequest, error) {
+	var request CreateReingestionRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return request, errors.Wrap(err, "failed to unmarshal")
+	}
+	if request.FromBlock == 0 {
+		return request, errors.New("from_block must be greater than 0")
+	}
+	if request.ToBlock < request.FromBlock {
+		return request, errors.New("to_block must not be less than from_block")
+	}
+	return request, nil
+}
+
+type ListAuditLogRequest struct {
+	PageRequest
+	Action string `url:"action"`
+}
+
+func NewListAuditLogRequest(r *http.Request) (ListAuditLogRequest, error) {
+	var request ListAuditLogRequest
+
+	err := urlval.Decode(r.URL.Query(), &request)
+	if err != nil {
+		return request, errors.Wrap(err, "failed to decode query parameters")
+	}
+
+	if request.Page == 0 {
+		request.Page = 1
+	}
+	if request.PerPage == 0 {
+		request.PerPage = 20
+	}
+
+	return request, validatePageRequest(request.PageRequest)
+}
@@ -0,0 +1,125 @@
+package requests
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"gitlab.com/distributed_lab/urlval"
+)
+
+type CreateAlertRuleRequest struct {
+	Name          string   `json:"name"`
+	Kind          string   `json:"kind"`
+	MinAmount     *string  `json:"min_amount"`
+	Addresses     []string `json:"addresses"`
+	TransferCount *int64   `json:"transfer_count"`
+	// TimeWindow is the velocity window in seconds
+	TimeWindow *int64   `json:"time_window"`
+	Notifiers  []string `json:"notifiers"`
+}
+
+func NewCreateAlertRuleRequest(r *http.Request) (CreateAlertRuleRequest, error) {
+	var request CreateAlertRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return request, errors.Wrap(err, "failed to unmarshal")
+	}
+
+	if len(request.Notifiers) == 0 {
+		request.Notifiers = []string{data.AlertNotifierLog}
+	}
+
+	return request, validateCreateAlertRuleRequest(&request)
+}
+
+func validateCreateAlertRuleRequest(request *CreateAlertRuleRequest) error {
+	if request.Name == "" {
+		return errors.New("name is required")
+	}
+
+	for i, address := range request.Addresses {
+		if !common.IsHexAddress(address) {
+			return errors.Errorf("invalid address format: %s", address)
+		}
+		request.Addresses[i] = common.HexToAddress(address).Hex()
+	}
+
+	if request.MinAmount != nil && parseAmount(*request.MinAmount) == nil {
+		return errors.New("min_amount must be a non-negative integer")
+	}
+
+	switch request.Kind {
+	case data.AlertRuleAmount:
+		if request.MinAmount == nil {
+			return errors.New("min_amount is required for amount rules")
+		}
+	case data.AlertRuleAddress:
+		if len(request.Addresses) == 0 {
+			return errors.New("addresses are required for address rules")
+		}
+	case data.AlertRuleVelocity:
+		if request.TransferCount == nil || *request.TransferCount < 1 {
+			return errors.New("transfer_count must be greater than 0 for velocity rules")
+		}
+		if request.TimeWindow == nil || *request.TimeWindow < 1 {
+			return errors.New("time_window must be greater than 0 for velocity rules")
+		}
+	default:
+		return errors.New("kind must be one of amount, address, velocity")
+	}
+
+	if request.Kind != data.AlertRuleVelocity && (request.TransferCount != nil || request.TimeWindow != nil) {
+		return errors.New("transfer_count and time_window are only allowed for velocity rules")
+	}
+
+	for _, notifier := range request.Notifiers {
+		if notifier != data.AlertNotifierLog && notifier != data.AlertNotifierWebhook {
+			return errors.Errorf("unknown notifier: %s", notifier)
+		}
+	}
+	return nil
+}
+
+type ListAlertsRequest struct {
+	PageRequest
+	RuleID  int64  `url:"rule_id"`
+	Address string `url:"address"`
+}
+
+func NewListAlertsRequest(r *http.Request) (ListAlertsRequest, error) {
+	var request ListAlertsRequest
+
+	err := urlval.Decode(r.URL.Query(), &request)
+	if err != nil {
+		return request, errors.Wrap(err, "failed to decode query parameters")
+	}
+
+	if request.Page == 0 {
+		request.Page = 1
+	}
+	if request.PerPage == 0 {
+		request.PerPage = 20
+	}
+
+	if err := validateListAlertsRequest(request); err != nil {
+		return request, err
+	}
+
+	if request.Address != "" {
+		request.Address = common.HexToAddress(request.Address).Hex()
+	}
+
+	return request, nil
+}
+
+func validateListAlertsRequest(request ListAlertsRequest) error {
+	if err := validatePageRequest(request.PageRequest); err != nil {
+		return err
+	}
+	if request.Address != "" && !common.IsHexAddress(request.Address) {
+		return errors.New("invalid address format")
+	}
+	return nil
+}
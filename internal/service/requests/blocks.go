@@ -0,0 +1,65 @@
+package requests
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/pkg/errors"
+	"gitlab.com/distributed_lab/urlval"
+)
+
+type ListBlocksRequest struct {
+	PageRequest
+	RawFrom string `url:"from"`
+	RawTo   string `url:"to"`
+	From    *time.Time
+	To      *time.Time
+}
+
+func NewListBlocksRequest(r *http.Request) (ListBlocksRequest, error) {
+	var request ListBlocksRequest
+
+	err := urlval.Decode(r.URL.Query(), &request)
+	if err != nil {
+		return request, errors.Wrap(err, "failed to decode query parameters")
+	}
+
+	if request.Page == 0 {
+		request.Page = 1
+	}
+	if request.PerPage == 0 {
+		request.PerPage = 20
+	}
+
+	if err := validatePageRequest(request.PageRequest); err != nil {
+		return request, err
+	}
+
+	if request.RawFrom != "" {
+		from, err := parseTimestamp(request.RawFrom)
+		if err != nil {
+			return request, errors.Wrap(err, "invalid from")
+		}
+		request.From = &from
+	}
+	if request.RawTo != "" {
+		to, err := parseTimestamp(request.RawTo)
+		if err != nil {
+			return request, errors.Wrap(err, "invalid to")
+		}
+		request.To = &to
+	}
+
+	return request, nil
+}
+
+// BlockNumberParam parses a block number path parameter
+func BlockNumberParam(r *http.Request, name string) (uint64, error) {
+	number, err := strconv.ParseUint(chi.URLParam(r, name), 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid %s", name)
+	}
+	return number, nil
+}
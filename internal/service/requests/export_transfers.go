@@ -0,0 +1,64 @@
+package requests
+
+import (
+	"net/http"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/export"
+	"github.com/pkg/errors"
+	"gitlab.com/distributed_lab/urlval"
+)
+
+// ExportTransfersRequest takes the filters of the list endpoint, the export
+// is not paginated
+type ExportTransfersRequest struct {
+	TransferFilters
+	BlockRange
+	Watchlist int64  `url:"watchlist"`
+	Label     string `url:"label"`
+	Format    string `url:"format"`
+}
+
+func NewExportTransfersRequest(r *http.Request) (ExportTransfersRequest, error) {
+	var request ExportTransfersRequest
+
+	err := urlval.Decode(r.URL.Query(), &request)
+	if err != nil {
+		return request, errors.Wrap(err, "failed to decode query parameters")
+	}
+
+	return request, request.Validate()
+}
+
+// Validate sets defaults and validates the request, it is used by the export
+// command as well
+func (r *ExportTransfersRequest) Validate() error {
+	if r.Format == "" {
+		r.Format = export.FormatCSV
+	}
+	if r.Format != export.FormatCSV && r.Format != export.FormatNDJSON && r.Format != export.FormatParquet {
+		return errors.New("format must be one of csv, ndjson, parquet")
+	}
+	if r.Watchlist < 0 {
+		return errors.New("watchlist must be a valid id")
+	}
+	if err := r.BlockRange.validate(); err != nil {
+		return err
+	}
+	return r.TransferFilters.normalize()
+}
+
+// Apply adds the filters to the transfers query
+func (r ExportTransfersRequest) Apply(q data.USDTTransferQ) data.USDTTransferQ {
+	q = r.TransferFilters.Apply(q)
+	if r.FromBlock != 0 || r.ToBlock != 0 {
+		q = q.FilterByBlockRange(r.Bounds())
+	}
+	if r.Watchlist != 0 {
+		q = q.FilterByWatchlist(r.Watchlist, r.Direction)
+	}
+	if r.Label != "" {
+		q = q.FilterByLabel(r.Label, r.Direction)
+	}
+	return q
+}
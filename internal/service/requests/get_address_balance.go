@@ -0,0 +1,61 @@
+package requests
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-chi/chi"
+	"github.com/pkg/errors"
+)
+
+type GetAddressBalanceRequest struct {
+	Address string
+	// Block is the block the balance is requested at, nil if not set
+	Block *uint64
+	// At is the moment the balance is requested at, nil if not set
+	At *time.Time
+}
+
+func NewGetAddressBalanceRequest(r *http.Request) (GetAddressBalanceRequest, error) {
+	var request GetAddressBalanceRequest
+
+	address := chi.URLParam(r, "address")
+	if !common.IsHexAddress(address) {
+		return request, errors.New("invalid address format")
+	}
+	request.Address = common.HexToAddress(address).Hex()
+
+	query := r.URL.Query()
+
+	if raw := query.Get("block"); raw != "" {
+		block, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return request, errors.Wrap(err, "invalid block")
+		}
+		request.Block = &block
+	}
+
+	if raw := query.Get("at"); raw != "" {
+		at, err := parseTimestamp(raw)
+		if err != nil {
+			return request, errors.Wrap(err, "invalid at")
+		}
+		request.At = &at
+	}
+
+	if request.Block != nil && request.At != nil {
+		return request, errors.New("only one of block and at can be set")
+	}
+
+	return request, nil
+}
+
+// parseTimestamp accepts either unix seconds or an RFC 3339 date
+func parseTimestamp(raw string) (time.Time, error) {
+	if seconds, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		return time.Unix(seconds, 0).UTC(), nil
+	}
+	return time.Parse(time.RFC3339, raw)
+}
@@ -0,0 +1,59 @@
+package requests
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// defaultReportPeriod is the report range when from is omitted
+const defaultReportPeriod = 30 * 24 * time.Hour
+
+type GetScreeningReportRequest struct {
+	From time.Time
+	To   time.Time
+	// Limit is the number of flagged addresses listed
+	Limit uint64
+}
+
+func NewGetScreeningReportRequest(r *http.Request) (GetScreeningReportRequest, error) {
+	query := r.URL.Query()
+
+	request := GetScreeningReportRequest{
+		To:    time.Now().UTC(),
+		Limit: 100,
+	}
+
+	if raw := query.Get("to"); raw != "" {
+		to, err := parseTimestamp(raw)
+		if err != nil {
+			return request, errors.Wrap(err, "invalid to")
+		}
+		request.To = to
+	}
+
+	request.From = request.To.Add(-defaultReportPeriod)
+	if raw := query.Get("from"); raw != "" {
+		from, err := parseTimestamp(raw)
+		if err != nil {
+			return request, errors.Wrap(err, "invalid from")
+		}
+		request.From = from
+	}
+
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil || limit < 1 || limit > 1000 {
+			return request, errors.New("limit must be between 1 and 1000")
+		}
+		request.Limit = limit
+	}
+
+	if !request.From.Before(request.To) {
+		return request, errors.New("from must be before to")
+	}
+
+	return request, nil
+}
@@ -0,0 +1,66 @@
+package requests
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/pkg/errors"
+)
+
+// defaultStatsBuckets is the number of buckets returned when from is omitted
+const defaultStatsBuckets = 30
+
+var granularityDurations = map[string]time.Duration{
+	data.GranularityHour: time.Hour,
+	data.GranularityDay:  24 * time.Hour,
+	data.GranularityWeek: 7 * 24 * time.Hour,
+}
+
+// IntervalRangeRequest is the bucketed time range shared by the time series
+// endpoints
+type IntervalRangeRequest struct {
+	Interval string
+	From     time.Time
+	To       time.Time
+}
+
+func NewIntervalRangeRequest(r *http.Request) (IntervalRangeRequest, error) {
+	query := r.URL.Query()
+
+	request := IntervalRangeRequest{
+		Interval: query.Get("interval"),
+		To:       time.Now().UTC(),
+	}
+	if request.Interval == "" {
+		request.Interval = data.GranularityDay
+	}
+
+	duration, ok := granularityDurations[request.Interval]
+	if !ok {
+		return request, errors.New("interval must be one of hour, day, week")
+	}
+
+	if raw := query.Get("to"); raw != "" {
+		to, err := parseTimestamp(raw)
+		if err != nil {
+			return request, errors.Wrap(err, "invalid to")
+		}
+		request.To = to
+	}
+
+	request.From = request.To.Add(-defaultStatsBuckets * duration)
+	if raw := query.Get("from"); raw != "" {
+		from, err := parseTimestamp(raw)
+		if err != nil {
+			return request, errors.Wrap(err, "invalid from")
+		}
+		request.From = from
+	}
+
+	if !request.From.Before(request.To) {
+		return request, errors.New("from must be before to")
+	}
+
+	return request, nil
+}
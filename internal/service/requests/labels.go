@@ -0,0 +1,140 @@
+package requests
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-chi/chi"
+	"github.com/pkg/errors"
+	"gitlab.com/distributed_lab/urlval"
+)
+
+// maxLabelLength keeps labels short enough to be shown next to addresses
+const maxLabelLength = 128
+
+type SetAddressLabelRequest struct {
+	Address string `json:"-"`
+	Label   string `json:"label"`
+}
+
+func NewSetAddressLabelRequest(r *http.Request) (SetAddressLabelRequest, error) {
+	var request SetAddressLabelRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return request, errors.Wrap(err, "failed to unmarshal")
+	}
+
+	address, err := AddressParam(r, "address")
+	if err != nil {
+		return request, err
+	}
+	request.Address = address
+
+	request.Label = strings.TrimSpace(request.Label)
+	return request, validateLabel(request.Label)
+}
+
+func validateLabel(label string) error {
+	if label == "" {
+		return errors.New("label is required")
+	}
+	if len(label) > maxLabelLength {
+		return errors.Errorf("label must be at most %d characters long", maxLabelLength)
+	}
+	return nil
+}
+
+type ListAddressLabelsRequest struct {
+	PageRequest
+	Label string `url:"label"`
+}
+
+func NewListAddressLabelsRequest(r *http.Request) (ListAddressLabelsRequest, error) {
+	var request ListAddressLabelsRequest
+
+	err := urlval.Decode(r.URL.Query(), &request)
+	if err != nil {
+		return request, errors.Wrap(err, "failed to decode query parameters")
+	}
+
+	if request.Page == 0 {
+		request.Page = 1
+	}
+	if request.PerPage == 0 {
+		request.PerPage = 20
+	}
+
+	return request, validatePageRequest(request.PageRequest)
+}
+
+// NewImportAddressLabelsRequest reads "address,label" CSV rows from the body.
+// A header row is skipped, the last label of a repeated address wins.
+func NewImportAddressLabelsRequest(r *http.Request) ([]data.AddressLabel, error) {
+	rows, err := readCSV(r.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := make(map[string]int)
+	labels := make([]data.AddressLabel, 0, len(rows))
+
+	for i, row := range rows {
+		if len(row) != 2 {
+			return nil, errors.Errorf("row %d: expected address and label", i+1)
+		}
+		if !common.IsHexAddress(row[0]) {
+			return nil, errors.Errorf("row %d: invalid address format: %s", i+1, row[0])
+		}
+
+		label := data.AddressLabel{
+			Address: common.HexToAddress(row[0]).Hex(),
+			Label:   strings.TrimSpace(row[1]),
+		}
+		if err := validateLabel(label.Label); err != nil {
+			return nil, errors.Wrapf(err, "row %d", i+1)
+		}
+
+		if index, ok := indexes[label.Address]; ok {
+			labels[index] = label
+			continue
+		}
+		indexes[label.Address] = len(labels)
+		labels = append(labels, label)
+	}
+
+	if len(labels) == 0 {
+		return nil, errors.New("no labels to import")
+	}
+	return labels, nil
+}
+
+// readCSV returns the records of a CSV body without the header row, which is
+// recognized by its first column being "address"
+func readCSV(body io.Reader) ([][]string, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse csv")
+	}
+
+	if len(rows) > 0 && len(rows[0]) > 0 && strings.EqualFold(strings.TrimSpace(rows[0][0]), "address") {
+		rows = rows[1:]
+	}
+	return rows, nil
+}
+
+// AddressParam parses an address from the url path into its checksummed form
+func AddressParam(r *http.Request, name string) (string, error) {
+	address := chi.URLParam(r, name)
+	if !common.IsHexAddress(address) {
+		return "", errors.New("invalid address format")
+	}
+	return common.HexToAddress(address).Hex(), nil
+}
@@ -0,0 +1,64 @@
+package requests
+
+import (
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"gitlab.com/distributed_lab/kit/pgdb"
+	"gitlab.com/distributed_lab/urlval"
+)
+
+type ListDiscrepanciesRequest struct {
+	Page    int    `url:"page"`
+	PerPage int    `url:"per_page"`
+	Address string `url:"address"`
+}
+
+func NewListDiscrepanciesRequest(r *http.Request) (ListDiscrepanciesRequest, error) {
+	var request ListDiscrepanciesRequest
+
+	err := urlval.Decode(r.URL.Query(), &request)
+	if err != nil {
+		return request, errors.Wrap(err, "failed to decode query parameters")
+	}
+
+	if request.Page == 0 {
+		request.Page = 1
+	}
+	if request.PerPage == 0 {
+		request.PerPage = 20
+	}
+
+	if err := validateListDiscrepanciesRequest(request); err != nil {
+		return request, err
+	}
+
+	if request.Address != "" {
+		request.Address = common.HexToAddress(request.Address).Hex()
+	}
+
+	return request, nil
+}
+
+func validateListDiscrepanciesRequest(request ListDiscrepanciesRequest) error {
+	if request.Page < 1 {
+		return errors.New("page must be greater than 0")
+	}
+	if request.PerPage < 1 || request.PerPage > 100 {
+		return errors.New("per_page must be between 1 and 100")
+	}
+	if request.Address != "" && !common.IsHexAddress(request.Address) {
+		return errors.New("invalid address format")
+	}
+	return nil
+}
+
+// GetPageParams returns params ordering the most recent discrepancies first
+func (r ListDiscrepanciesRequest) GetPageParams() pgdb.OffsetPageParams {
+	return pgdb.OffsetPageParams{
+		Limit:      uint64(r.PerPage),
+		Order:      pgdb.OrderTypeDesc,
+		PageNumber: uint64(r.Page - 1),
+	}
+}
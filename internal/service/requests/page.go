@@ -0,0 +1,52 @@
+package requests
+
+import (
+	"net/http"
+
+	"github.com/pkg/errors"
+	"gitlab.com/distributed_lab/kit/pgdb"
+	"gitlab.com/distributed_lab/urlval"
+)
+
+// PageRequest is a request of an endpoint accepting only page parameters
+type PageRequest struct {
+	Page    int `url:"page"`
+	PerPage int `url:"per_page"`
+}
+
+func NewPageRequest(r *http.Request) (PageRequest, error) {
+	var request PageRequest
+
+	err := urlval.Decode(r.URL.Query(), &request)
+	if err != nil {
+		return request, errors.Wrap(err, "failed to decode query parameters")
+	}
+
+	if request.Page == 0 {
+		request.Page = 1
+	}
+	if request.PerPage == 0 {
+		request.PerPage = 20
+	}
+
+	return request, validatePageRequest(request)
+}
+
+func validatePageRequest(request PageRequest) error {
+	if request.Page < 1 {
+		return errors.New("page must be greater than 0")
+	}
+	if request.PerPage < 1 || request.PerPage > 100 {
+		return errors.New("per_page must be between 1 and 100")
+	}
+	return nil
+}
+
+// GetPageParams returns params ordering rows in descending order
+func (r PageRequest) GetPageParams() pgdb.OffsetPageParams {
+	return pgdb.OffsetPageParams{
+		Limit:      uint64(r.PerPage),
+		Order:      pgdb.OrderTypeDesc,
+		PageNumber: uint64(r.Page - 1),
+	}
+}
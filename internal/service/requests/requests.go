@@ -51,9 +51,13 @@ func validateCreateUSDTTransferRequest(request CreateUSDTTransferRequest) error
 }
 
 type ListUSDTTransfersRequest struct {
+    TransferFilters
+    // Watchlist and Label keep transfers whose address on the filter
+    // direction side is in the watchlist or has the label
+    Watchlist int64  `url:"watchlist"`
+    Label     string `url:"label"`
     Page    int    `url:"page"`
     PerPage int    `url:"per_page"`
-    Address string `url:"address"`
     Limit   uint64
     PageNumber uint64
 }
@@ -76,20 +80,17 @@ func NewListUSDTTransfersRequest(r *http.Request) (ListUSDTTransfersRequest, err
     request.Limit = uint64(request.PerPage)
     request.PageNumber = uint64(request.Page)
 
-    return request, validateListUSDTTransfersRequest(request)
+    return request, validateListUSDTTransfersRequest(&request)
 }
 
-func validateListUSDTTransfersRequest(request ListUSDTTransfersRequest) error {
+func validateListUSDTTransfersRequest(request *ListUSDTTransfersRequest) error {
     if request.Page < 1 {
         return errors.New("page must be greater than 0")
     }
     if request.PerPage < 1 || request.PerPage > 100 {
         return errors.New("per_page must be between 1 and 100")
     }
-    if request.Address != "" && !common.IsHexAddress(request.Address) {
-        return errors.New("invalid address format")
-    }
-    return nil
+    return request.TransferFilters.normalize()
 }
 
 func (r ListUSDTTransfersRequest) GetPageParams() pgdb.OffsetPageParams {
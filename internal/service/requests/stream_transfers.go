@@ -0,0 +1,83 @@
+package requests
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/pkg/errors"
+	"gitlab.com/distributed_lab/urlval"
+)
+
+// Cursor is a position in the transfers stream. LogIndex -1 points right
+// before the first log of the block.
+type Cursor struct {
+	BlockNumber uint64
+	LogIndex    int64
+}
+
+func (c Cursor) String() string {
+	return strconv.FormatUint(c.BlockNumber, 10) + ":" + strconv.FormatInt(c.LogIndex, 10)
+}
+
+// ParseCursor parses a "block:log_index" cursor
+func ParseCursor(raw string) (Cursor, error) {
+	parts := strings.Split(raw, ":")
+	if len(parts) != 2 {
+		return Cursor{}, errors.New("cursor must be in block:log_index format")
+	}
+
+	blockNumber, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return Cursor{}, errors.Wrap(err, "invalid cursor block")
+	}
+	logIndex, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || logIndex < -1 {
+		return Cursor{}, errors.New("invalid cursor log index")
+	}
+
+	return Cursor{BlockNumber: blockNumber, LogIndex: logIndex}, nil
+}
+
+type StreamTransfersRequest struct {
+	TransferFilters
+	// LastEventID is the cursor to resume from, nil for live events only
+	LastEventID *Cursor
+}
+
+func NewStreamTransfersRequest(r *http.Request) (StreamTransfersRequest, error) {
+	var request StreamTransfersRequest
+
+	err := urlval.Decode(r.URL.Query(), &request)
+	if err != nil {
+		return request, errors.Wrap(err, "failed to decode query parameters")
+	}
+
+	if err := request.TransferFilters.normalize(); err != nil {
+		return request, err
+	}
+
+	if raw := r.Header.Get("Last-Event-ID"); raw != "" {
+		cursor, err := ParseCursor(raw)
+		if err != nil {
+			return request, errors.Wrap(err, "invalid Last-Event-ID")
+		}
+		request.LastEventID = &cursor
+	}
+
+	return request, nil
+}
+
+// CursorOf returns the cursor pointing at the transfer
+func CursorOf(transfer data.USDTTransfer) Cursor {
+	return Cursor{BlockNumber: transfer.BlockNumber, LogIndex: int64(transfer.LogIndex)}
+}
+
+// Less reports whether the cursor points before the other one
+func (c Cursor) Less(other Cursor) bool {
+	if c.BlockNumber != other.BlockNumber {
+		return c.BlockNumber < other.BlockNumber
+	}
+	return c.LogIndex < other.LogIndex
+}
@@ -0,0 +1,31 @@
+package requests
+
+import (
+	"net/http"
+
+	"github.com/pkg/errors"
+	"gitlab.com/distributed_lab/urlval"
+)
+
+type ListSupplyCheckpointsRequest struct {
+	PageRequest
+	Mismatched bool `url:"mismatched"`
+}
+
+func NewListSupplyCheckpointsRequest(r *http.Request) (ListSupplyCheckpointsRequest, error) {
+	var request ListSupplyCheckpointsRequest
+
+	err := urlval.Decode(r.URL.Query(), &request)
+	if err != nil {
+		return request, errors.Wrap(err, "failed to decode query parameters")
+	}
+
+	if request.Page == 0 {
+		request.Page = 1
+	}
+	if request.PerPage == 0 {
+		request.PerPage = 20
+	}
+
+	return request, validatePageRequest(request.PageRequest)
+}
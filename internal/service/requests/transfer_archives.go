@@ -0,0 +1,57 @@
+package requests
+
+import (
+	"math"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"gitlab.com/distributed_lab/urlval"
+)
+
+// BlockRange is an optional block range filter, zero leaves an end open
+type BlockRange struct {
+	FromBlock uint64 `url:"from_block"`
+	ToBlock   uint64 `url:"to_block"`
+}
+
+func (r BlockRange) validate() error {
+	if r.ToBlock != 0 && r.FromBlock > r.ToBlock {
+		return errors.New("from_block must not be greater than to_block")
+	}
+	return nil
+}
+
+// Bounds returns the range with the open ends closed, the upper bound fits
+// the bigint column
+func (r BlockRange) Bounds() (uint64, uint64) {
+	if r.ToBlock == 0 {
+		return r.FromBlock, math.MaxInt64
+	}
+	return r.FromBlock, r.ToBlock
+}
+
+type ListTransferArchivesRequest struct {
+	PageRequest
+	BlockRange
+}
+
+func NewListTransferArchivesRequest(r *http.Request) (ListTransferArchivesRequest, error) {
+	var request ListTransferArchivesRequest
+
+	err := urlval.Decode(r.URL.Query(), &request)
+	if err != nil {
+		return request, errors.Wrap(err, "failed to decode query parameters")
+	}
+
+	if request.Page == 0 {
+		request.Page = 1
+	}
+	if request.PerPage == 0 {
+		request.PerPage = 20
+	}
+
+	if err := validatePageRequest(request.PageRequest); err != nil {
+		return request, err
+	}
+	return request, request.BlockRange.validate()
+}
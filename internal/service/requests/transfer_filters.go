@@ -0,0 +1,116 @@
+package requests
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+// Directions of the address filter
+const (
+	DirectionFrom = data.DirectionFrom
+	DirectionTo   = data.DirectionTo
+	DirectionAny  = data.DirectionAny
+)
+
+// TransferFilters are the transfer filters shared by the list and streaming
+// endpoints. They can be applied to a query or matched against a single
+// transfer.
+type TransferFilters struct {
+	Address   string `url:"address" json:"address"`
+	Direction string `url:"direction" json:"direction"`
+	MinAmount string `url:"min_amount" json:"min_amount"`
+	MaxAmount string `url:"max_amount" json:"max_amount"`
+}
+
+// normalize sets defaults and validates the filters
+func (f *TransferFilters) normalize() error {
+	if f.Direction == "" {
+		f.Direction = DirectionFrom
+	}
+	if f.Direction != DirectionFrom && f.Direction != DirectionTo && f.Direction != DirectionAny {
+		return errors.New("direction must be one of from, to, any")
+	}
+
+	if f.Address != "" {
+		if !common.IsHexAddress(f.Address) {
+			return errors.New("invalid address format")
+		}
+		f.Address = common.HexToAddress(f.Address).Hex()
+	}
+
+	if f.MinAmount != "" && parseAmount(f.MinAmount) == nil {
+		return errors.New("min_amount must be a non-negative integer")
+	}
+	if f.MaxAmount != "" && parseAmount(f.MaxAmount) == nil {
+		return errors.New("max_amount must be a non-negative integer")
+	}
+	return nil
+}
+
+// Apply adds the filters to the transfers query
+func (f TransferFilters) Apply(q data.USDTTransferQ) data.USDTTransferQ {
+	if f.Address != "" {
+		switch f.Direction {
+		case DirectionFrom:
+			q = q.FilterByFromAddress(f.Address)
+		case DirectionTo:
+			q = q.FilterByToAddress(f.Address)
+		default:
+			q = q.FilterByAddress(f.Address)
+		}
+	}
+	if f.MinAmount != "" {
+		q = q.FilterByMinAmount(f.MinAmount)
+	}
+	if f.MaxAmount != "" {
+		q = q.FilterByMaxAmount(f.MaxAmount)
+	}
+	return q
+}
+
+// Matches reports whether the transfer passes the filters
+func (f TransferFilters) Matches(transfer data.USDTTransfer) bool {
+	if f.Address != "" {
+		from := strings.EqualFold(transfer.FromAddress, f.Address)
+		to := strings.EqualFold(transfer.ToAddress, f.Address)
+
+		switch f.Direction {
+		case DirectionFrom:
+			if !from {
+				return false
+			}
+		case DirectionTo:
+			if !to {
+				return false
+			}
+		default:
+			if !from && !to {
+				return false
+			}
+		}
+	}
+
+	amount := parseAmount(transfer.Amount)
+	if amount == nil {
+		return false
+	}
+	if f.MinAmount != "" && amount.Cmp(parseAmount(f.MinAmount)) < 0 {
+		return false
+	}
+	if f.MaxAmount != "" && amount.Cmp(parseAmount(f.MaxAmount)) > 0 {
+		return false
+	}
+	return true
+}
+
+func parseAmount(raw string) *big.Int {
+	amount, ok := new(big.Int).SetString(raw, 10)
+	if !ok || amount.Sign() < 0 {
+		return nil
+	}
+	return amount
+}
@@ -0,0 +1,131 @@
+package requests
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"gitlab.com/distributed_lab/urlval"
+)
+
+type CreateWatchlistRequest struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Addresses   []string `json:"addresses"`
+}
+
+func NewCreateWatchlistRequest(r *http.Request) (CreateWatchlistRequest, error) {
+	var request CreateWatchlistRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return request, errors.Wrap(err, "failed to unmarshal")
+	}
+	return request, validateCreateWatchlistRequest(&request)
+}
+
+func validateCreateWatchlistRequest(request *CreateWatchlistRequest) error {
+	request.Name = strings.TrimSpace(request.Name)
+	if request.Name == "" {
+		return errors.New("name is required")
+	}
+	return normalizeAddresses(request.Addresses)
+}
+
+type AddWatchlistAddressesRequest struct {
+	WatchlistID int64    `json:"-"`
+	Addresses   []string `json:"addresses"`
+}
+
+func NewAddWatchlistAddressesRequest(r *http.Request) (AddWatchlistAddressesRequest, error) {
+	var request AddWatchlistAddressesRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return request, errors.Wrap(err, "failed to unmarshal")
+	}
+
+	id, err := IDParam(r, "id")
+	if err != nil {
+		return request, err
+	}
+	request.WatchlistID = id
+
+	if len(request.Addresses) == 0 {
+		return request, errors.New("addresses are required")
+	}
+	return request, normalizeAddresses(request.Addresses)
+}
+
+// NewImportWatchlistAddressesRequest reads addresses from the first column of
+// a CSV body, other columns and a header row are ignored
+func NewImportWatchlistAddressesRequest(r *http.Request) (AddWatchlistAddressesRequest, error) {
+	var request AddWatchlistAddressesRequest
+
+	id, err := IDParam(r, "id")
+	if err != nil {
+		return request, err
+	}
+	request.WatchlistID = id
+
+	rows, err := readCSV(r.Body)
+	if err != nil {
+		return request, err
+	}
+
+	seen := make(map[string]bool)
+	for i, row := range rows {
+		if len(row) == 0 || !common.IsHexAddress(row[0]) {
+			return request, errors.Errorf("row %d: invalid address format", i+1)
+		}
+
+		address := common.HexToAddress(row[0]).Hex()
+		if !seen[address] {
+			seen[address] = true
+			request.Addresses = append(request.Addresses, address)
+		}
+	}
+
+	if len(request.Addresses) == 0 {
+		return request, errors.New("no addresses to import")
+	}
+	return request, nil
+}
+
+type ListWatchlistAddressesRequest struct {
+	PageRequest
+	WatchlistID int64
+}
+
+func NewListWatchlistAddressesRequest(r *http.Request) (ListWatchlistAddressesRequest, error) {
+	var request ListWatchlistAddressesRequest
+
+	err := urlval.Decode(r.URL.Query(), &request)
+	if err != nil {
+		return request, errors.Wrap(err, "failed to decode query parameters")
+	}
+
+	request.WatchlistID, err = IDParam(r, "id")
+	if err != nil {
+		return request, err
+	}
+
+	if request.Page == 0 {
+		request.Page = 1
+	}
+	if request.PerPage == 0 {
+		request.PerPage = 20
+	}
+
+	return request, validatePageRequest(request.PageRequest)
+}
+
+// normalizeAddresses validates the addresses and converts them to the
+// checksummed form in place
+func normalizeAddresses(addresses []string) error {
+	for i, address := range addresses {
+		if !common.IsHexAddress(address) {
+			return errors.Errorf("invalid address format: %s", address)
+		}
+		addresses[i] = common.HexToAddress(address).Hex()
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package requests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/go-chi/chi"
+	"github.com/pkg/errors"
+	"gitlab.com/distributed_lab/kit/pgdb"
+	"gitlab.com/distributed_lab/urlval"
+)
+
+type CreateWebhookRequest struct {
+	URL       string   `json:"url"`
+	Secret    string   `json:"secret"`
+	Addresses []string `json:"addresses"`
+	MinAmount *string  `json:"min_amount"`
+}
+
+func NewCreateWebhookRequest(r *http.Request) (CreateWebhookRequest, error) {
+	var request CreateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return request, errors.Wrap(err, "failed to unmarshal")
+	}
+	return request, validateCreateWebhookRequest(&request)
+}
+
+func validateCreateWebhookRequest(request *CreateWebhookRequest) error {
+	target, err := url.Parse(request.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return errors.New("url must be an absolute http(s) url")
+	}
+
+	for i, address := range request.Addresses {
+		if !common.IsHexAddress(address) {
+			return errors.Errorf("invalid address format: %s", address)
+		}
+		request.Addresses[i] = common.HexToAddress(address).Hex()
+	}
+
+	if request.MinAmount != nil && parseAmount(*request.MinAmount) == nil {
+		return errors.New("min_amount must be a non-negative integer")
+	}
+	return nil
+}
+
+type ListWebhookDeliveriesRequest struct {
+	WebhookID int64
+	Page      int    `url:"page"`
+	PerPage   int    `url:"per_page"`
+	Status    string `url:"status"`
+}
+
+func NewListWebhookDeliveriesRequest(r *http.Request) (ListWebhookDeliveriesRequest, error) {
+	var request ListWebhookDeliveriesRequest
+
+	err := urlval.Decode(r.URL.Query(), &request)
+	if err != nil {
+		return request, errors.Wrap(err, "failed to decode query parameters")
+	}
+
+	request.WebhookID, err = IDParam(r, "id")
+	if err != nil {
+		return request, err
+	}
+
+	if request.Page == 0 {
+		request.Page = 1
+	}
+	if request.PerPage == 0 {
+		request.PerPage = 20
+	}
+
+	return request, validateListWebhookDeliveriesRequest(request)
+}
+
+func validateListWebhookDeliveriesRequest(request ListWebhookDeliveriesRequest) error {
+	if request.Page < 1 {
+		return errors.New("page must be greater than 0")
+	}
+	if request.PerPage < 1 || request.PerPage > 100 {
+		return errors.New("per_page must be between 1 and 100")
+	}
+	switch request.Status {
+	case "", data.DeliveryStatusPending, data.DeliveryStatusDelivered, data.DeliveryStatusDead:
+		return nil
+	default:
+		return errors.New("status must be one of pending, delivered, dead")
+	}
+}
+
+// GetPageParams returns params ordering the most recent deliveries first
+func (r ListWebhookDeliveriesRequest) GetPageParams() pgdb.OffsetPageParams {
+	return pgdb.OffsetPageParams{
+		Limit:      uint64(r.PerPage),
+		Order:      pgdb.OrderTypeDesc,
+		PageNumber: uint64(r.Page - 1),
+	}
+}
+
+// IDParam parses a numeric id from the url path
+func IDParam(r *http.Request, name string) (int64, error) {
+	id, err := strconv.ParseInt(chi.URLParam(r, name), 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid %s", name)
+	}
+	return id, nil
+}
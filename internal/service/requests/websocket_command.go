@@ -0,0 +1,44 @@
+package requests
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// Actions a WebSocket client can send
+const (
+	ActionSubscribe   = "subscribe"
+	ActionUnsubscribe = "unsubscribe"
+)
+
+// WebSocketCommand is a client message managing the filter set of a
+// connection. Every filter is identified by a client chosen id.
+type WebSocketCommand struct {
+	Action string          `json:"action"`
+	ID     string          `json:"id"`
+	Filter TransferFilters `json:"filter"`
+}
+
+func NewWebSocketCommand(raw []byte) (WebSocketCommand, error) {
+	var command WebSocketCommand
+	if err := json.Unmarshal(raw, &command); err != nil {
+		return command, errors.Wrap(err, "failed to unmarshal")
+	}
+	return command, validateWebSocketCommand(&command)
+}
+
+func validateWebSocketCommand(command *WebSocketCommand) error {
+	if command.ID == "" {
+		return errors.New("id is required")
+	}
+
+	switch command.Action {
+	case ActionSubscribe:
+		return command.Filter.normalize()
+	case ActionUnsubscribe:
+		return nil
+	default:
+		return errors.New("action must be one of subscribe, unsubscribe")
+	}
+}
@@ -2,26 +2,114 @@ package service
 
 import (
 	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/config"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
 	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data/pg"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/metrics"
 	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/handlers"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/tracing"
 	"github.com/go-chi/chi"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"gitlab.com/distributed_lab/ape"
 )
 
-func (s *service) router(cfg config.Config) chi.Router {
+func (s *service) router(cfg config.Config, client *metrics.EthClient) chi.Router {
   r := chi.NewRouter()
 
   r.Use(
     ape.RecoverMiddleware(s.log),
+    handlers.StripQueryKey,
+    metrics.Middleware,
+    tracing.Middleware,
     ape.LoganMiddleware(s.log),
     ape.CtxMiddleware(
       handlers.CtxLog(s.log),
       handlers.CtxDB(pg.NewMasterQ(cfg.DB())),
+      handlers.CtxBroadcaster(s.events),
+      handlers.CtxStreaming(cfg.Streaming()),
+      handlers.CtxHealth(cfg.Health()),
+      handlers.CtxStatus(s.status),
+      handlers.CtxEthClient(client),
+      handlers.CtxAuth(cfg.Auth()),
+      handlers.CtxLimiter(s.limiter),
+      handlers.CtxRetention(cfg.Retention()),
+      handlers.CtxEthereum(cfg.Ethereum()),
     ),
+    handlers.CORS,
   )
+  r.Handle("/metrics", promhttp.Handler())
+  r.Get("/health/live", handlers.Live)
+  r.Get("/health/ready", handlers.Ready)
+  r.Get("/status", handlers.GetStatus)
   r.Route("/usdt-listener-svc", func(r chi.Router) {
-      r.Get("/", handlers.ListUSDTTransfers)
-      r.Get("/{id}", handlers.GetUSDTTransfer)
+      r.Group(func(r chi.Router) {
+          r.Use(handlers.Authenticate, handlers.RequireScope(data.ScopeRead))
+          r.Get("/", handlers.ListUSDTTransfers)
+          r.Get("/balances", handlers.ListBalances)
+          r.Get("/addresses/{address}/balance", handlers.GetAddressBalance)
+          r.Get("/discrepancies", handlers.ListDiscrepancies)
+          r.Get("/stats/volume", handlers.GetVolumeStats)
+          r.Get("/supply", handlers.GetSupply)
+          r.Get("/supply/checkpoints", handlers.ListSupplyCheckpoints)
+          r.Get("/fees/params", handlers.ListFeeParams)
+          r.Get("/contracts", handlers.ListUSDTContracts)
+          r.Get("/blocks", handlers.ListBlocks)
+          r.Get("/blocks/{number}", handlers.GetBlock)
+          r.Get("/webhooks", handlers.ListWebhooks)
+          r.Get("/webhooks/{id}", handlers.GetWebhook)
+          r.Get("/webhooks/{id}/deliveries", handlers.ListWebhookDeliveries)
+          r.Get("/alerts", handlers.ListAlerts)
+          r.Get("/alerts/rules", handlers.ListAlertRules)
+          r.Get("/alerts/rules/{id}", handlers.GetAlertRule)
+          r.Get("/labels", handlers.ListAddressLabels)
+          r.Get("/labels/{address}", handlers.GetAddressLabel)
+          r.Get("/screening/report", handlers.GetScreeningReport)
+          r.Get("/screening/lists", handlers.ListSanctionsLists)
+          r.Get("/watchlists", handlers.ListWatchlists)
+          r.Get("/watchlists/{id}", handlers.GetWatchlist)
+          r.Get("/watchlists/{id}/addresses", handlers.ListWatchlistAddresses)
+          r.Get("/transfers/export", handlers.ExportTransfers)
+          r.Get("/transfers/archives", handlers.ListTransferArchives)
+          r.Get("/transfers/archives/{id}", handlers.GetTransferArchive)
+          r.Get("/transfers/archives/{id}/file", handlers.DownloadTransferArchive)
+          r.Get("/{id}", handlers.GetUSDTTransfer)
+      })
+
+      r.Group(func(r chi.Router) {
+          r.Use(handlers.AuthenticateStream, handlers.RequireScope(data.ScopeStream))
+          r.Get("/transfers/stream", handlers.StreamTransfers)
+          r.Get("/transfers/ws", handlers.TransfersWebSocket)
+      })
+
+      r.Group(func(r chi.Router) {
+          r.Use(handlers.Authenticate, handlers.RequireScope(data.ScopeAdmin))
+          r.Post("/webhooks", handlers.CreateWebhook)
+          r.Delete("/webhooks/{id}", handlers.DeleteWebhook)
+          r.Post("/webhooks/deliveries/{delivery_id}/redeliver", handlers.RedeliverWebhookDelivery)
+          r.Post("/alerts/rules", handlers.CreateAlertRule)
+          r.Delete("/alerts/rules/{id}", handlers.DeleteAlertRule)
+          r.Post("/labels/import", handlers.ImportAddressLabels)
+          r.Put("/labels/{address}", handlers.SetAddressLabel)
+          r.Delete("/labels/{address}", handlers.DeleteAddressLabel)
+          r.Post("/watchlists", handlers.CreateWatchlist)
+          r.Delete("/watchlists/{id}", handlers.DeleteWatchlist)
+          r.Post("/watchlists/{id}/addresses", handlers.AddWatchlistAddresses)
+          r.Post("/watchlists/{id}/import", handlers.ImportWatchlistAddresses)
+          r.Delete("/watchlists/{id}/addresses/{address}", handlers.RemoveWatchlistAddress)
+
+          // Without keys anyone could control the listener
+          if !cfg.Auth().Disabled {
+              r.Route("/admin", func(r chi.Router) {
+                  r.Get("/checkpoint", handlers.GetCheckpoint)
+                  r.Put("/checkpoint", handlers.SetCheckpoint)
+                  r.Post("/pause", handlers.PauseListener)
+                  r.Post("/resume", handlers.ResumeListener)
+                  r.Post("/reingestions", handlers.CreateReingestion)
+                  r.Get("/reingestions", handlers.ListReingestions)
+                  r.Get("/reingestions/{id}", handlers.GetReingestion)
+                  r.Get("/audit", handlers.ListAuditLog)
+              })
+          }
+      })
   })
 
   return r
@@ -0,0 +1,87 @@
+package screening
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/contracts"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/config"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/metrics"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/service/listener"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// BlacklistSeeder records addresses blacklisted before the starting block.
+// The listener only sees AddedBlackList events from the starting block on,
+// earlier ones are recovered with isBlackListed calls right before it.
+type BlacklistSeeder struct {
+	usdt   *contracts.ContractsCaller
+	db     data.MasterQ
+	log    *logan.Entry
+	config config.Config
+}
+
+// NewBlacklistSeeder creates a new BlacklistSeeder instance
+func NewBlacklistSeeder(config config.Config, db data.MasterQ, log *logan.Entry) (*BlacklistSeeder, error) {
+	client, err := metrics.DialEthClient(config.Ethereum().RPCURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to Ethereum client")
+	}
+	usdt, err := contracts.NewContractsCaller(common.HexToAddress(listener.USDTContractAddress), client)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to bind USDT contract")
+	}
+	return &BlacklistSeeder{
+		usdt:   usdt,
+		db:     db,
+		log:    log,
+		config: config,
+	}, nil
+}
+
+// Seed checks the addresses at the block before the starting one, records
+// the blacklisted ones and rescreens their transfers. It returns the number
+// of blacklisted addresses found.
+func (s *BlacklistSeeder) Seed(ctx context.Context, addresses []string) (int, error) {
+	baselineBlock := s.config.Ethereum().StartingBlock - 1
+	opts := &bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(baselineBlock)}
+
+	var events []data.BlacklistEvent
+	var blacklisted []string
+
+	for i, address := range addresses {
+		listed, err := s.usdt.IsBlackListed(opts, common.HexToAddress(address))
+		if err != nil {
+			return 0, errors.Wrap(err, "failed to call isBlackListed", logan.F{"address": address})
+		}
+		if listed {
+			blacklisted = append(blacklisted, address)
+			events = append(events, data.BlacklistEvent{
+				Address:     address,
+				Blacklisted: true,
+				BlockNumber: baselineBlock,
+				LogIndex:    -1,
+			})
+		}
+
+		if (i+1)%1000 == 0 {
+			s.log.WithFields(logan.F{"checked": i + 1, "total": len(addresses)}).Info("Checking blacklist status")
+		}
+	}
+
+	err := s.db.Transaction(func(q data.MasterQ) error {
+		if err := q.BlacklistEvent().InsertBatch(events); err != nil {
+			return errors.Wrap(err, "failed to insert blacklist events")
+		}
+		return errors.Wrap(q.Screening().Rescreen(blacklisted), "failed to rescreen transfers")
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(blacklisted), nil
+}
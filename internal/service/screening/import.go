@@ -0,0 +1,60 @@
+package screening
+
+import (
+	"io"
+	"regexp"
+	"time"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"github.com/ethereum/go-ethereum/common"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// addressPattern finds Ethereum addresses anywhere in a list file, so plain
+// one-per-line lists and the OFAC SDN CSV ("Digital Currency Address - ETH
+// 0x...") are read the same way
+var addressPattern = regexp.MustCompile(`0x[0-9a-fA-F]{40}\b`)
+
+// ParseAddresses returns the unique addresses found in a sanctions list file
+func ParseAddresses(r io.Reader) ([]string, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read sanctions list")
+	}
+
+	seen := make(map[string]bool)
+	var addresses []string
+	for _, match := range addressPattern.FindAll(raw, -1) {
+		address := common.HexToAddress(string(match)).Hex()
+		if !seen[address] {
+			seen[address] = true
+			addresses = append(addresses, address)
+		}
+	}
+	return addresses, nil
+}
+
+// ImportList replaces the addresses of the named list and rescreens
+// transfers of every added or removed address in the same transaction. It
+// returns the number of changed addresses.
+func ImportList(db data.MasterQ, name, source string, addresses []string) (int, error) {
+	var changed []string
+
+	err := db.Transaction(func(q data.MasterQ) error {
+		var err error
+		changed, err = q.SanctionsList().Replace(data.SanctionsList{
+			Name:       name,
+			Source:     source,
+			ImportedAt: time.Now().UTC(),
+		}, addresses)
+		if err != nil {
+			return errors.Wrap(err, "failed to replace sanctions list")
+		}
+
+		return errors.Wrap(q.Screening().Rescreen(changed), "failed to rescreen transfers")
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(changed), nil
+}
@@ -0,0 +1,123 @@
+package status
+
+import (
+	"sync"
+	"time"
+)
+
+type State string
+
+const (
+	// StateStarting is reported until the listener learns the chain head
+	StateStarting State = "starting"
+	// StatePaused is reported while the listener is paused from the admin API
+	StatePaused State = "paused"
+	// StateBackfilling is reported while the listener is further behind the
+	// head than the allowed lag
+	StateBackfilling State = "backfilling"
+	// StateSyncing is reported while the listener follows the head
+	StateSyncing State = "syncing"
+)
+
+// Status is the sync progress reported by the listener, it is shared with
+// the HTTP handlers serving the health and status endpoints
+type Status struct {
+	mu      sync.RWMutex
+	started time.Time
+	maxLag  uint64
+
+	chainID            uint64
+	head               uint64
+	lastProcessedBlock uint64
+	paused             bool
+	lastError          *Error
+}
+
+type Error struct {
+	Message string    `json:"message"`
+	At      time.Time `json:"at"`
+}
+
+// Snapshot is a consistent copy of the status
+type Snapshot struct {
+	ChainID            uint64  `json:"chain_id"`
+	Head               uint64  `json:"head"`
+	LastProcessedBlock uint64  `json:"last_processed_block"`
+	Lag                uint64  `json:"lag"`
+	State              State   `json:"state"`
+	LastError          *Error  `json:"last_error"`
+	Uptime             float64 `json:"uptime_seconds"`
+}
+
+// New creates the status of a listener started now, maxLag is the number of
+// blocks it may be behind the head while syncing
+func New(maxLag uint64) *Status {
+	return &Status{
+		started: time.Now(),
+		maxLag:  maxLag,
+	}
+}
+
+func (s *Status) SetChainID(chainID uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.chainID = chainID
+}
+
+func (s *Status) SetHead(head uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.head = head
+}
+
+func (s *Status) SetLastProcessedBlock(blockNum uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastProcessedBlock = blockNum
+}
+
+func (s *Status) SetPaused(paused bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.paused = paused
+}
+
+// SetError records the last error of the listener, it is kept after the
+// listener recovers so the status page can show what went wrong
+func (s *Status) SetError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastError = &Error{
+		Message: err.Error(),
+		At:      time.Now().UTC(),
+	}
+}
+
+func (s *Status) Snapshot() Snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	snapshot := Snapshot{
+		ChainID:            s.chainID,
+		Head:               s.head,
+		LastProcessedBlock: s.lastProcessedBlock,
+		LastError:          s.lastError,
+		Uptime:             time.Since(s.started).Seconds(),
+	}
+	if s.head > s.lastProcessedBlock {
+		snapshot.Lag = s.head - s.lastProcessedBlock
+	}
+
+	switch {
+	case s.paused:
+		snapshot.State = StatePaused
+	case s.head == 0:
+		snapshot.State = StateStarting
+	case snapshot.Lag > s.maxLag:
+		snapshot.State = StateBackfilling
+	default:
+		snapshot.State = StateSyncing
+	}
+
+	return snapshot
+}
@@ -0,0 +1,179 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/config"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"gitlab.com/distributed_lab/logan/v3"
+	"gitlab.com/distributed_lab/logan/v3/errors"
+)
+
+// Headers set on every webhook call
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	DeliveryHeader  = "X-Webhook-Delivery"
+	TimestampHeader = "X-Webhook-Timestamp"
+)
+
+// Payload is the body of a webhook call
+type Payload struct {
+	DeliveryID int64           `json:"delivery_id"`
+	WebhookID  int64           `json:"webhook_id"`
+	Type       string          `json:"type"`
+	Transfer   json.RawMessage `json:"transfer"`
+}
+
+// Dispatcher delivers the webhook outbox written by the listener
+type Dispatcher struct {
+	client *http.Client
+	db     data.MasterQ
+	log    *logan.Entry
+	config *config.Webhooks
+}
+
+// NewDispatcher creates a new Dispatcher instance
+func NewDispatcher(config *config.Webhooks, db data.MasterQ, log *logan.Entry) *Dispatcher {
+	return &Dispatcher{
+		client: &http.Client{Timeout: config.Timeout},
+		db:     db,
+		log:    log,
+		config: config,
+	}
+}
+
+// Run delivers due outbox entries every poll period until the context is
+// cancelled
+func (d *Dispatcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(d.config.PollPeriod)
+	defer ticker.Stop()
+
+	for {
+		if err := d.dispatch(ctx); err != nil {
+			d.log.WithError(err).Error("Failed to dispatch webhooks")
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context) error {
+	deliveries, err := d.db.WebhookDelivery().FilterDue(time.Now().UTC()).OrderByID().Limit(d.config.BatchSize).Select()
+	if err != nil {
+		return errors.Wrap(err, "failed to select due deliveries")
+	}
+
+	webhooks := make(map[int64]*data.Webhook)
+
+	for _, delivery := range deliveries {
+		fields := logan.F{"deliveryID": delivery.ID, "webhookID": delivery.WebhookID}
+
+		webhook, ok := webhooks[delivery.WebhookID]
+		if !ok {
+			webhook, err = d.db.Webhook().FilterByID(delivery.WebhookID).Get()
+			if err != nil {
+				return errors.Wrap(err, "failed to get webhook", fields)
+			}
+			webhooks[delivery.WebhookID] = webhook
+		}
+		if webhook == nil {
+			// Deleted meanwhile, its deliveries are gone with it
+			continue
+		}
+
+		callErr := d.call(ctx, *webhook, delivery)
+		if callErr == nil {
+			if err := d.db.WebhookDelivery().MarkDelivered(delivery.ID); err != nil {
+				return errors.Wrap(err, "failed to mark delivery as delivered", fields)
+			}
+			continue
+		}
+
+		attempts := delivery.Attempts + 1
+		dead := attempts >= d.config.MaxAttempts
+		nextAttemptAt := time.Now().UTC().Add(d.backoff(attempts))
+
+		d.log.WithError(callErr).WithFields(fields).WithFields(logan.F{
+			"attempts": attempts,
+			"dead":     dead,
+		}).Warn("Webhook call failed")
+
+		if err := d.db.WebhookDelivery().MarkFailed(delivery.ID, callErr.Error(), nextAttemptAt, dead); err != nil {
+			return errors.Wrap(err, "failed to mark delivery as failed", fields)
+		}
+	}
+
+	return nil
+}
+
+// backoff returns the delay before the next attempt after the given number
+// of failed ones
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.config.MinBackoff
+	for i := 1; i < attempts && delay < d.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > d.config.MaxBackoff {
+		delay = d.config.MaxBackoff
+	}
+	return delay
+}
+
+func (d *Dispatcher) call(ctx context.Context, webhook data.Webhook, delivery data.WebhookDelivery) error {
+	body, err := json.Marshal(Payload{
+		DeliveryID: delivery.ID,
+		WebhookID:  webhook.ID,
+		Type:       delivery.EventType,
+		Transfer:   delivery.Payload,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal payload")
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, "sha256="+Sign(webhook.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to send request")
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of "timestamp.body" keyed with the
+// webhook secret
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,410 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/config"
+	"github.com/Dmytro-Hladkykh/usdt-listener-svc/internal/data"
+	"gitlab.com/distributed_lab/logan/v3"
+)
+
+// outbox is an in-memory webhook outbox behaving as the pg one
+type outbox struct {
+	data.MasterQ
+
+	mu         sync.Mutex
+	webhooks   map[int64]data.Webhook
+	deliveries []*data.WebhookDelivery
+}
+
+func (o *outbox) Webhook() data.WebhookQ {
+	return &webhookQ{outbox: o}
+}
+
+func (o *outbox) WebhookDelivery() data.WebhookDeliveryQ {
+	return &deliveryQ{outbox: o}
+}
+
+func (o *outbox) delivery(id int64) *data.WebhookDelivery {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, delivery := range o.deliveries {
+		if delivery.ID == id {
+			copied := *delivery
+			return &copied
+		}
+	}
+	return nil
+}
+
+// makeDue moves the next attempt of the delivery to the past
+func (o *outbox) makeDue(id int64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, delivery := range o.deliveries {
+		if delivery.ID == id {
+			delivery.NextAttemptAt = time.Now().UTC().Add(-time.Second)
+		}
+	}
+}
+
+type webhookQ struct {
+	data.WebhookQ
+	outbox *outbox
+	id     int64
+}
+
+func (q *webhookQ) FilterByID(ids ...int64) data.WebhookQ {
+	q.id = ids[0]
+	return q
+}
+
+func (q *webhookQ) Get() (*data.Webhook, error) {
+	q.outbox.mu.Lock()
+	defer q.outbox.mu.Unlock()
+	webhook, ok := q.outbox.webhooks[q.id]
+	if !ok {
+		return nil, nil
+	}
+	return &webhook, nil
+}
+
+type deliveryQ struct {
+	data.WebhookDeliveryQ
+	outbox *outbox
+	due    *time.Time
+}
+
+func (q *deliveryQ) FilterDue(now time.Time) data.WebhookDeliveryQ {
+	q.due = &now
+	return q
+}
+
+func (q *deliveryQ) OrderByID() data.WebhookDeliveryQ {
+	return q
+}
+
+func (q *deliveryQ) Limit(limit uint64) data.WebhookDeliveryQ {
+	return q
+}
+
+func (q *deliveryQ) Select() ([]data.WebhookDelivery, error) {
+	q.outbox.mu.Lock()
+	defer q.outbox.mu.Unlock()
+	var result []data.WebhookDelivery
+	for _, delivery := range q.outbox.deliveries {
+		if q.due != nil && (delivery.Status != data.DeliveryStatusPending || delivery.NextAttemptAt.After(*q.due)) {
+			continue
+		}
+		result = append(result, *delivery)
+	}
+	return result, nil
+}
+
+func (q *deliveryQ) update(id int64, fn func(delivery *data.WebhookDelivery)) {
+	q.outbox.mu.Lock()
+	defer q.outbox.mu.Unlock()
+	for _, delivery := range q.outbox.deliveries {
+		if delivery.ID == id {
+			fn(delivery)
+		}
+	}
+}
+
+func (q *deliveryQ) MarkDelivered(id int64) error {
+	q.update(id, func(delivery *data.WebhookDelivery) {
+		now := time.Now().UTC()
+		delivery.Status = data.DeliveryStatusDelivered
+		delivery.Attempts++
+		delivery.DeliveredAt = &now
+		delivery.LastError = nil
+	})
+	return nil
+}
+
+func (q *deliveryQ) MarkFailed(id int64, reason string, nextAttemptAt time.Time, dead bool) error {
+	q.update(id, func(delivery *data.WebhookDelivery) {
+		delivery.Status = data.DeliveryStatusPending
+		if dead {
+			delivery.Status = data.DeliveryStatusDead
+		}
+		delivery.Attempts++
+		delivery.NextAttemptAt = nextAttemptAt
+		delivery.LastError = &reason
+	})
+	return nil
+}
+
+func (q *deliveryQ) Redeliver(id int64) (*data.WebhookDelivery, error) {
+	redelivered := false
+	q.update(id, func(delivery *data.WebhookDelivery) {
+		if delivery.Status == data.DeliveryStatusDelivered {
+			return
+		}
+		delivery.Status = data.DeliveryStatusPending
+		delivery.Attempts = 0
+		delivery.NextAttemptAt = time.Now().UTC()
+		delivery.LastError = nil
+		redelivered = true
+	})
+	if !redelivered {
+		return nil, nil
+	}
+	return q.outbox.delivery(id), nil
+}
+
+type call struct {
+	header http.Header
+	body   []byte
+}
+
+// receiver is a webhook endpoint answering with the queued statuses, then
+// with 200
+type receiver struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	statuses []int
+	calls    []call
+}
+
+func newReceiver(t *testing.T, statuses ...int) *receiver {
+	r := &receiver{statuses: statuses}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			t.Errorf("failed to read body: %v", err)
+		}
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.calls = append(r.calls, call{header: req.Header.Clone(), body: body})
+		status := http.StatusOK
+		if len(r.statuses) > 0 {
+			status, r.statuses = r.statuses[0], r.statuses[1:]
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func (r *receiver) received() []call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]call(nil), r.calls...)
+}
+
+func testConfig() *config.Webhooks {
+	return &config.Webhooks{
+		PollPeriod:  time.Second,
+		Timeout:     time.Second,
+		BatchSize:   100,
+		MaxAttempts: 3,
+		MinBackoff:  time.Second,
+		MaxBackoff:  3 * time.Second,
+	}
+}
+
+func newOutbox(url string, deliveries ...*data.WebhookDelivery) *outbox {
+	return &outbox{
+		webhooks: map[int64]data.Webhook{
+			1: {ID: 1, URL: url, Secret: "secret"},
+		},
+		deliveries: deliveries,
+	}
+}
+
+func pendingDelivery(id int64, eventType string) *data.WebhookDelivery {
+	return &data.WebhookDelivery{
+		ID:            id,
+		WebhookID:     1,
+		EventType:     eventType,
+		Payload:       json.RawMessage(`{"id":7,"amount":"1000000"}`),
+		Status:        data.DeliveryStatusPending,
+		NextAttemptAt: time.Now().UTC().Add(-time.Second),
+	}
+}
+
+func TestDispatchSignsTimestampAndBody(t *testing.T) {
+	endpoint := newReceiver(t)
+	db := newOutbox(endpoint.URL, pendingDelivery(1, "transfer"))
+	dispatcher := NewDispatcher(testConfig(), db, logan.New())
+
+	if err := dispatcher.dispatch(context.Background()); err != nil {
+		t.Fatalf("dispatch failed: %v", err)
+	}
+
+	calls := endpoint.received()
+	if len(calls) != 1 {
+		t.Fatalf("expected 1 call, got %d", len(calls))
+	}
+	got := calls[0]
+
+	timestamp := got.header.Get(TimestampHeader)
+	if _, err := strconv.ParseInt(timestamp, 10, 64); err != nil {
+		t.Fatalf("invalid timestamp header %q", timestamp)
+	}
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte(timestamp + "." + string(got.body)))
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); got.header.Get(SignatureHeader) != want {
+		t.Errorf("signature %q, want %q", got.header.Get(SignatureHeader), want)
+	}
+	if got.header.Get(DeliveryHeader) != "1" {
+		t.Errorf("delivery header %q, want 1", got.header.Get(DeliveryHeader))
+	}
+
+	var payload Payload
+	if err := json.Unmarshal(got.body, &payload); err != nil {
+		t.Fatalf("invalid payload: %v", err)
+	}
+	if payload.DeliveryID != 1 || payload.WebhookID != 1 || payload.Type != "transfer" {
+		t.Errorf("unexpected payload %+v", payload)
+	}
+
+	if delivery := db.delivery(1); delivery.Status != data.DeliveryStatusDelivered || delivery.Attempts != 1 {
+		t.Errorf("delivery %s after %d attempts, want delivered after 1", delivery.Status, delivery.Attempts)
+	}
+}
+
+func TestSignDependsOnTimestamp(t *testing.T) {
+	body := []byte(`{"delivery_id":1}`)
+	if Sign("secret", "1700000000", body) == Sign("secret", "1700000001", body) {
+		t.Error("signatures of different timestamps match")
+	}
+	if Sign("secret", "1700000000", body) == Sign("other", "1700000000", body) {
+		t.Error("signatures of different secrets match")
+	}
+}
+
+func TestDispatchRetriesWithBackoff(t *testing.T) {
+	endpoint := newReceiver(t, http.StatusInternalServerError, http.StatusBadGateway)
+	db := newOutbox(endpoint.URL, pendingDelivery(1, "transfer"))
+	dispatcher := NewDispatcher(testConfig(), db, logan.New())
+
+	before := time.Now().UTC()
+	if err := dispatcher.dispatch(context.Background()); err != nil {
+		t.Fatalf("dispatch failed: %v", err)
+	}
+	delivery := db.delivery(1)
+	if delivery.Status != data.DeliveryStatusPending || delivery.Attempts != 1 || delivery.LastError == nil {
+		t.Fatalf("unexpected delivery after a failed call: %+v", delivery)
+	}
+	if delay := delivery.NextAttemptAt.Sub(before); delay < time.Second || delay > 2*time.Second {
+		t.Errorf("next attempt in %s, want min_backoff", delay)
+	}
+
+	// not due yet
+	if err := dispatcher.dispatch(context.Background()); err != nil {
+		t.Fatalf("dispatch failed: %v", err)
+	}
+	if len(endpoint.received()) != 1 {
+		t.Fatalf("delivery retried before its backoff")
+	}
+
+	db.makeDue(1)
+	if err := dispatcher.dispatch(context.Background()); err != nil {
+		t.Fatalf("dispatch failed: %v", err)
+	}
+	db.makeDue(1)
+	if err := dispatcher.dispatch(context.Background()); err != nil {
+		t.Fatalf("dispatch failed: %v", err)
+	}
+
+	if len(endpoint.received()) != 3 {
+		t.Fatalf("expected 3 calls, got %d", len(endpoint.received()))
+	}
+	if delivery := db.delivery(1); delivery.Status != data.DeliveryStatusDelivered || delivery.Attempts != 3 {
+		t.Errorf("delivery %s after %d attempts, want delivered after 3", delivery.Status, delivery.Attempts)
+	}
+}
+
+func TestDispatchMovesToDead(t *testing.T) {
+	endpoint := newReceiver(t, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError)
+	db := newOutbox(endpoint.URL, pendingDelivery(1, "transfer"))
+	dispatcher := NewDispatcher(testConfig(), db, logan.New())
+
+	for i := 0; i < 4; i++ {
+		db.makeDue(1)
+		if err := dispatcher.dispatch(context.Background()); err != nil {
+			t.Fatalf("dispatch failed: %v", err)
+		}
+	}
+
+	if len(endpoint.received()) != 3 {
+		t.Errorf("expected max_attempts calls, got %d", len(endpoint.received()))
+	}
+	if delivery := db.delivery(1); delivery.Status != data.DeliveryStatusDead || delivery.Attempts != 3 {
+		t.Errorf("delivery %s after %d attempts, want dead after 3", delivery.Status, delivery.Attempts)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	dispatcher := NewDispatcher(testConfig(), nil, logan.New())
+
+	for attempts, want := range map[int]time.Duration{
+		1: time.Second,
+		2: 2 * time.Second,
+		3: 3 * time.Second,
+		9: 3 * time.Second,
+	} {
+		if got := dispatcher.backoff(attempts); got != want {
+			t.Errorf("backoff after %d attempts is %s, want %s", attempts, got, want)
+		}
+	}
+}
+
+func TestRedeliverDeadDelivery(t *testing.T) {
+	endpoint := newReceiver(t)
+	dead := pendingDelivery(1, "retraction")
+	dead.Status = data.DeliveryStatusDead
+	dead.Attempts = 3
+	reason := "status 500"
+	dead.LastError = &reason
+	db := newOutbox(endpoint.URL, dead)
+	dispatcher := NewDispatcher(testConfig(), db, logan.New())
+
+	if err := dispatcher.dispatch(context.Background()); err != nil {
+		t.Fatalf("dispatch failed: %v", err)
+	}
+	if len(endpoint.received()) != 0 {
+		t.Fatalf("dead delivery dispatched")
+	}
+
+	redelivered, err := db.WebhookDelivery().Redeliver(1)
+	if err != nil {
+		t.Fatalf("redeliver failed: %v", err)
+	}
+	if redelivered == nil || redelivered.LastError != nil {
+		t.Fatalf("got redelivered %+v, want pending without last error", redelivered)
+	}
+	if err := dispatcher.dispatch(context.Background()); err != nil {
+		t.Fatalf("dispatch failed: %v", err)
+	}
+
+	calls := endpoint.received()
+	if len(calls) != 1 {
+		t.Fatalf("expected 1 call, got %d", len(calls))
+	}
+	var payload Payload
+	if err := json.Unmarshal(calls[0].body, &payload); err != nil {
+		t.Fatalf("invalid payload: %v", err)
+	}
+	if payload.Type != "retraction" {
+		t.Errorf("payload type %q, want retraction", payload.Type)
+	}
+	if delivery := db.delivery(1); delivery.Status != data.DeliveryStatusDelivered || delivery.Attempts != 1 {
+		t.Errorf("delivery %s after %d attempts, want delivered after 1", delivery.Status, delivery.Attempts)
+	}
+}
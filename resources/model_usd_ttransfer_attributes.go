@@ -0,0 +1,28 @@
+/*
+ * GENERATED. Do not modify. Your changes might be overwritten!
+ */
+
+package resources
+
+import "time"
+
+type UsdTtransferAttributes struct {
+	// Amount of USDT transferred
+	Amount string `json:"amount"`
+	// Block number where the transfer occurred
+	BlockNumber int64 `json:"block_number"`
+	// Fee charged by the contract on top of the amount
+	Fee string `json:"fee"`
+	// Compliance flags of the transfer parties
+	Flags Flags `json:"flags"`
+	// Ethereum address of the sender
+	FromAddress string `json:"from_address"`
+	// Index of the log in the block
+	LogIndex int64 `json:"log_index"`
+	// Timestamp of the transfer
+	Timestamp time.Time `json:"timestamp"`
+	// Ethereum address of the recipient
+	ToAddress string `json:"to_address"`
+	// Hash of the Ethereum transaction
+	TransactionHash string `json:"transaction_hash"`
+}